@@ -103,6 +103,12 @@ func Init(cfg *config.Config) (*OllamaModel, error) {
 
 // GenerateResponse sends a request to the Ollama /api/generate endpoint using plain HTTP.
 func (om *OllamaModel) GenerateResponse(userPrompt string) (string, error) {
+	return om.GenerateResponseWithSystem(context.Background(), om.systemPrompt, userPrompt)
+}
+
+// GenerateResponseWithSystem is GenerateResponse with its own system prompt
+// instead of the configured one, cancelled with ctx.
+func (om *OllamaModel) GenerateResponseWithSystem(ctx context.Context, systemPrompt string, userPrompt string) (string, error) {
 	// Construct the target URL for the /api/generate endpoint
 	targetURL, err := url.JoinPath(om.ollamaAddr, "/api/generate")
 	if err != nil {
@@ -113,7 +119,7 @@ func (om *OllamaModel) GenerateResponse(userPrompt string) (string, error) {
 	payload := OllamaRequestPayload{
 		Model: om.modelName,
 		Messages: []OllamaMessage{
-			{Role: "system", Content: om.systemPrompt},
+			{Role: "system", Content: systemPrompt},
 			{Role: "user", Content: userPrompt},
 		},
 		Stream: false, // For a single, non-streaming response
@@ -126,7 +132,7 @@ func (om *OllamaModel) GenerateResponse(userPrompt string) (string, error) {
 
 	// Create the HTTP request
 	req, err := http.NewRequestWithContext(
-		context.Background(), // Or a more specific context
+		ctx,
 		"POST",
 		targetURL,
 		bytes.NewBuffer(payloadBytes),
@@ -10,6 +10,9 @@ import (
 	"github.com/dukunuu/hackathon_backend/db"
 	_ "github.com/dukunuu/hackathon_backend/docs"
 	"github.com/dukunuu/hackathon_backend/file"
+	"github.com/dukunuu/hackathon_backend/jwtkeys"
+	"github.com/dukunuu/hackathon_backend/mail"
+	"github.com/dukunuu/hackathon_backend/oidc"
 	"github.com/dukunuu/hackathon_backend/server"
 )
 
@@ -39,10 +42,17 @@ func main() {
 		log.Fatal("Failed to load config")
 	}
 
+	// Checked before connecting to anything so a misconfigured deployment fails fast.
+	signingKeys, err := jwtkeys.Init(cfg)
+	if err!=nil {
+		log.Fatal("Failed to load JWT signing keys: ", err)
+	}
+
 	db, err := db.Init(cfg.DB_URL, &ctx)
 	if err!=nil {
 		log.Fatal("Failed to load config")
 	}
+	defer db.Close()
 
 	fileCfg := file.MinioConfig{
 		Endpoint: cfg.MINIO_ENDPOINT,
@@ -75,7 +85,18 @@ func main() {
     }
 }
 
-	srvr := server.Init(cfg.HOST, cfg.JWT_SECRET, db, store, aiModel)
+	mailer, err := mail.Init(cfg)
+	if err!=nil {
+		log.Fatal("Failed to load mail sender: ", err)
+	}
+
+	srvr := server.Init(cfg, db, store, aiModel, mailer, oidc.Init(cfg), signingKeys)
+
+	if cfg.BOOTSTRAP_ADMIN_EMAIL != "" {
+		if err := srvr.BootstrapAdmin(ctx, cfg.BOOTSTRAP_ADMIN_EMAIL); err != nil {
+			slog.Warn("Could not bootstrap admin user", "error", err)
+		}
+	}
 
 	log.Printf("Starting server on port: %s", cfg.HOST)
 	srvr.Start()
@@ -0,0 +1,275 @@
+// Command stubidp is a minimal OpenID Connect provider for trying out and
+// testing social login locally. It signs in whoever submits its login form,
+// so it must never be exposed outside a development machine.
+//
+//	go run ./cmd/stubidp -addr :9096 -client-id hackathon -client-secret stub-secret
+//
+// and configure the API with OIDC_PROVIDERS=stub and the matching
+// OIDC_STUB_ISSUER, OIDC_STUB_CLIENT_ID and OIDC_STUB_CLIENT_SECRET.
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"html/template"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const keyID = "stub-key"
+
+type authCode struct {
+	clientID      string
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+	claims        jwt.MapClaims
+	expiresAt     time.Time
+}
+
+type stubIdP struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	key          *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]authCode
+}
+
+var loginPage = template.Must(template.New("login").Parse(`<!doctype html>
+<html><head><title>Stub IdP</title></head>
+<body>
+<h1>Stub identity provider</h1>
+<form method="post">
+{{range $k, $v := .Params}}<input type="hidden" name="{{$k}}" value="{{$v}}">
+{{end}}<p><label>Email <input name="email" value="resident@example.com"></label></p>
+<p><label>Given name <input name="given_name" value="Test"></label></p>
+<p><label>Family name <input name="family_name" value="Resident"></label></p>
+<p><label><input type="checkbox" name="email_verified" value="true" checked> Email verified</label></p>
+<p><button type="submit">Sign in</button></p>
+</form>
+</body></html>
+`))
+
+func main() {
+	addr := flag.String("addr", ":9096", "listen address")
+	issuer := flag.String("issuer", "http://localhost:9096", "issuer URL as seen by the API and the browser")
+	clientID := flag.String("client-id", "hackathon", "accepted client ID")
+	clientSecret := flag.String("client-secret", "stub-secret", "accepted client secret")
+	flag.Parse()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatalf("Failed to generate signing key: %v", err)
+	}
+
+	idp := &stubIdP{
+		issuer:       strings.TrimSuffix(*issuer, "/"),
+		clientID:     *clientID,
+		clientSecret: *clientSecret,
+		key:          key,
+		codes:        make(map[string]authCode),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", idp.handleDiscovery)
+	mux.HandleFunc("/jwks", idp.handleJWKS)
+	mux.HandleFunc("/authorize", idp.handleAuthorize)
+	mux.HandleFunc("/token", idp.handleToken)
+
+	log.Printf("Stub IdP listening on %s with issuer %s", *addr, idp.issuer)
+	log.Fatal(http.ListenAndServe(*addr, mux))
+}
+
+func writeJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(payload)
+}
+
+func tokenError(w http.ResponseWriter, code, description string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": code, "error_description": description})
+}
+
+func randomString() string {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		log.Fatalf("Failed to read random bytes: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func (idp *stubIdP) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                idp.issuer,
+		"authorization_endpoint":                idp.issuer + "/authorize",
+		"token_endpoint":                        idp.issuer + "/token",
+		"jwks_uri":                              idp.issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"scopes_supported":                      []string{"openid", "email", "profile"},
+	})
+}
+
+func (idp *stubIdP) handleJWKS(w http.ResponseWriter, r *http.Request) {
+	pub := idp.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"kid": keyID,
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+// handleAuthorize shows the login form on GET and issues a code on POST.
+func (idp *stubIdP) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+	params := map[string]string{}
+	for _, name := range []string{"client_id", "redirect_uri", "state", "nonce", "code_challenge", "code_challenge_method", "response_type", "scope"} {
+		params[name] = r.Form.Get(name)
+	}
+	if params["client_id"] != idp.clientID {
+		http.Error(w, "unknown client_id", http.StatusBadRequest)
+		return
+	}
+	if params["response_type"] != "code" || params["code_challenge_method"] != "S256" || params["code_challenge"] == "" {
+		http.Error(w, "only response_type=code with S256 PKCE is supported", http.StatusBadRequest)
+		return
+	}
+	redirectURI, err := url.Parse(params["redirect_uri"])
+	if err != nil || redirectURI.Scheme == "" {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		loginPage.Execute(w, map[string]any{"Params": params})
+		return
+	}
+
+	email := strings.ToLower(strings.TrimSpace(r.PostForm.Get("email")))
+	if email == "" {
+		http.Error(w, "email is required", http.StatusBadRequest)
+		return
+	}
+	givenName := strings.TrimSpace(r.PostForm.Get("given_name"))
+	familyName := strings.TrimSpace(r.PostForm.Get("family_name"))
+	subject := sha256.Sum256([]byte(email))
+
+	code := randomString()
+	idp.mu.Lock()
+	idp.codes[code] = authCode{
+		clientID:      params["client_id"],
+		redirectURI:   params["redirect_uri"],
+		nonce:         params["nonce"],
+		codeChallenge: params["code_challenge"],
+		claims: jwt.MapClaims{
+			"sub":            hex.EncodeToString(subject[:16]),
+			"email":          email,
+			"email_verified": r.PostForm.Get("email_verified") == "true",
+			"given_name":     givenName,
+			"family_name":    familyName,
+			"name":           strings.TrimSpace(givenName + " " + familyName),
+		},
+		expiresAt: time.Now().Add(time.Minute),
+	}
+	idp.mu.Unlock()
+
+	query := redirectURI.Query()
+	query.Set("code", code)
+	query.Set("state", params["state"])
+	redirectURI.RawQuery = query.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (idp *stubIdP) handleToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		tokenError(w, "invalid_request", "malformed form body")
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != idp.clientID || subtle.ConstantTimeCompare([]byte(clientSecret), []byte(idp.clientSecret)) != 1 {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		tokenError(w, "unsupported_grant_type", "only authorization_code is supported")
+		return
+	}
+
+	idp.mu.Lock()
+	code, found := idp.codes[r.PostForm.Get("code")]
+	delete(idp.codes, r.PostForm.Get("code"))
+	idp.mu.Unlock()
+	if !found || time.Now().After(code.expiresAt) || code.clientID != clientID {
+		tokenError(w, "invalid_grant", "unknown or expired code")
+		return
+	}
+	if r.PostForm.Get("redirect_uri") != code.redirectURI {
+		tokenError(w, "invalid_grant", "redirect_uri does not match")
+		return
+	}
+	challenge := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(challenge[:]) != code.codeChallenge {
+		tokenError(w, "invalid_grant", "PKCE verification failed")
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":   idp.issuer,
+		"aud":   clientID,
+		"iat":   now.Unix(),
+		"exp":   now.Add(5 * time.Minute).Unix(),
+		"nonce": code.nonce,
+	}
+	for k, v := range code.claims {
+		claims[k] = v
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = keyID
+	idToken, err := token.SignedString(idp.key)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
@@ -43,3 +43,18 @@ func GetBool(key string, fallback bool) bool {
 	}
 	return res
 }
+
+// GetList reads a comma-separated value, dropping empty entries.
+func GetList(key string, fallback []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	var res []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			res = append(res, item)
+		}
+	}
+	return res
+}
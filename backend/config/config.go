@@ -3,6 +3,9 @@ package config
 
 import (
 	"fmt"
+	"strings"
+	"time"
+
 	"github.com/dukunuu/hackathon_backend/common" // Assuming this path is correct
 )
 
@@ -10,7 +13,10 @@ type Config struct {
 	DB_URL               string
 	ENV                  string
 	HOST                 string
-	JWT_SECRET           string
+	JWT_KEYS_DIR         string // Directory of <kid>.pem keys used to sign and verify tokens
+	JWT_ACTIVE_KEY_ID    string // kid of the key new tokens are signed with
+	ACCESS_TOKEN_TTL     time.Duration
+	REFRESH_TOKEN_TTL    time.Duration
 	MINIO_ENDPOINT       string
 	MINIO_ACCESS_KEY_ID  string
 	MINIO_SECRET_ACCESS_KEY string
@@ -22,6 +28,43 @@ type Config struct {
 	OLLAMA_ADDR          string
 	OLLAMA_MODEL_NAME    string
 	OLLAMA_SYSTEM_PROMPT string
+
+	// Optional: email of an already registered user promoted to ADMIN on startup
+	BOOTSTRAP_ADMIN_EMAIL string
+
+	// Outgoing mail: MAIL_DRIVER is one of smtp, file or memory
+	MAIL_DRIVER   string
+	MAIL_FROM     string
+	MAIL_FILE_DIR string
+	SMTP_HOST     string
+	SMTP_PORT     int
+	SMTP_USERNAME string
+	SMTP_PASSWORD string
+	APP_BASE_URL  string // Frontend URL used to build links in emails
+
+	MFA_ISSUER string // Name authenticator apps show next to TOTP codes
+
+	// Deleted accounts can be restored for this long before they are purged
+	ACCOUNT_DELETION_GRACE time.Duration
+
+	// Public URL of this API, used to build OIDC redirect URIs
+	API_BASE_URL   string
+	OIDC_PROVIDERS []OIDCProviderConfig
+}
+
+// OIDCProviderConfig describes one OpenID Connect identity provider. Each
+// provider listed in OIDC_PROVIDERS is read from OIDC_<NAME>_* variables.
+type OIDCProviderConfig struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+}
+
+// IsProduction reports whether ENV names a production deployment.
+func (c *Config) IsProduction() bool {
+	return c.ENV == "prod" || c.ENV == "production"
 }
 
 func LoadConfig() (*Config, error) {
@@ -32,7 +75,10 @@ func LoadConfig() (*Config, error) {
 	}
 
 	addr := common.GetString("HOST", ":8080") // Defaulting to 8000 as per previous discussion
-	jwt := common.GetString("JWT_SECRET", "my_app_secret")
+	jwtKeysDir := common.GetString("JWT_KEYS_DIR", "")
+	jwtActiveKeyID := common.GetString("JWT_ACTIVE_KEY_ID", "")
+	accessTokenTTL := time.Duration(common.GetNumber("ACCESS_TOKEN_TTL_MINUTES", 15)) * time.Minute
+	refreshTokenTTL := time.Duration(common.GetNumber("REFRESH_TOKEN_TTL_HOURS", 24*30)) * time.Hour
 
 	minioEndpoint := common.GetString("MINIO_ENDPOINT", "")
 	minioAccessKey := common.GetString("MINIO_ACCESS_KEY_ID", "")
@@ -65,11 +111,46 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("OLLAMA_MODEL_NAME must be set")
 	}
 
+	bootstrapAdminEmail := common.GetString("BOOTSTRAP_ADMIN_EMAIL", "")
+
+	mailDriver := common.GetString("MAIL_DRIVER", "file")
+	mailFrom := common.GetString("MAIL_FROM", "no-reply@localhost")
+	mailFileDir := common.GetString("MAIL_FILE_DIR", "./mail_outbox")
+	smtpHost := common.GetString("SMTP_HOST", "")
+	smtpPort := common.GetNumber("SMTP_PORT", 587)
+	smtpUsername := common.GetString("SMTP_USERNAME", "")
+	smtpPassword := common.GetString("SMTP_PASSWORD", "")
+	appBaseURL := common.TrimSuffix(common.GetString("APP_BASE_URL", "http://localhost:3000"), "/")
+
+	mfaIssuer := common.GetString("MFA_ISSUER", "Hackathon")
+
+	accountDeletionGrace := time.Duration(common.GetNumber("ACCOUNT_DELETION_GRACE_DAYS", 30)) * 24 * time.Hour
+
+	apiBaseURL := common.TrimSuffix(common.GetString("API_BASE_URL", "http://localhost:8080"), "/")
+	var oidcProviders []OIDCProviderConfig
+	for _, name := range common.GetList("OIDC_PROVIDERS", nil) {
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		provider := OIDCProviderConfig{
+			Name:         strings.ToLower(name),
+			Issuer:       common.GetString(prefix+"ISSUER", ""),
+			ClientID:     common.GetString(prefix+"CLIENT_ID", ""),
+			ClientSecret: common.GetString(prefix+"CLIENT_SECRET", ""),
+			Scopes:       common.GetList(prefix+"SCOPES", []string{"openid", "email", "profile"}),
+		}
+		if provider.Issuer == "" || provider.ClientID == "" {
+			return nil, fmt.Errorf("%sISSUER and %sCLIENT_ID must be set for OIDC provider '%s'", prefix, prefix, name)
+		}
+		oidcProviders = append(oidcProviders, provider)
+	}
+
 	return &Config{
 		ENV:                     appEnv,
 		DB_URL:                  dbUrl,
 		HOST:                    addr,
-		JWT_SECRET:              jwt,
+		JWT_KEYS_DIR:            jwtKeysDir,
+		JWT_ACTIVE_KEY_ID:       jwtActiveKeyID,
+		ACCESS_TOKEN_TTL:        accessTokenTTL,
+		REFRESH_TOKEN_TTL:       refreshTokenTTL,
 		MINIO_ENDPOINT:          minioEndpoint,
 		MINIO_ACCESS_KEY_ID:     minioAccessKey,
 		MINIO_SECRET_ACCESS_KEY: minioSecretKey,
@@ -81,6 +162,24 @@ func LoadConfig() (*Config, error) {
 		OLLAMA_ADDR:          ollamaAddr,
 		OLLAMA_MODEL_NAME:    ollamaModelName,
 		OLLAMA_SYSTEM_PROMPT: ollamaSystemPrompt,
+
+		BOOTSTRAP_ADMIN_EMAIL: bootstrapAdminEmail,
+
+		MAIL_DRIVER:   mailDriver,
+		MAIL_FROM:     mailFrom,
+		MAIL_FILE_DIR: mailFileDir,
+		SMTP_HOST:     smtpHost,
+		SMTP_PORT:     smtpPort,
+		SMTP_USERNAME: smtpUsername,
+		SMTP_PASSWORD: smtpPassword,
+		APP_BASE_URL:  appBaseURL,
+
+		MFA_ISSUER: mfaIssuer,
+
+		ACCOUNT_DELETION_GRACE: accountDeletionGrace,
+
+		API_BASE_URL:   apiBaseURL,
+		OIDC_PROVIDERS: oidcProviders,
 	}, nil
 }
 
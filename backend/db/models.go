@@ -141,11 +141,55 @@ func (ns NullPostType) Value() (driver.Value, error) {
 	return string(ns.PostType), nil
 }
 
+type ProfileFieldVisibility string
+
+const (
+	ProfileFieldVisibilityPrivate    ProfileFieldVisibility = "private"
+	ProfileFieldVisibilityOrganizers ProfileFieldVisibility = "organizers"
+	ProfileFieldVisibilityPublic     ProfileFieldVisibility = "public"
+)
+
+func (e *ProfileFieldVisibility) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ProfileFieldVisibility(s)
+	case string:
+		*e = ProfileFieldVisibility(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ProfileFieldVisibility: %T", src)
+	}
+	return nil
+}
+
+type NullProfileFieldVisibility struct {
+	ProfileFieldVisibility ProfileFieldVisibility
+	Valid                  bool // Valid is true if ProfileFieldVisibility is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullProfileFieldVisibility) Scan(value interface{}) error {
+	if value == nil {
+		ns.ProfileFieldVisibility, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ProfileFieldVisibility.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullProfileFieldVisibility) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ProfileFieldVisibility), nil
+}
+
 type UserRole string
 
 const (
-	UserRoleADMIN UserRole = "ADMIN"
-	UserRoleUSER  UserRole = "USER"
+	UserRoleADMIN     UserRole = "ADMIN"
+	UserRoleUSER      UserRole = "USER"
+	UserRoleMODERATOR UserRole = "MODERATOR"
 )
 
 func (e *UserRole) Scan(src interface{}) error {
@@ -183,6 +227,50 @@ func (ns NullUserRole) Value() (driver.Value, error) {
 	return string(ns.UserRole), nil
 }
 
+type UserTokenPurpose string
+
+const (
+	UserTokenPurposeEmailVerification UserTokenPurpose = "email_verification"
+	UserTokenPurposeEmailChange       UserTokenPurpose = "email_change"
+	UserTokenPurposePasswordReset     UserTokenPurpose = "password_reset"
+	UserTokenPurposeOidcLogin         UserTokenPurpose = "oidc_login"
+)
+
+func (e *UserTokenPurpose) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = UserTokenPurpose(s)
+	case string:
+		*e = UserTokenPurpose(s)
+	default:
+		return fmt.Errorf("unsupported scan type for UserTokenPurpose: %T", src)
+	}
+	return nil
+}
+
+type NullUserTokenPurpose struct {
+	UserTokenPurpose UserTokenPurpose
+	Valid            bool // Valid is true if UserTokenPurpose is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullUserTokenPurpose) Scan(value interface{}) error {
+	if value == nil {
+		ns.UserTokenPurpose, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.UserTokenPurpose.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullUserTokenPurpose) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.UserTokenPurpose), nil
+}
+
 type VolunteerStatus string
 
 const (
@@ -190,6 +278,8 @@ const (
 	VolunteerStatusApproved  VolunteerStatus = "approved"
 	VolunteerStatusCompleted VolunteerStatus = "completed"
 	VolunteerStatusRejected  VolunteerStatus = "rejected"
+	VolunteerStatusWithdrawn VolunteerStatus = "withdrawn"
+	VolunteerStatusNoShow    VolunteerStatus = "no_show"
 )
 
 func (e *VolunteerStatus) Scan(src interface{}) error {
@@ -227,6 +317,40 @@ func (ns NullVolunteerStatus) Value() (driver.Value, error) {
 	return string(ns.VolunteerStatus), nil
 }
 
+type AccountLockout struct {
+	ID     pgtype.UUID
+	UserID pgtype.UUID
+	// Client address of the failed attempt that triggered the lockout
+	IpAddress pgtype.Text
+	// Consecutive failures on the account when it was locked
+	FailedAttempts int32
+	LockedAt       pgtype.Timestamptz
+	LockedUntil    pgtype.Timestamptz
+	// Set when an administrator lifts the lockout early
+	UnlockedAt pgtype.Timestamptz
+	// Administrator who lifted the lockout
+	UnlockedBy pgtype.UUID
+}
+
+type ApiKey struct {
+	ID     pgtype.UUID
+	UserID pgtype.UUID
+	Name   string
+	// Leading characters of the key, shown so users can tell their keys apart
+	Prefix string
+	// SHA-256 hash of the full key; the key itself is only shown once
+	KeyHash string
+	// What the key may do, on top of the owner's role
+	Scopes []string
+	// Whether the session that created the key had passed a second factor
+	MfaVerified bool
+	ExpiresAt   pgtype.Timestamptz
+	// Updated at most once a minute
+	LastUsedAt pgtype.Timestamptz
+	RevokedAt  pgtype.Timestamptz
+	CreatedAt  pgtype.Timestamptz
+}
+
 type Category struct {
 	ID           pgtype.UUID
 	Name         string
@@ -237,16 +361,48 @@ type Category struct {
 	UpdatedAt    pgtype.Timestamptz
 }
 
+type LoginThrottle struct {
+	// What the failures are counted for: 'email:<address>' or 'ip:<address>'
+	ThrottleKey string
+	// Consecutive failed logins; restarts after a day without failures
+	FailedAttempts int32
+	LastFailedAt   pgtype.Timestamptz
+	// No login attempt for this key is evaluated before this time
+	BlockedUntil pgtype.Timestamptz
+}
+
+type MfaRecoveryCode struct {
+	ID     pgtype.UUID
+	UserID pgtype.UUID
+	// SHA-256 hash of a single-use recovery code
+	CodeHash  string
+	UsedAt    pgtype.Timestamptz
+	CreatedAt pgtype.Timestamptz
+}
+
+// Authorization requests sent to an OIDC provider and not yet completed
+type OidcAuthRequest struct {
+	State    string
+	Provider string
+	Nonce    string
+	// PKCE verifier whose S256 challenge was sent to the provider
+	CodeVerifier string
+	ExpiresAt    pgtype.Timestamptz
+	CreatedAt    pgtype.Timestamptz
+}
+
 type Post struct {
-	ID                pgtype.UUID
-	Title             string
-	Description       string
-	Status            PostStatus
-	Priority          PostPriority
-	PreviewUrl        pgtype.Text
-	PostType          PostType
-	UserID            pgtype.UUID
-	MaxVolunteers     int32
+	ID          pgtype.UUID
+	Title       string
+	Description string
+	Status      PostStatus
+	Priority    PostPriority
+	PreviewUrl  pgtype.Text
+	PostType    PostType
+	// Author; NULL once the author's account has been purged
+	UserID        pgtype.UUID
+	MaxVolunteers int32
+	// Approved and completed volunteers on the post, maintained by trigger_post_volunteers_count
 	CurrentVolunteers int32
 	CategoryID        pgtype.UUID
 	LocationLat       pgtype.Float8
@@ -254,6 +410,8 @@ type Post struct {
 	AddressText       pgtype.Text
 	CreatedAt         pgtype.Timestamptz
 	UpdatedAt         pgtype.Timestamptz
+	// Weighted full-text document over title (A), description (B) and address_text (C)
+	SearchVector interface{}
 }
 
 type PostImage struct {
@@ -261,18 +419,99 @@ type PostImage struct {
 	PostID    pgtype.UUID
 	ImageUrl  string
 	CreatedAt pgtype.Timestamptz
+	// Display order within the post, from 0; the image at 0 is the primary image and the post's preview
+	Position int32
 }
 
-type PostVolunteer struct {
-	ID        pgtype.UUID
-	UserID    pgtype.UUID
+// Time slots of a post that approved volunteers sign up for
+type PostShift struct {
+	ID           pgtype.UUID
+	PostID       pgtype.UUID
+	StartsAt     pgtype.Timestamptz
+	EndsAt       pgtype.Timestamptz
+	MeetingPoint pgtype.Text
+	MeetingLat   pgtype.Float8
+	MeetingLng   pgtype.Float8
+	Capacity     int32
+	// Volunteers signed up for the shift, maintained by trigger_shift_signups_count
+	SignedUp  int32
+	CreatedAt pgtype.Timestamptz
+	UpdatedAt pgtype.Timestamptz
+}
+
+// Skills a post needs from its volunteers
+type PostSkill struct {
 	PostID    pgtype.UUID
-	Status    pgtype.Text
+	Skill     string
+	CreatedAt pgtype.Timestamptz
+}
+
+// Every status a post has been moved to, and by whom
+type PostStatusHistory struct {
+	ID     pgtype.UUID
+	PostID pgtype.UUID
+	// NULL for the status a post was created with
+	FromStatus NullPostStatus
+	ToStatus   PostStatus
+	// NULL once the acting user's account has been purged
+	ChangedBy pgtype.UUID
+	Comment   pgtype.Text
+	CreatedAt pgtype.Timestamptz
+}
+
+type PostVolunteer struct {
+	ID     pgtype.UUID
+	UserID pgtype.UUID
+	PostID pgtype.UUID
+	// pending until the organizer approves or rejects; approved volunteers end as completed or no_show, or withdraw
+	Status    VolunteerStatus
 	Notes     pgtype.Text
 	CreatedAt pgtype.Timestamptz
 	UpdatedAt pgtype.Timestamptz
 }
 
+type Session struct {
+	ID     pgtype.UUID
+	UserID pgtype.UUID
+	// SHA-256 hash of the current refresh token; the token itself is never stored
+	RefreshTokenHash string
+	// Hash of the refresh token replaced by the last rotation, used to detect token reuse
+	PreviousTokenHash pgtype.Text
+	UserAgent         pgtype.Text
+	IpAddress         pgtype.Text
+	// Absolute expiry of the session; rotation does not extend it
+	ExpiresAt pgtype.Timestamptz
+	// Set on logout, credential change or detected token reuse
+	RevokedAt  pgtype.Timestamptz
+	LastUsedAt pgtype.Timestamptz
+	CreatedAt  pgtype.Timestamptz
+	// Whether the login that started the session passed a second factor
+	MfaVerified bool
+}
+
+// An approved volunteer's place on a shift, with their check-in and check-out
+type ShiftSignup struct {
+	ID          pgtype.UUID
+	ShiftID     pgtype.UUID
+	VolunteerID pgtype.UUID
+	CheckedInAt pgtype.Timestamptz
+	CheckInLat  pgtype.Float8
+	CheckInLng  pgtype.Float8
+	// Hours are credited for the part of [checked_in_at, checked_out_at] inside the shift
+	CheckedOutAt pgtype.Timestamptz
+	CheckOutLat  pgtype.Float8
+	CheckOutLng  pgtype.Float8
+	CreatedAt    pgtype.Timestamptz
+}
+
+// Runtime settings administrators can change without a deploy
+type SystemSetting struct {
+	Key       string
+	Value     string
+	UpdatedBy pgtype.UUID
+	UpdatedAt pgtype.Timestamptz
+}
+
 type User struct {
 	// Primary key, UUID
 	ID pgtype.UUID
@@ -286,7 +525,7 @@ type User struct {
 	IsVolunteering bool
 	// User's email address (unique, mandatory)
 	Email string
-	// User's role in the system (admin or user, default: user)
+	// User's role in the system (ADMIN, MODERATOR or USER, default: USER)
 	Role UserRole
 	// URL to the user's profile picture or page (optional)
 	ProfileUrl pgtype.Text
@@ -296,4 +535,79 @@ type User struct {
 	CreatedAt pgtype.Timestamptz
 	// Timestamp of when the user record was last updated
 	UpdatedAt pgtype.Timestamptz
+	// Timestamp of when the user confirmed their email address (NULL until verified)
+	EmailVerifiedAt pgtype.Timestamptz
+	// Set when the account is deleted; it can be restored until the purge job removes it after the grace period
+	DeletedAt pgtype.Timestamptz
+}
+
+// Weekly time slots in which a user can volunteer
+type UserAvailability struct {
+	ID     pgtype.UUID
+	UserID pgtype.UUID
+	// 0 is Sunday; times are in the database time zone
+	Weekday   int16
+	StartTime pgtype.Time
+	EndTime   pgtype.Time
+	CreatedAt pgtype.Timestamptz
+}
+
+type UserIdentity struct {
+	ID     pgtype.UUID
+	UserID pgtype.UUID
+	// Name of the configured OIDC provider
+	Provider string
+	// Stable 'sub' claim of the user at the provider
+	Subject string
+	// Email the provider reported at the last login
+	Email       pgtype.Text
+	CreatedAt   pgtype.Timestamptz
+	LastLoginAt pgtype.Timestamptz
+}
+
+type UserMfa struct {
+	UserID pgtype.UUID
+	// Base32 TOTP shared secret (RFC 6238)
+	Secret string
+	// NULL while enrollment is pending confirmation with a first code
+	EnabledAt pgtype.Timestamptz
+	// Time step of the last accepted code, so a code cannot be replayed
+	LastUsedStep pgtype.Int8
+	CreatedAt    pgtype.Timestamptz
+}
+
+// Who may see contact details on a public profile; users without a row get the column defaults
+type UserPrivacySetting struct {
+	UserID          pgtype.UUID
+	PhoneVisibility ProfileFieldVisibility
+	EmailVisibility ProfileFieldVisibility
+	UpdatedAt       pgtype.Timestamptz
+}
+
+// Skills a user offers as a volunteer
+type UserSkill struct {
+	UserID    pgtype.UUID
+	Skill     string
+	CreatedAt pgtype.Timestamptz
+}
+
+type UserToken struct {
+	ID      pgtype.UUID
+	UserID  pgtype.UUID
+	Purpose UserTokenPurpose
+	// SHA-256 hash of the one-time token sent by email
+	TokenHash string
+	// Address being confirmed, only set for email_change tokens
+	NewEmail   pgtype.Text
+	ExpiresAt  pgtype.Timestamptz
+	ConsumedAt pgtype.Timestamptz
+	CreatedAt  pgtype.Timestamptz
+}
+
+// Where a volunteer is based, for matching them to nearby posts
+type VolunteerProfile struct {
+	UserID      pgtype.UUID
+	LocationLat pgtype.Float8
+	LocationLng pgtype.Float8
+	UpdatedAt   pgtype.Timestamptz
 }
@@ -12,7 +12,7 @@ import (
 )
 
 const approveVolunteer = `-- name: ApproveVolunteer :one
-UPDATE post_volunteers SET status = 'approved', updated_at = CURRENT_TIMESTAMP WHERE post_id = $1 AND user_id = $2 RETURNING id, user_id, post_id, status, notes, created_at, updated_at
+UPDATE post_volunteers SET status = 'approved', updated_at = CURRENT_TIMESTAMP WHERE post_id = $1 AND user_id = $2 AND status = 'pending' RETURNING id, user_id, post_id, status, notes, created_at, updated_at
 `
 
 type ApproveVolunteerParams struct {
@@ -20,6 +20,7 @@ type ApproveVolunteerParams struct {
 	UserID pgtype.UUID
 }
 
+// Only pending applications are decided; others are left unchanged.
 func (q *Queries) ApproveVolunteer(ctx context.Context, arg ApproveVolunteerParams) (PostVolunteer, error) {
 	row := q.db.QueryRow(ctx, approveVolunteer, arg.PostID, arg.UserID)
 	var i PostVolunteer
@@ -69,6 +70,7 @@ func (q *Queries) CreateCategory(ctx context.Context, arg CreateCategoryParams)
 
 const createPost = `-- name: CreatePost :one
 INSERT INTO posts (
+    id,
     title,
     description,
     status,
@@ -83,11 +85,12 @@ INSERT INTO posts (
     location_lng,
     address_text
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
-) RETURNING id, title, description, status, priority, preview_url, post_type, user_id, max_volunteers, current_volunteers, category_id, location_lat, location_lng, address_text, created_at, updated_at
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
+) RETURNING id, title, description, status, priority, preview_url, post_type, user_id, max_volunteers, current_volunteers, category_id, location_lat, location_lng, address_text, created_at, updated_at, search_vector
 `
 
 type CreatePostParams struct {
+	ID                pgtype.UUID
 	Title             string
 	Description       string
 	Status            PostStatus
@@ -105,6 +108,7 @@ type CreatePostParams struct {
 
 func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, error) {
 	row := q.db.QueryRow(ctx, createPost,
+		arg.ID,
 		arg.Title,
 		arg.Description,
 		arg.Status,
@@ -137,13 +141,14 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, e
 		&i.AddressText,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SearchVector,
 	)
 	return i, err
 }
 
 const createPostImage = `-- name: CreatePostImage :one
-INSERT INTO post_images(post_id, image_url)
-VALUES ($1, $2) RETURNING id, post_id, image_url, created_at
+INSERT INTO post_images(post_id, image_url, position)
+VALUES ($1, $2, (SELECT COALESCE(MAX(position) + 1, 0) FROM post_images WHERE post_id = $1)) RETURNING id, post_id, image_url, created_at, position
 `
 
 type CreatePostImageParams struct {
@@ -151,6 +156,7 @@ type CreatePostImageParams struct {
 	ImageUrl string
 }
 
+// Appends an image after the post's existing images.
 func (q *Queries) CreatePostImage(ctx context.Context, arg CreatePostImageParams) (PostImage, error) {
 	row := q.db.QueryRow(ctx, createPostImage, arg.PostID, arg.ImageUrl)
 	var i PostImage
@@ -159,6 +165,7 @@ func (q *Queries) CreatePostImage(ctx context.Context, arg CreatePostImageParams
 		&i.PostID,
 		&i.ImageUrl,
 		&i.CreatedAt,
+		&i.Position,
 	)
 	return i, err
 }
@@ -248,15 +255,10 @@ SELECT
     p.created_at,
     p.updated_at,
     (
-        SELECT json_agg(pi.image_url)
+        SELECT json_agg(pi.image_url ORDER BY pi.position)
         FROM post_images pi
         WHERE pi.post_id = p.id
-    ) AS images,
-    (
-        SELECT json_agg(pv.*)
-        FROM post_volunteers pv
-        WHERE pv.post_id = p.id
-    ) AS volunteers
+    ) AS images
 FROM posts p
 WHERE p.id = $1
 `
@@ -279,7 +281,6 @@ type GetPostRow struct {
 	CreatedAt         pgtype.Timestamptz
 	UpdatedAt         pgtype.Timestamptz
 	Images            []byte
-	Volunteers        []byte
 }
 
 func (q *Queries) GetPost(ctx context.Context, id pgtype.UUID) (GetPostRow, error) {
@@ -303,15 +304,16 @@ func (q *Queries) GetPost(ctx context.Context, id pgtype.UUID) (GetPostRow, erro
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Images,
-		&i.Volunteers,
 	)
 	return i, err
 }
 
 const getUserPosts = `-- name: GetUserPosts :many
-SELECT id, title, description, status, priority, preview_url, post_type, user_id, max_volunteers, current_volunteers, category_id, location_lat, location_lng, address_text, created_at, updated_at FROM posts WHERE user_id = $1 ORDER BY created_at DESC
+SELECT id, title, description, status, priority, preview_url, post_type, user_id, max_volunteers, current_volunteers, category_id, location_lat, location_lng, address_text, created_at, updated_at, search_vector FROM posts WHERE user_id = $1 ORDER BY created_at DESC
 `
 
+// Every post of the user, for the data export. Listings page through
+// ListPosts instead.
 func (q *Queries) GetUserPosts(ctx context.Context, userID pgtype.UUID) ([]Post, error) {
 	rows, err := q.db.Query(ctx, getUserPosts, userID)
 	if err != nil {
@@ -338,6 +340,7 @@ func (q *Queries) GetUserPosts(ctx context.Context, userID pgtype.UUID) ([]Post,
 			&i.AddressText,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.SearchVector,
 		); err != nil {
 			return nil, err
 		}
@@ -353,54 +356,40 @@ const getUserStats = `-- name: GetUserStats :one
 SELECT
     (SELECT COUNT(*) FROM posts p WHERE p.user_id = $1) AS user_posts_count,
     (SELECT COUNT(*) FROM post_volunteers p_v WHERE p_v.user_id = $1) AS user_volunteer_count,
-    (SELECT COUNT(*) FROM posts po WHERE po.user_id = $1 AND status = 'Шийдвэрлэгдсэн') AS approved_posts
+    (SELECT COUNT(*) FROM posts po WHERE po.user_id = $1 AND status = 'Шийдвэрлэгдсэн') AS approved_posts,
+    (
+        SELECT EXTRACT(EPOCH FROM COALESCE(SUM(GREATEST(
+            LEAST(ss.checked_out_at, s.ends_at) - GREATEST(ss.checked_in_at, s.starts_at),
+            INTERVAL '0'
+        )), INTERVAL '0')) / 3600
+        FROM shift_signups ss
+        JOIN post_shifts s ON s.id = ss.shift_id
+        JOIN post_volunteers pv ON pv.id = ss.volunteer_id
+        WHERE pv.user_id = $1 AND ss.checked_out_at IS NOT NULL
+    )::float8 AS volunteered_hours
 `
 
 type GetUserStatsRow struct {
 	UserPostsCount     int64
 	UserVolunteerCount int64
 	ApprovedPosts      int64
+	VolunteeredHours   float64
 }
 
+// volunteered_hours only counts the part of each check-in that falls inside
+// its shift.
 func (q *Queries) GetUserStats(ctx context.Context, userID pgtype.UUID) (GetUserStatsRow, error) {
 	row := q.db.QueryRow(ctx, getUserStats, userID)
 	var i GetUserStatsRow
-	err := row.Scan(&i.UserPostsCount, &i.UserVolunteerCount, &i.ApprovedPosts)
+	err := row.Scan(
+		&i.UserPostsCount,
+		&i.UserVolunteerCount,
+		&i.ApprovedPosts,
+		&i.VolunteeredHours,
+	)
 	return i, err
 }
 
-const listPostVolunteers = `-- name: ListPostVolunteers :many
-SELECT id, user_id, post_id, status, notes, created_at, updated_at FROM post_volunteers ORDER BY created_at DESC
-`
-
-func (q *Queries) ListPostVolunteers(ctx context.Context) ([]PostVolunteer, error) {
-	rows, err := q.db.Query(ctx, listPostVolunteers)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []PostVolunteer
-	for rows.Next() {
-		var i PostVolunteer
-		if err := rows.Scan(
-			&i.ID,
-			&i.UserID,
-			&i.PostID,
-			&i.Status,
-			&i.Notes,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
 const listPosts = `-- name: ListPosts :many
 SELECT
     p.id,
@@ -420,19 +409,45 @@ SELECT
     p.created_at,
     p.updated_at,
     (
-        SELECT json_agg(pi.image_url)
+        SELECT json_agg(pi.image_url ORDER BY pi.position)
         FROM post_images pi
         WHERE pi.post_id = p.id
-    ) AS images,
-    (
-        SELECT json_agg(pv.*)
-        FROM post_volunteers pv
-        WHERE pv.post_id = p.id
-    ) AS volunteers
+    ) AS images
 FROM posts p
-ORDER BY p.created_at DESC
+WHERE ($1::post_status IS NULL OR p.status = $1)
+  AND ($2::post_priority IS NULL OR p.priority = $2)
+  AND ($3::post_type IS NULL OR p.post_type = $3)
+  AND ($4::uuid IS NULL OR p.category_id = $4)
+  AND ($5::uuid IS NULL OR p.user_id = $5)
+  AND ($6::timestamptz IS NULL OR p.created_at >= $6)
+  AND ($7::timestamptz IS NULL OR p.created_at < $7)
+  AND (
+    $8::timestamptz IS NULL
+    OR ($9::boolean AND (p.created_at, p.id) > ($8, $10::uuid))
+    OR (NOT $9::boolean AND (p.created_at, p.id) < ($8, $10::uuid))
+  )
+ORDER BY
+    CASE WHEN $9::boolean THEN p.created_at END ASC,
+    CASE WHEN $9::boolean THEN p.id END ASC,
+    CASE WHEN NOT $9::boolean THEN p.created_at END DESC,
+    CASE WHEN NOT $9::boolean THEN p.id END DESC
+LIMIT $11
 `
 
+type ListPostsParams struct {
+	Status          NullPostStatus
+	Priority        NullPostPriority
+	PostType        NullPostType
+	CategoryID      pgtype.UUID
+	AuthorID        pgtype.UUID
+	CreatedAfter    pgtype.Timestamptz
+	CreatedBefore   pgtype.Timestamptz
+	CursorCreatedAt pgtype.Timestamptz
+	SortAsc         bool
+	CursorID        pgtype.UUID
+	PageSize        int32
+}
+
 type ListPostsRow struct {
 	ID                pgtype.UUID
 	Title             string
@@ -451,11 +466,24 @@ type ListPostsRow struct {
 	CreatedAt         pgtype.Timestamptz
 	UpdatedAt         pgtype.Timestamptz
 	Images            []byte
-	Volunteers        []byte
 }
 
-func (q *Queries) ListPosts(ctx context.Context) ([]ListPostsRow, error) {
-	rows, err := q.db.Query(ctx, listPosts)
+// Keyset pagination on (created_at, id): the cursor is the last row of the
+// previous page. NULL filters match every post.
+func (q *Queries) ListPosts(ctx context.Context, arg ListPostsParams) ([]ListPostsRow, error) {
+	rows, err := q.db.Query(ctx, listPosts,
+		arg.Status,
+		arg.Priority,
+		arg.PostType,
+		arg.CategoryID,
+		arg.AuthorID,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.CursorCreatedAt,
+		arg.SortAsc,
+		arg.CursorID,
+		arg.PageSize,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -481,7 +509,6 @@ func (q *Queries) ListPosts(ctx context.Context) ([]ListPostsRow, error) {
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Images,
-			&i.Volunteers,
 		); err != nil {
 			return nil, err
 		}
@@ -493,8 +520,90 @@ func (q *Queries) ListPosts(ctx context.Context) ([]ListPostsRow, error) {
 	return items, nil
 }
 
+const patchPost = `-- name: PatchPost :one
+UPDATE posts SET
+    title = COALESCE($1, title),
+    description = COALESCE($2, description),
+    priority = COALESCE($3, priority),
+    post_type = COALESCE($4, post_type),
+    max_volunteers = COALESCE($5, max_volunteers),
+    preview_url = CASE WHEN $6::boolean THEN $7 ELSE preview_url END,
+    category_id = CASE WHEN $8::boolean THEN $9 ELSE category_id END,
+    location_lat = CASE WHEN $10::boolean THEN $11 ELSE location_lat END,
+    location_lng = CASE WHEN $10::boolean THEN $12 ELSE location_lng END,
+    address_text = CASE WHEN $13::boolean THEN $14 ELSE address_text END,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $15 AND updated_at = $16
+RETURNING id, title, description, status, priority, preview_url, post_type, user_id, max_volunteers, current_volunteers, category_id, location_lat, location_lng, address_text, created_at, updated_at, search_vector
+`
+
+type PatchPostParams struct {
+	Title          pgtype.Text
+	Description    pgtype.Text
+	Priority       NullPostPriority
+	PostType       NullPostType
+	MaxVolunteers  pgtype.Int4
+	SetPreviewUrl  bool
+	PreviewUrl     pgtype.Text
+	SetCategoryID  bool
+	CategoryID     pgtype.UUID
+	SetLocation    bool
+	LocationLat    pgtype.Float8
+	LocationLng    pgtype.Float8
+	SetAddressText bool
+	AddressText    pgtype.Text
+	ID             pgtype.UUID
+	UpdatedAt      pgtype.Timestamptz
+}
+
+// Applies a JSON Merge Patch. NULL leaves a required column unchanged; the
+// optional columns are only replaced when their set_* flag is true, so they
+// can also be cleared. Only applies while updated_at is still the value the
+// caller read.
+func (q *Queries) PatchPost(ctx context.Context, arg PatchPostParams) (Post, error) {
+	row := q.db.QueryRow(ctx, patchPost,
+		arg.Title,
+		arg.Description,
+		arg.Priority,
+		arg.PostType,
+		arg.MaxVolunteers,
+		arg.SetPreviewUrl,
+		arg.PreviewUrl,
+		arg.SetCategoryID,
+		arg.CategoryID,
+		arg.SetLocation,
+		arg.LocationLat,
+		arg.LocationLng,
+		arg.SetAddressText,
+		arg.AddressText,
+		arg.ID,
+		arg.UpdatedAt,
+	)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.Status,
+		&i.Priority,
+		&i.PreviewUrl,
+		&i.PostType,
+		&i.UserID,
+		&i.MaxVolunteers,
+		&i.CurrentVolunteers,
+		&i.CategoryID,
+		&i.LocationLat,
+		&i.LocationLng,
+		&i.AddressText,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SearchVector,
+	)
+	return i, err
+}
+
 const rejectVolunteer = `-- name: RejectVolunteer :one
-UPDATE post_volunteers SET status = 'rejected', updated_at = CURRENT_TIMESTAMP WHERE post_id = $1 AND user_id = $2 RETURNING id, user_id, post_id, status, notes, created_at, updated_at
+UPDATE post_volunteers SET status = 'rejected', updated_at = CURRENT_TIMESTAMP WHERE post_id = $1 AND user_id = $2 AND status = 'pending' RETURNING id, user_id, post_id, status, notes, created_at, updated_at
 `
 
 type RejectVolunteerParams struct {
@@ -502,6 +611,7 @@ type RejectVolunteerParams struct {
 	UserID pgtype.UUID
 }
 
+// Only pending applications are decided; others are left unchanged.
 func (q *Queries) RejectVolunteer(ctx context.Context, arg RejectVolunteerParams) (PostVolunteer, error) {
 	row := q.db.QueryRow(ctx, rejectVolunteer, arg.PostID, arg.UserID)
 	var i PostVolunteer
@@ -517,46 +627,140 @@ func (q *Queries) RejectVolunteer(ctx context.Context, arg RejectVolunteerParams
 	return i, err
 }
 
+const searchPosts = `-- name: SearchPosts :many
+SELECT
+    p.id,
+    p.title,
+    p.status,
+    p.priority,
+    p.post_type,
+    p.category_id,
+    p.address_text,
+    p.created_at,
+    ts_rank_cd(p.search_vector, to_tsquery('mongolian', $1))::real AS rank,
+    ts_headline('mongolian', p.title, to_tsquery('mongolian', $1),
+        'HighlightAll=true, StartSel=' || chr(2) || ', StopSel=' || chr(3))::text AS title_highlight,
+    ts_headline('mongolian', p.description, to_tsquery('mongolian', $1),
+        'MaxFragments=2, MaxWords=25, MinWords=10, FragmentDelimiter=" … ", StartSel=' || chr(2) || ', StopSel=' || chr(3))::text AS snippet,
+    ts_headline('mongolian', coalesce(p.address_text, ''), to_tsquery('mongolian', $1),
+        'HighlightAll=true, StartSel=' || chr(2) || ', StopSel=' || chr(3))::text AS address_highlight
+FROM posts p
+WHERE p.search_vector @@ to_tsquery('mongolian', $1)
+  AND ($2::post_status IS NULL OR p.status = $2)
+  AND ($3::post_priority IS NULL OR p.priority = $3)
+  AND ($4::post_type IS NULL OR p.post_type = $4)
+  AND ($5::uuid IS NULL OR p.category_id = $5)
+ORDER BY rank DESC, p.created_at DESC, p.id
+LIMIT $6
+`
+
+type SearchPostsParams struct {
+	Query       string
+	Status      NullPostStatus
+	Priority    NullPostPriority
+	PostType    NullPostType
+	CategoryID  pgtype.UUID
+	ResultLimit int32
+}
+
+type SearchPostsRow struct {
+	ID               pgtype.UUID
+	Title            string
+	Status           PostStatus
+	Priority         PostPriority
+	PostType         PostType
+	CategoryID       pgtype.UUID
+	AddressText      pgtype.Text
+	CreatedAt        pgtype.Timestamptz
+	Rank             float32
+	TitleHighlight   string
+	Snippet          string
+	AddressHighlight string
+}
+
+// Ranks posts matching a to_tsquery expression in the mongolian text search
+// configuration. Matches in the highlights are wrapped in chr(2) and chr(3)
+// so callers can escape the text before marking them up.
+func (q *Queries) SearchPosts(ctx context.Context, arg SearchPostsParams) ([]SearchPostsRow, error) {
+	rows, err := q.db.Query(ctx, searchPosts,
+		arg.Query,
+		arg.Status,
+		arg.Priority,
+		arg.PostType,
+		arg.CategoryID,
+		arg.ResultLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchPostsRow
+	for rows.Next() {
+		var i SearchPostsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Status,
+			&i.Priority,
+			&i.PostType,
+			&i.CategoryID,
+			&i.AddressText,
+			&i.CreatedAt,
+			&i.Rank,
+			&i.TitleHighlight,
+			&i.Snippet,
+			&i.AddressHighlight,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updatePost = `-- name: UpdatePost :one
 UPDATE posts SET
-    title = COALESCE($2, title),
-    description = COALESCE($3, description),
-    status = COALESCE($4, status),
-    priority = COALESCE($5, priority),
-    preview_url = COALESCE($6, preview_url),
-    post_type = COALESCE($7, post_type),
-    user_id = COALESCE($8, user_id),
-    max_volunteers = COALESCE($9, max_volunteers),
-    current_volunteers = COALESCE($10, current_volunteers),
-    category_id = COALESCE($11, category_id),
-    location_lat = COALESCE($12, location_lat),
-    location_lng = COALESCE($13, location_lng),
-    address_text = COALESCE($14, address_text),
+    title = COALESCE($1, title),
+    description = COALESCE($2, description),
+    status = COALESCE($3, status),
+    priority = COALESCE($4, priority),
+    preview_url = COALESCE($5, preview_url),
+    post_type = COALESCE($6, post_type),
+    user_id = COALESCE($7, user_id),
+    max_volunteers = COALESCE($8, max_volunteers),
+    category_id = COALESCE($9, category_id),
+    location_lat = COALESCE($10, location_lat),
+    location_lng = COALESCE($11, location_lng),
+    address_text = COALESCE($12, address_text),
     updated_at = CURRENT_TIMESTAMP
-WHERE id = $1
-RETURNING id, title, description, status, priority, preview_url, post_type, user_id, max_volunteers, current_volunteers, category_id, location_lat, location_lng, address_text, created_at, updated_at
+WHERE id = $13 AND updated_at = $14
+RETURNING id, title, description, status, priority, preview_url, post_type, user_id, max_volunteers, current_volunteers, category_id, location_lat, location_lng, address_text, created_at, updated_at, search_vector
 `
 
 type UpdatePostParams struct {
-	ID                pgtype.UUID
-	Title             string
-	Description       string
-	Status            PostStatus
-	Priority          PostPriority
-	PreviewUrl        pgtype.Text
-	PostType          PostType
-	UserID            pgtype.UUID
-	MaxVolunteers     int32
-	CurrentVolunteers int32
-	CategoryID        pgtype.UUID
-	LocationLat       pgtype.Float8
-	LocationLng       pgtype.Float8
-	AddressText       pgtype.Text
+	Title         pgtype.Text
+	Description   pgtype.Text
+	Status        PostStatus
+	Priority      PostPriority
+	PreviewUrl    pgtype.Text
+	PostType      PostType
+	UserID        pgtype.UUID
+	MaxVolunteers pgtype.Int4
+	CategoryID    pgtype.UUID
+	LocationLat   pgtype.Float8
+	LocationLng   pgtype.Float8
+	AddressText   pgtype.Text
+	ID            pgtype.UUID
+	UpdatedAt     pgtype.Timestamptz
 }
 
+// NULL keeps the current value. Only updates the post while updated_at is
+// still the value the caller read.
 func (q *Queries) UpdatePost(ctx context.Context, arg UpdatePostParams) (Post, error) {
 	row := q.db.QueryRow(ctx, updatePost,
-		arg.ID,
 		arg.Title,
 		arg.Description,
 		arg.Status,
@@ -565,11 +769,12 @@ func (q *Queries) UpdatePost(ctx context.Context, arg UpdatePostParams) (Post, e
 		arg.PostType,
 		arg.UserID,
 		arg.MaxVolunteers,
-		arg.CurrentVolunteers,
 		arg.CategoryID,
 		arg.LocationLat,
 		arg.LocationLng,
 		arg.AddressText,
+		arg.ID,
+		arg.UpdatedAt,
 	)
 	var i Post
 	err := row.Scan(
@@ -589,6 +794,7 @@ func (q *Queries) UpdatePost(ctx context.Context, arg UpdatePostParams) (Post, e
 		&i.AddressText,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SearchVector,
 	)
 	return i, err
 }
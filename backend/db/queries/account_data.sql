@@ -0,0 +1,35 @@
+-- name: ListUserPostImages :many
+-- Images attached to posts the user wrote, for data export and purging.
+SELECT pi.* FROM post_images pi
+JOIN posts p ON p.id = pi.post_id
+WHERE p.user_id = $1
+ORDER BY pi.post_id, pi.position;
+
+-- name: ListUserPostPreviewUrls :many
+-- Preview images of the user's posts, for purging.
+SELECT preview_url FROM posts
+WHERE user_id = $1 AND preview_url IS NOT NULL;
+
+-- name: DeleteUserPostImages :exec
+DELETE FROM post_images
+WHERE post_id IN (SELECT id FROM posts WHERE user_id = $1);
+
+-- name: ClearUserPostPreviews :exec
+-- Drops preview images of the user's posts whose objects are being purged.
+UPDATE posts
+SET preview_url = NULL
+WHERE user_id = $1 AND preview_url IS NOT NULL;
+
+-- name: ListUserShiftSignups :many
+-- Shifts the user signed up for, with their check-ins, for data export.
+SELECT ss.*, s.post_id, s.starts_at, s.ends_at
+FROM shift_signups ss
+JOIN post_volunteers pv ON pv.id = ss.volunteer_id
+JOIN post_shifts s ON s.id = ss.shift_id
+WHERE pv.user_id = $1
+ORDER BY s.starts_at;
+
+-- name: ListUserVolunteering :many
+SELECT * FROM post_volunteers
+WHERE user_id = $1
+ORDER BY created_at DESC;
@@ -0,0 +1,44 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (
+    user_id,
+    name,
+    prefix,
+    key_hash,
+    scopes,
+    mfa_verified,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: ListUserAPIKeys :many
+SELECT * FROM api_keys
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: GetActiveAPIKey :one
+-- Used by AuthMiddleware for X-API-Key requests: fails with no rows when the
+-- key is unknown, revoked or expired.
+SELECT k.id, k.user_id, k.scopes, k.mfa_verified, u.role FROM api_keys k
+JOIN users u ON u.id = k.user_id
+WHERE k.key_hash = $1
+  AND k.revoked_at IS NULL
+  AND k.expires_at > CURRENT_TIMESTAMP
+  AND u.deleted_at IS NULL;
+
+-- name: TouchAPIKey :exec
+UPDATE api_keys
+SET last_used_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND (last_used_at IS NULL OR last_used_at < CURRENT_TIMESTAMP - INTERVAL '1 minute');
+
+-- name: RevokeAPIKey :execrows
+UPDATE api_keys
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;
+
+-- name: RevokeUserAPIKeys :exec
+UPDATE api_keys
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE user_id = $1 AND revoked_at IS NULL;
@@ -0,0 +1,107 @@
+-- name: ListPostsNearby :many
+-- Posts within radius_m metres of (lat, lng), nearest first.
+SELECT
+    p.id,
+    p.title,
+    p.status,
+    p.priority,
+    p.post_type,
+    p.category_id,
+    p.preview_url,
+    p.location_lat,
+    p.location_lng,
+    p.address_text,
+    p.created_at,
+    earth_distance(ll_to_earth(sqlc.arg(lat)::float8, sqlc.arg(lng)::float8), ll_to_earth(p.location_lat, p.location_lng))::float8 AS distance_m
+FROM posts p
+WHERE p.location_lat IS NOT NULL
+  AND earth_box(ll_to_earth(sqlc.arg(lat)::float8, sqlc.arg(lng)::float8), sqlc.arg(radius_m)::float8) @> ll_to_earth(p.location_lat, p.location_lng)
+  AND earth_distance(ll_to_earth(sqlc.arg(lat)::float8, sqlc.arg(lng)::float8), ll_to_earth(p.location_lat, p.location_lng)) <= sqlc.arg(radius_m)::float8
+  AND (sqlc.narg(status)::post_status IS NULL OR p.status = sqlc.narg(status))
+  AND (sqlc.narg(priority)::post_priority IS NULL OR p.priority = sqlc.narg(priority))
+  AND (sqlc.narg(post_type)::post_type IS NULL OR p.post_type = sqlc.narg(post_type))
+  AND (sqlc.narg(category_id)::uuid IS NULL OR p.category_id = sqlc.narg(category_id))
+ORDER BY distance_m, p.id
+LIMIT sqlc.arg(result_limit);
+
+-- name: ListPostsInBounds :many
+-- Posts inside the box, nearest to (lat, lng) first.
+SELECT
+    p.id,
+    p.title,
+    p.status,
+    p.priority,
+    p.post_type,
+    p.category_id,
+    p.preview_url,
+    p.location_lat,
+    p.location_lng,
+    p.address_text,
+    p.created_at,
+    earth_distance(ll_to_earth(sqlc.arg(lat)::float8, sqlc.arg(lng)::float8), ll_to_earth(p.location_lat, p.location_lng))::float8 AS distance_m
+FROM posts p
+WHERE p.location_lat IS NOT NULL
+  AND box(point(sqlc.arg(min_lng)::float8, sqlc.arg(min_lat)::float8), point(sqlc.arg(max_lng)::float8, sqlc.arg(max_lat)::float8)) @> point(p.location_lng, p.location_lat)
+  AND (sqlc.narg(status)::post_status IS NULL OR p.status = sqlc.narg(status))
+  AND (sqlc.narg(priority)::post_priority IS NULL OR p.priority = sqlc.narg(priority))
+  AND (sqlc.narg(post_type)::post_type IS NULL OR p.post_type = sqlc.narg(post_type))
+  AND (sqlc.narg(category_id)::uuid IS NULL OR p.category_id = sqlc.narg(category_id))
+ORDER BY distance_m, p.id
+LIMIT sqlc.arg(result_limit);
+
+-- name: ClusterPostsInBounds :many
+-- Groups the posts inside the box into grid cells of cell_size degrees.
+-- post_id is the newest post of the cell, for cells holding a single post.
+-- At most max_clusters cells are returned, the fullest first.
+SELECT
+    count(*) AS post_count,
+    avg(p.location_lat)::float8 AS lat,
+    avg(p.location_lng)::float8 AS lng,
+    min(p.location_lat)::float8 AS min_lat,
+    min(p.location_lng)::float8 AS min_lng,
+    max(p.location_lat)::float8 AS max_lat,
+    max(p.location_lng)::float8 AS max_lng,
+    (array_agg(p.id ORDER BY p.created_at DESC))[1]::uuid AS post_id
+FROM posts p
+WHERE p.location_lat IS NOT NULL
+  AND box(point(sqlc.arg(min_lng)::float8, sqlc.arg(min_lat)::float8), point(sqlc.arg(max_lng)::float8, sqlc.arg(max_lat)::float8)) @> point(p.location_lng, p.location_lat)
+  AND (sqlc.narg(status)::post_status IS NULL OR p.status = sqlc.narg(status))
+  AND (sqlc.narg(priority)::post_priority IS NULL OR p.priority = sqlc.narg(priority))
+  AND (sqlc.narg(post_type)::post_type IS NULL OR p.post_type = sqlc.narg(post_type))
+  AND (sqlc.narg(category_id)::uuid IS NULL OR p.category_id = sqlc.narg(category_id))
+GROUP BY floor(p.location_lng / sqlc.arg(cell_size)::float8), floor(p.location_lat / sqlc.arg(cell_size)::float8)
+ORDER BY post_count DESC
+LIMIT sqlc.arg(max_clusters);
+
+-- name: ListPostFeatures :many
+-- Located posts for GeoJSON and vector tile export, newest first. The box
+-- is optional; pass all four corners or none.
+SELECT
+    p.id,
+    p.title,
+    p.status,
+    p.priority,
+    p.post_type,
+    p.category_id,
+    c.name AS category_name,
+    p.address_text,
+    p.location_lat,
+    p.location_lng,
+    p.created_at,
+    p.updated_at
+FROM posts p
+LEFT JOIN categories c ON c.id = p.category_id
+WHERE p.location_lat IS NOT NULL
+  AND (
+    sqlc.narg(min_lng)::float8 IS NULL
+    OR box(point(sqlc.narg(min_lng)::float8, sqlc.narg(min_lat)::float8), point(sqlc.narg(max_lng)::float8, sqlc.narg(max_lat)::float8)) @> point(p.location_lng, p.location_lat)
+  )
+  AND (sqlc.narg(status)::post_status IS NULL OR p.status = sqlc.narg(status))
+  AND (sqlc.narg(priority)::post_priority IS NULL OR p.priority = sqlc.narg(priority))
+  AND (sqlc.narg(post_type)::post_type IS NULL OR p.post_type = sqlc.narg(post_type))
+  AND (sqlc.narg(category_id)::uuid IS NULL OR p.category_id = sqlc.narg(category_id))
+  AND (sqlc.narg(author_id)::uuid IS NULL OR p.user_id = sqlc.narg(author_id))
+  AND (sqlc.narg(created_after)::timestamptz IS NULL OR p.created_at >= sqlc.narg(created_after))
+  AND (sqlc.narg(created_before)::timestamptz IS NULL OR p.created_at < sqlc.narg(created_before))
+ORDER BY p.created_at DESC, p.id
+LIMIT sqlc.arg(max_features);
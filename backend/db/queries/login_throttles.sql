@@ -0,0 +1,60 @@
+-- name: GetLoginThrottles :many
+SELECT * FROM login_throttles
+WHERE throttle_key = ANY(sqlc.arg(keys)::text[]);
+
+-- name: RecordLoginFailure :one
+-- Counts a failed login for the key. The count starts over when the
+-- previous failure is more than a day old.
+INSERT INTO login_throttles (throttle_key, failed_attempts, last_failed_at)
+VALUES ($1, 1, CURRENT_TIMESTAMP)
+ON CONFLICT (throttle_key) DO UPDATE
+SET
+    failed_attempts = CASE
+        WHEN login_throttles.last_failed_at < CURRENT_TIMESTAMP - INTERVAL '1 day' THEN 1
+        ELSE login_throttles.failed_attempts + 1
+    END,
+    last_failed_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: BlockLoginThrottle :exec
+UPDATE login_throttles
+SET blocked_until = $2
+WHERE throttle_key = $1;
+
+-- name: ResetLoginThrottle :exec
+DELETE FROM login_throttles
+WHERE throttle_key = $1;
+
+-- name: CreateAccountLockout :one
+INSERT INTO account_lockouts (
+    user_id,
+    ip_address,
+    failed_attempts,
+    locked_until
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetActiveAccountLockout :one
+SELECT * FROM account_lockouts
+WHERE user_id = $1
+  AND unlocked_at IS NULL
+  AND locked_until > CURRENT_TIMESTAMP
+ORDER BY locked_until DESC
+LIMIT 1;
+
+-- name: ListAccountLockouts :many
+SELECT * FROM account_lockouts
+WHERE user_id = $1
+ORDER BY locked_at DESC;
+
+-- name: UnlockAccount :execrows
+-- Lifts every lockout of the user that is still in effect.
+UPDATE account_lockouts
+SET
+    unlocked_at = CURRENT_TIMESTAMP,
+    unlocked_by = sqlc.arg(unlocked_by)
+WHERE user_id = sqlc.arg(user_id)
+  AND unlocked_at IS NULL
+  AND locked_until > CURRENT_TIMESTAMP;
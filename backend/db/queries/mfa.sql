@@ -0,0 +1,54 @@
+-- name: UpsertPendingUserMFA :one
+-- Starts (or restarts) enrollment. Returns no rows when MFA is already
+-- enabled, so an active secret is never overwritten.
+INSERT INTO user_mfa (user_id, secret)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET
+    secret = EXCLUDED.secret,
+    last_used_step = NULL,
+    created_at = CURRENT_TIMESTAMP
+WHERE user_mfa.enabled_at IS NULL
+RETURNING *;
+
+-- name: GetUserMFA :one
+SELECT * FROM user_mfa
+WHERE user_id = $1;
+
+-- name: EnableUserMFA :one
+UPDATE user_mfa
+SET
+    enabled_at = CURRENT_TIMESTAMP,
+    last_used_step = $2
+WHERE user_id = $1 AND enabled_at IS NULL
+RETURNING *;
+
+-- name: UseTOTPStep :execrows
+-- Records the step of an accepted code. Affects no rows when the same or a
+-- later step was already used, which means the code is being replayed.
+UPDATE user_mfa
+SET last_used_step = sqlc.arg(step)
+WHERE user_id = sqlc.arg(user_id)
+  AND enabled_at IS NOT NULL
+  AND (last_used_step IS NULL OR last_used_step < sqlc.arg(step));
+
+-- name: DeleteUserMFA :exec
+DELETE FROM user_mfa
+WHERE user_id = $1;
+
+-- name: CreateMFARecoveryCode :exec
+INSERT INTO mfa_recovery_codes (user_id, code_hash)
+VALUES ($1, $2);
+
+-- name: DeleteMFARecoveryCodes :exec
+DELETE FROM mfa_recovery_codes
+WHERE user_id = $1;
+
+-- name: UseMFARecoveryCode :execrows
+UPDATE mfa_recovery_codes
+SET used_at = CURRENT_TIMESTAMP
+WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL;
+
+-- name: CountUnusedMFARecoveryCodes :one
+SELECT COUNT(*) FROM mfa_recovery_codes
+WHERE user_id = $1 AND used_at IS NULL;
@@ -0,0 +1,44 @@
+-- name: CreateOIDCAuthRequest :exec
+INSERT INTO oidc_auth_requests (
+    state,
+    provider,
+    nonce,
+    code_verifier,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5
+);
+
+-- name: ConsumeOIDCAuthRequest :one
+-- Each state can complete exactly one login.
+DELETE FROM oidc_auth_requests
+WHERE state = $1
+  AND provider = $2
+  AND expires_at > CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: DeleteExpiredOIDCAuthRequests :exec
+DELETE FROM oidc_auth_requests
+WHERE expires_at <= CURRENT_TIMESTAMP;
+
+-- name: GetUserIdentity :one
+SELECT * FROM user_identities
+WHERE provider = $1 AND subject = $2;
+
+-- name: CreateUserIdentity :one
+INSERT INTO user_identities (
+    user_id,
+    provider,
+    subject,
+    email
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: TouchUserIdentity :exec
+UPDATE user_identities
+SET
+    email = $2,
+    last_login_at = CURRENT_TIMESTAMP
+WHERE id = $1;
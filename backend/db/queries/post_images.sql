@@ -0,0 +1,38 @@
+-- name: ListPostImages :many
+SELECT * FROM post_images
+WHERE post_id = $1
+ORDER BY position;
+
+-- name: DeletePostImage :one
+-- Removes an image and closes the gap it leaves in the order.
+WITH deleted AS (
+    DELETE FROM post_images
+    WHERE id = sqlc.arg(id) AND post_id = sqlc.arg(post_id)
+    RETURNING id, post_id, image_url, created_at, position
+), shifted AS (
+    UPDATE post_images
+    SET position = post_images.position - 1
+    FROM deleted
+    WHERE post_images.post_id = deleted.post_id AND post_images.position > deleted.position
+)
+SELECT id, post_id, image_url, created_at, position FROM deleted;
+
+-- name: ReorderPostImages :execrows
+-- Gives the images the positions of their IDs in image_ids.
+UPDATE post_images
+SET position = o.ord - 1
+FROM unnest(sqlc.arg(image_ids)::uuid[]) WITH ORDINALITY AS o(id, ord)
+WHERE post_images.id = o.id AND post_images.post_id = sqlc.arg(post_id);
+
+-- name: SyncPostPreview :exec
+-- Points the post's preview at its primary image, or clears it when the
+-- post has no images left.
+UPDATE posts
+SET preview_url = (
+        SELECT pi.image_url FROM post_images pi
+        WHERE pi.post_id = posts.id
+        ORDER BY pi.position
+        LIMIT 1
+    ),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
@@ -0,0 +1,35 @@
+-- name: CreatePostStatusHistory :one
+-- Records the status a post was created with.
+INSERT INTO post_status_history (post_id, to_status, changed_by, comment)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: TransitionPostStatus :one
+-- Moves a post to to_status and records the change, but only while the post
+-- is still in from_status. Returns no rows when another change got there first.
+WITH updated AS (
+    UPDATE posts
+    SET status = sqlc.arg(to_status), updated_at = CURRENT_TIMESTAMP
+    WHERE id = sqlc.arg(post_id) AND status = sqlc.arg(from_status)
+    RETURNING id, status
+)
+INSERT INTO post_status_history (post_id, from_status, to_status, changed_by, comment)
+SELECT updated.id, sqlc.arg(from_status), updated.status, sqlc.arg(changed_by)::uuid, sqlc.narg(comment)::text
+FROM updated
+RETURNING *;
+
+-- name: ListPostStatusHistory :many
+SELECT
+    h.id,
+    h.post_id,
+    h.from_status,
+    h.to_status,
+    h.changed_by,
+    h.comment,
+    h.created_at,
+    u.first_name AS changed_by_first_name,
+    u.last_name AS changed_by_last_name
+FROM post_status_history h
+LEFT JOIN users u ON u.id = h.changed_by
+WHERE h.post_id = $1
+ORDER BY h.created_at, h.id;
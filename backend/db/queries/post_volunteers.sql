@@ -0,0 +1,104 @@
+-- name: CreatePostVolunteer :one
+-- Applies to a post as a pending volunteer. Inserts nothing when the post's
+-- category does not take volunteers.
+INSERT INTO post_volunteers (user_id, post_id, notes)
+SELECT sqlc.arg(user_id)::uuid, p.id, sqlc.narg(notes)::text
+FROM posts p
+JOIN categories c ON c.id = p.category_id
+WHERE p.id = sqlc.arg(post_id) AND c.can_volunteer
+RETURNING *;
+
+-- name: GetPostVolunteer :one
+SELECT * FROM post_volunteers WHERE post_id = $1 AND user_id = $2;
+
+-- name: WithdrawPostVolunteer :one
+-- The volunteer leaves a post they applied to or were approved on.
+UPDATE post_volunteers SET status = 'withdrawn', updated_at = CURRENT_TIMESTAMP
+WHERE post_id = $1 AND user_id = $2 AND status IN ('pending', 'approved')
+RETURNING *;
+
+-- name: CompletePostVolunteer :one
+UPDATE post_volunteers SET status = 'completed', updated_at = CURRENT_TIMESTAMP
+WHERE post_id = $1 AND user_id = $2 AND status = 'approved'
+RETURNING *;
+
+-- name: MarkPostVolunteerNoShow :one
+UPDATE post_volunteers SET status = 'no_show', updated_at = CURRENT_TIMESTAMP
+WHERE post_id = $1 AND user_id = $2 AND status = 'approved'
+RETURNING *;
+
+-- name: CountPostVolunteersByStatus :many
+SELECT pv.status, COUNT(*) AS count
+FROM post_volunteers pv
+JOIN users u ON u.id = pv.user_id
+WHERE pv.post_id = $1 AND u.deleted_at IS NULL
+GROUP BY pv.status;
+
+-- name: ListPostVolunteerPage :many
+-- A post's volunteers with their profiles and privacy settings. Keyset
+-- pagination on (created_at, id), like ListPosts.
+SELECT
+    pv.id,
+    pv.user_id,
+    pv.post_id,
+    pv.status,
+    pv.notes,
+    pv.created_at,
+    pv.updated_at,
+    u.first_name,
+    u.last_name,
+    u.profile_url,
+    u.phone,
+    u.email,
+    ps.phone_visibility,
+    ps.email_visibility
+FROM post_volunteers pv
+JOIN users u ON u.id = pv.user_id
+LEFT JOIN user_privacy_settings ps ON ps.user_id = pv.user_id
+WHERE pv.post_id = sqlc.arg(post_id) AND u.deleted_at IS NULL
+  AND (sqlc.narg(status)::volunteer_status IS NULL OR pv.status = sqlc.narg(status))
+  AND (
+    sqlc.narg(cursor_created_at)::timestamptz IS NULL
+    OR (sqlc.arg(sort_asc)::boolean AND (pv.created_at, pv.id) > (sqlc.narg(cursor_created_at), sqlc.narg(cursor_id)::uuid))
+    OR (NOT sqlc.arg(sort_asc)::boolean AND (pv.created_at, pv.id) < (sqlc.narg(cursor_created_at), sqlc.narg(cursor_id)::uuid))
+  )
+ORDER BY
+    CASE WHEN sqlc.arg(sort_asc)::boolean THEN pv.created_at END ASC,
+    CASE WHEN sqlc.arg(sort_asc)::boolean THEN pv.id END ASC,
+    CASE WHEN NOT sqlc.arg(sort_asc)::boolean THEN pv.created_at END DESC,
+    CASE WHEN NOT sqlc.arg(sort_asc)::boolean THEN pv.id END DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: ListUserVolunteeringPage :many
+-- The posts a user volunteered on with their organizers. Keyset pagination on
+-- (created_at, id), like ListPosts.
+SELECT
+    pv.id,
+    pv.user_id,
+    pv.post_id,
+    pv.status,
+    pv.notes,
+    pv.created_at,
+    pv.updated_at,
+    p.title AS post_title,
+    p.status AS post_status,
+    p.preview_url AS post_preview_url,
+    p.user_id AS organizer_id,
+    o.first_name AS organizer_first_name,
+    o.last_name AS organizer_last_name
+FROM post_volunteers pv
+JOIN posts p ON p.id = pv.post_id
+LEFT JOIN users o ON o.id = p.user_id AND o.deleted_at IS NULL
+WHERE pv.user_id = sqlc.arg(user_id)
+  AND (sqlc.narg(status)::volunteer_status IS NULL OR pv.status = sqlc.narg(status))
+  AND (
+    sqlc.narg(cursor_created_at)::timestamptz IS NULL
+    OR (sqlc.arg(sort_asc)::boolean AND (pv.created_at, pv.id) > (sqlc.narg(cursor_created_at), sqlc.narg(cursor_id)::uuid))
+    OR (NOT sqlc.arg(sort_asc)::boolean AND (pv.created_at, pv.id) < (sqlc.narg(cursor_created_at), sqlc.narg(cursor_id)::uuid))
+  )
+ORDER BY
+    CASE WHEN sqlc.arg(sort_asc)::boolean THEN pv.created_at END ASC,
+    CASE WHEN sqlc.arg(sort_asc)::boolean THEN pv.id END ASC,
+    CASE WHEN NOT sqlc.arg(sort_asc)::boolean THEN pv.created_at END DESC,
+    CASE WHEN NOT sqlc.arg(sort_asc)::boolean THEN pv.id END DESC
+LIMIT sqlc.arg(page_size);
@@ -1,7 +1,11 @@
 -- name: GetUserPosts :many
+-- Every post of the user, for the data export. Listings page through
+-- ListPosts instead.
 SELECT * FROM posts WHERE user_id = $1 ORDER BY created_at DESC;
 
 -- name: ListPosts :many
+-- Keyset pagination on (created_at, id): the cursor is the last row of the
+-- previous page. NULL filters match every post.
 SELECT
     p.id,
     p.title,
@@ -20,20 +24,33 @@ SELECT
     p.created_at,
     p.updated_at,
     (
-        SELECT json_agg(pi.image_url)
+        SELECT json_agg(pi.image_url ORDER BY pi.position)
         FROM post_images pi
         WHERE pi.post_id = p.id
-    ) AS images,
-    (
-        SELECT json_agg(pv.*)
-        FROM post_volunteers pv
-        WHERE pv.post_id = p.id
-    ) AS volunteers
+    ) AS images
 FROM posts p
-ORDER BY p.created_at DESC;
+WHERE (sqlc.narg(status)::post_status IS NULL OR p.status = sqlc.narg(status))
+  AND (sqlc.narg(priority)::post_priority IS NULL OR p.priority = sqlc.narg(priority))
+  AND (sqlc.narg(post_type)::post_type IS NULL OR p.post_type = sqlc.narg(post_type))
+  AND (sqlc.narg(category_id)::uuid IS NULL OR p.category_id = sqlc.narg(category_id))
+  AND (sqlc.narg(author_id)::uuid IS NULL OR p.user_id = sqlc.narg(author_id))
+  AND (sqlc.narg(created_after)::timestamptz IS NULL OR p.created_at >= sqlc.narg(created_after))
+  AND (sqlc.narg(created_before)::timestamptz IS NULL OR p.created_at < sqlc.narg(created_before))
+  AND (
+    sqlc.narg(cursor_created_at)::timestamptz IS NULL
+    OR (sqlc.arg(sort_asc)::boolean AND (p.created_at, p.id) > (sqlc.narg(cursor_created_at), sqlc.narg(cursor_id)::uuid))
+    OR (NOT sqlc.arg(sort_asc)::boolean AND (p.created_at, p.id) < (sqlc.narg(cursor_created_at), sqlc.narg(cursor_id)::uuid))
+  )
+ORDER BY
+    CASE WHEN sqlc.arg(sort_asc)::boolean THEN p.created_at END ASC,
+    CASE WHEN sqlc.arg(sort_asc)::boolean THEN p.id END ASC,
+    CASE WHEN NOT sqlc.arg(sort_asc)::boolean THEN p.created_at END DESC,
+    CASE WHEN NOT sqlc.arg(sort_asc)::boolean THEN p.id END DESC
+LIMIT sqlc.arg(page_size);
 
 -- name: CreatePost :one
 INSERT INTO posts (
+    id,
     title,
     description,
     status,
@@ -48,7 +65,7 @@ INSERT INTO posts (
     location_lng,
     address_text
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
 ) RETURNING *;
 
 -- name: GetPost :one
@@ -70,15 +87,10 @@ SELECT
     p.created_at,
     p.updated_at,
     (
-        SELECT json_agg(pi.image_url)
+        SELECT json_agg(pi.image_url ORDER BY pi.position)
         FROM post_images pi
         WHERE pi.post_id = p.id
-    ) AS images,
-    (
-        SELECT json_agg(pv.*)
-        FROM post_volunteers pv
-        WHERE pv.post_id = p.id
-    ) AS volunteers
+    ) AS images
 FROM posts p
 WHERE p.id = $1;
 
@@ -86,42 +98,74 @@ WHERE p.id = $1;
 DELETE FROM posts WHERE id = $1;
 
 -- name: UpdatePost :one
+-- NULL keeps the current value. Only updates the post while updated_at is
+-- still the value the caller read.
 UPDATE posts SET
-    title = COALESCE($2, title),
-    description = COALESCE($3, description),
-    status = COALESCE($4, status),
-    priority = COALESCE($5, priority),
-    preview_url = COALESCE($6, preview_url),
-    post_type = COALESCE($7, post_type),
-    user_id = COALESCE($8, user_id),
-    max_volunteers = COALESCE($9, max_volunteers),
-    current_volunteers = COALESCE($10, current_volunteers),
-    category_id = COALESCE($11, category_id),
-    location_lat = COALESCE($12, location_lat),
-    location_lng = COALESCE($13, location_lng),
-    address_text = COALESCE($14, address_text),
+    title = COALESCE(sqlc.narg(title), title),
+    description = COALESCE(sqlc.narg(description), description),
+    status = COALESCE(sqlc.arg(status), status),
+    priority = COALESCE(sqlc.arg(priority), priority),
+    preview_url = COALESCE(sqlc.narg(preview_url), preview_url),
+    post_type = COALESCE(sqlc.arg(post_type), post_type),
+    user_id = COALESCE(sqlc.arg(user_id), user_id),
+    max_volunteers = COALESCE(sqlc.narg(max_volunteers), max_volunteers),
+    category_id = COALESCE(sqlc.narg(category_id), category_id),
+    location_lat = COALESCE(sqlc.narg(location_lat), location_lat),
+    location_lng = COALESCE(sqlc.narg(location_lng), location_lng),
+    address_text = COALESCE(sqlc.narg(address_text), address_text),
     updated_at = CURRENT_TIMESTAMP
-WHERE id = $1
+WHERE id = sqlc.arg(id) AND updated_at = sqlc.arg(updated_at)
 RETURNING *;
 
--- name: ListPostVolunteers :many
-SELECT * FROM post_volunteers ORDER BY created_at DESC;
+-- name: PatchPost :one
+-- Applies a JSON Merge Patch. NULL leaves a required column unchanged; the
+-- optional columns are only replaced when their set_* flag is true, so they
+-- can also be cleared. Only applies while updated_at is still the value the
+-- caller read.
+UPDATE posts SET
+    title = COALESCE(sqlc.narg(title), title),
+    description = COALESCE(sqlc.narg(description), description),
+    priority = COALESCE(sqlc.narg(priority), priority),
+    post_type = COALESCE(sqlc.narg(post_type), post_type),
+    max_volunteers = COALESCE(sqlc.narg(max_volunteers), max_volunteers),
+    preview_url = CASE WHEN sqlc.arg(set_preview_url)::boolean THEN sqlc.narg(preview_url) ELSE preview_url END,
+    category_id = CASE WHEN sqlc.arg(set_category_id)::boolean THEN sqlc.narg(category_id) ELSE category_id END,
+    location_lat = CASE WHEN sqlc.arg(set_location)::boolean THEN sqlc.narg(location_lat) ELSE location_lat END,
+    location_lng = CASE WHEN sqlc.arg(set_location)::boolean THEN sqlc.narg(location_lng) ELSE location_lng END,
+    address_text = CASE WHEN sqlc.arg(set_address_text)::boolean THEN sqlc.narg(address_text) ELSE address_text END,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id) AND updated_at = sqlc.arg(updated_at)
+RETURNING *;
 
 -- name: DeletePostVolunteer :exec
 DELETE FROM post_volunteers WHERE post_id = $1 AND user_id = $2;
 
 -- name: GetUserStats :one
+-- volunteered_hours only counts the part of each check-in that falls inside
+-- its shift.
 SELECT
     (SELECT COUNT(*) FROM posts p WHERE p.user_id = $1) AS user_posts_count,
     (SELECT COUNT(*) FROM post_volunteers p_v WHERE p_v.user_id = $1) AS user_volunteer_count,
-    (SELECT COUNT(*) FROM posts po WHERE po.user_id = $1 AND status = 'Шийдвэрлэгдсэн') AS approved_posts;
+    (SELECT COUNT(*) FROM posts po WHERE po.user_id = $1 AND status = 'Шийдвэрлэгдсэн') AS approved_posts,
+    (
+        SELECT EXTRACT(EPOCH FROM COALESCE(SUM(GREATEST(
+            LEAST(ss.checked_out_at, s.ends_at) - GREATEST(ss.checked_in_at, s.starts_at),
+            INTERVAL '0'
+        )), INTERVAL '0')) / 3600
+        FROM shift_signups ss
+        JOIN post_shifts s ON s.id = ss.shift_id
+        JOIN post_volunteers pv ON pv.id = ss.volunteer_id
+        WHERE pv.user_id = $1 AND ss.checked_out_at IS NOT NULL
+    )::float8 AS volunteered_hours;
 
 
 -- name: ApproveVolunteer :one
-UPDATE post_volunteers SET status = 'approved', updated_at = CURRENT_TIMESTAMP WHERE post_id = $1 AND user_id = $2 RETURNING *;
+-- Only pending applications are decided; others are left unchanged.
+UPDATE post_volunteers SET status = 'approved', updated_at = CURRENT_TIMESTAMP WHERE post_id = $1 AND user_id = $2 AND status = 'pending' RETURNING *;
 
 -- name: RejectVolunteer :one
-UPDATE post_volunteers SET status = 'rejected', updated_at = CURRENT_TIMESTAMP WHERE post_id = $1 AND user_id = $2 RETURNING *;
+-- Only pending applications are decided; others are left unchanged.
+UPDATE post_volunteers SET status = 'rejected', updated_at = CURRENT_TIMESTAMP WHERE post_id = $1 AND user_id = $2 AND status = 'pending' RETURNING *;
 
 -- name: GetCategoryName :one
 SELECT name from categories WHERE id = $1;
@@ -134,5 +178,35 @@ INSERT INTO categories(name, description, endpoint, can_volunteer)
 VALUES ($1, $2, $3, $4) RETURNING *;
 
 -- name: CreatePostImage :one
-INSERT INTO post_images(post_id, image_url)
-VALUES ($1, $2) RETURNING *;
+-- Appends an image after the post's existing images.
+INSERT INTO post_images(post_id, image_url, position)
+VALUES ($1, $2, (SELECT COALESCE(MAX(position) + 1, 0) FROM post_images WHERE post_id = $1)) RETURNING *;
+
+-- name: SearchPosts :many
+-- Ranks posts matching a to_tsquery expression in the mongolian text search
+-- configuration. Matches in the highlights are wrapped in chr(2) and chr(3)
+-- so callers can escape the text before marking them up.
+SELECT
+    p.id,
+    p.title,
+    p.status,
+    p.priority,
+    p.post_type,
+    p.category_id,
+    p.address_text,
+    p.created_at,
+    ts_rank_cd(p.search_vector, to_tsquery('mongolian', sqlc.arg(query)))::real AS rank,
+    ts_headline('mongolian', p.title, to_tsquery('mongolian', sqlc.arg(query)),
+        'HighlightAll=true, StartSel=' || chr(2) || ', StopSel=' || chr(3))::text AS title_highlight,
+    ts_headline('mongolian', p.description, to_tsquery('mongolian', sqlc.arg(query)),
+        'MaxFragments=2, MaxWords=25, MinWords=10, FragmentDelimiter=" … ", StartSel=' || chr(2) || ', StopSel=' || chr(3))::text AS snippet,
+    ts_headline('mongolian', coalesce(p.address_text, ''), to_tsquery('mongolian', sqlc.arg(query)),
+        'HighlightAll=true, StartSel=' || chr(2) || ', StopSel=' || chr(3))::text AS address_highlight
+FROM posts p
+WHERE p.search_vector @@ to_tsquery('mongolian', sqlc.arg(query))
+  AND (sqlc.narg(status)::post_status IS NULL OR p.status = sqlc.narg(status))
+  AND (sqlc.narg(priority)::post_priority IS NULL OR p.priority = sqlc.narg(priority))
+  AND (sqlc.narg(post_type)::post_type IS NULL OR p.post_type = sqlc.narg(post_type))
+  AND (sqlc.narg(category_id)::uuid IS NULL OR p.category_id = sqlc.narg(category_id))
+ORDER BY rank DESC, p.created_at DESC, p.id
+LIMIT sqlc.arg(result_limit);
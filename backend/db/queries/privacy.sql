@@ -0,0 +1,33 @@
+-- name: ListUserPrivacySettings :many
+SELECT * FROM user_privacy_settings
+WHERE user_id = ANY(sqlc.arg(user_ids)::uuid[]);
+
+-- name: UpsertUserPrivacySettings :one
+INSERT INTO user_privacy_settings (user_id, phone_visibility, email_visibility)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id) DO UPDATE
+SET
+    phone_visibility = EXCLUDED.phone_visibility,
+    email_visibility = EXCLUDED.email_visibility,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: ListUserStats :many
+-- Public volunteer statistics for several users at once.
+SELECT
+    u.id AS user_id,
+    (SELECT COUNT(*) FROM posts p WHERE p.user_id = u.id) AS posts_count,
+    (SELECT COUNT(*) FROM post_volunteers pv WHERE pv.user_id = u.id) AS volunteer_count,
+    (SELECT COUNT(*) FROM post_volunteers pv WHERE pv.user_id = u.id AND pv.status = 'completed') AS completed_volunteer_count,
+    (SELECT COUNT(*) FROM posts p WHERE p.user_id = u.id AND p.status = 'Шийдвэрлэгдсэн') AS resolved_posts_count
+FROM users u
+WHERE u.id = ANY(sqlc.arg(user_ids)::uuid[]);
+
+-- name: ListVolunteersOfOrganizer :many
+-- Which of the given users volunteered (and were not rejected) on a post
+-- written by the organizer.
+SELECT DISTINCT pv.user_id FROM post_volunteers pv
+JOIN posts p ON p.id = pv.post_id
+WHERE p.user_id = sqlc.arg(organizer_id)
+  AND pv.user_id = ANY(sqlc.arg(user_ids)::uuid[])
+  AND pv.status IS DISTINCT FROM 'rejected';
@@ -0,0 +1,67 @@
+-- name: CreateSession :one
+INSERT INTO sessions (
+    user_id,
+    refresh_token_hash,
+    user_agent,
+    ip_address,
+    expires_at,
+    mfa_verified
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: GetSessionByRefreshTokenHash :one
+SELECT * FROM sessions
+WHERE refresh_token_hash = $1;
+
+-- name: GetSessionByPreviousTokenHash :one
+-- Matches a refresh token that has already been rotated away. Seeing one
+-- again means the token leaked, so the caller revokes the whole session.
+SELECT * FROM sessions
+WHERE previous_token_hash = $1;
+
+-- name: GetActiveSession :one
+-- Used by AuthMiddleware on every request: fails with no rows when the
+-- session was revoked, has expired or does not belong to the user.
+SELECT u.role, s.mfa_verified FROM sessions s
+JOIN users u ON u.id = s.user_id
+WHERE s.id = $1
+  AND s.user_id = $2
+  AND s.revoked_at IS NULL
+  AND s.expires_at > CURRENT_TIMESTAMP
+  AND u.deleted_at IS NULL;
+
+-- name: RotateSessionToken :one
+-- Replaces the refresh token hash only if it still matches the presented
+-- one, so two concurrent refreshes cannot both succeed.
+UPDATE sessions
+SET
+    previous_token_hash = refresh_token_hash,
+    refresh_token_hash = sqlc.arg(new_token_hash),
+    last_used_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)
+  AND refresh_token_hash = sqlc.arg(current_token_hash)
+  AND revoked_at IS NULL
+RETURNING *;
+
+-- name: RevokeSession :exec
+UPDATE sessions
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeUserSessions :exec
+UPDATE sessions
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE user_id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeOtherUserSessions :exec
+-- Revokes every session of the user except the one making the request.
+UPDATE sessions
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE user_id = $1 AND id <> $2 AND revoked_at IS NULL;
+
+-- name: MarkSessionMFAVerified :exec
+UPDATE sessions
+SET mfa_verified = TRUE
+WHERE id = $1;
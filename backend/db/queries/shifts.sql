@@ -0,0 +1,78 @@
+-- name: CreatePostShift :one
+INSERT INTO post_shifts (post_id, starts_at, ends_at, meeting_point, meeting_lat, meeting_lng, capacity)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: ListPostShifts :many
+SELECT * FROM post_shifts
+WHERE post_id = $1
+ORDER BY starts_at, id;
+
+-- name: GetPostShift :one
+SELECT * FROM post_shifts
+WHERE id = $1 AND post_id = $2;
+
+-- name: DeletePostShift :execrows
+DELETE FROM post_shifts
+WHERE id = $1 AND post_id = $2;
+
+-- name: CreateShiftSignup :one
+-- Signs a user up for a shift. Inserts nothing unless the user is an
+-- approved volunteer on the shift's post.
+INSERT INTO shift_signups (shift_id, volunteer_id)
+SELECT s.id, pv.id
+FROM post_shifts s
+JOIN post_volunteers pv ON pv.post_id = s.post_id
+WHERE s.id = sqlc.arg(shift_id) AND pv.user_id = sqlc.arg(user_id) AND pv.status = 'approved'
+RETURNING *;
+
+-- name: GetShiftSignup :one
+SELECT ss.* FROM shift_signups ss
+JOIN post_volunteers pv ON pv.id = ss.volunteer_id
+WHERE ss.shift_id = $1 AND pv.user_id = $2;
+
+-- name: ListShiftSignups :many
+SELECT ss.*, pv.user_id, u.first_name, u.last_name
+FROM shift_signups ss
+JOIN post_volunteers pv ON pv.id = ss.volunteer_id
+JOIN users u ON u.id = pv.user_id
+WHERE ss.shift_id = $1
+ORDER BY ss.created_at;
+
+-- name: DeleteShiftSignup :execrows
+-- Withdraws from a shift; a sign-up that was checked in to stays.
+DELETE FROM shift_signups
+WHERE id = $1 AND checked_in_at IS NULL;
+
+-- name: CheckInShift :one
+UPDATE shift_signups
+SET checked_in_at = CURRENT_TIMESTAMP,
+    check_in_lat = sqlc.narg(lat),
+    check_in_lng = sqlc.narg(lng)
+WHERE id = sqlc.arg(id) AND checked_in_at IS NULL
+RETURNING *;
+
+-- name: CheckOutShift :one
+UPDATE shift_signups
+SET checked_out_at = CURRENT_TIMESTAMP,
+    check_out_lat = sqlc.narg(lat),
+    check_out_lng = sqlc.narg(lng)
+WHERE id = sqlc.arg(id) AND checked_in_at IS NOT NULL AND checked_out_at IS NULL
+RETURNING *;
+
+-- name: CompleteShiftVolunteer :execrows
+-- Marks an approved volunteer completed once every shift they signed up for
+-- on the post has been checked out of. Callers only run it once the post has
+-- no shifts left to sign up for.
+UPDATE post_volunteers pv
+SET status = 'completed', updated_at = CURRENT_TIMESTAMP
+WHERE pv.id = $1 AND pv.status = 'approved'
+  AND NOT EXISTS (
+    SELECT 1 FROM shift_signups ss
+    WHERE ss.volunteer_id = pv.id AND ss.checked_out_at IS NULL
+  );
+
+-- name: DeleteOpenShiftSignups :execrows
+-- Frees the shift places of a volunteer who is leaving the post. Sign-ups that
+-- were checked in to are kept for their hours.
+DELETE FROM shift_signups WHERE volunteer_id = $1 AND checked_in_at IS NULL;
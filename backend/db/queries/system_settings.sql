@@ -0,0 +1,12 @@
+-- name: GetSystemSetting :one
+SELECT value FROM system_settings
+WHERE key = $1;
+
+-- name: UpsertSystemSetting :exec
+INSERT INTO system_settings (key, value, updated_by, updated_at)
+VALUES ($1, $2, $3, CURRENT_TIMESTAMP)
+ON CONFLICT (key) DO UPDATE
+SET
+    value = EXCLUDED.value,
+    updated_by = EXCLUDED.updated_by,
+    updated_at = CURRENT_TIMESTAMP;
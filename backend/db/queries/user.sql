@@ -12,14 +12,26 @@ WHERE email = $1;
 -- name: LoginRequest :one
 -- Specifically for authentication: fetches only necessary fields
 -- The application should then verify the password_hash
-SELECT id, email, password_hash, role FROM users
+SELECT id, email, password_hash, role, deleted_at FROM users
 WHERE email = $1;
 
 -- name: ListUsers :many
--- Selects all users. Consider adding pagination parameters (LIMIT, OFFSET)
--- for real-world applications if the user list can be large.
+-- Keyset pagination on (created_at, id), like ListPosts.
 SELECT * FROM users
-ORDER BY created_at DESC;
+WHERE deleted_at IS NULL
+  AND (sqlc.narg(role)::user_role IS NULL OR role = sqlc.narg(role))
+  AND (sqlc.narg(is_volunteering)::boolean IS NULL OR is_volunteering = sqlc.narg(is_volunteering))
+  AND (
+    sqlc.narg(cursor_created_at)::timestamptz IS NULL
+    OR (sqlc.arg(sort_asc)::boolean AND (created_at, id) > (sqlc.narg(cursor_created_at), sqlc.narg(cursor_id)::uuid))
+    OR (NOT sqlc.arg(sort_asc)::boolean AND (created_at, id) < (sqlc.narg(cursor_created_at), sqlc.narg(cursor_id)::uuid))
+  )
+ORDER BY
+    CASE WHEN sqlc.arg(sort_asc)::boolean THEN created_at END ASC,
+    CASE WHEN sqlc.arg(sort_asc)::boolean THEN id END ASC,
+    CASE WHEN NOT sqlc.arg(sort_asc)::boolean THEN created_at END DESC,
+    CASE WHEN NOT sqlc.arg(sort_asc)::boolean THEN id END DESC
+LIMIT sqlc.arg(page_size);
 
 -- name: CreateUser :one
 -- Inserts a new user and returns the newly created user record.
@@ -41,6 +53,7 @@ RETURNING *;
 -- name: UpdateUserDetails :one
 -- Updates general user details.
 -- Excludes password_hash and role, which might have separate update logic.
+-- Only updates the user while updated_at is still the value the caller read.
 UPDATE users
 SET
     first_name = $2,
@@ -48,15 +61,17 @@ SET
     phone = $4,
     is_volunteering = $5,
     profile_url = $6
-WHERE id = $1
+WHERE id = $1 AND updated_at = $7
 RETURNING *;
 
 -- name: UpdateUserEmail :one
 -- Specific query to update user email.
--- Requires careful handling in application (e.g., re-verification).
+-- Only called once the new address was confirmed through an email_change
+-- token, so the address is marked verified at the same time.
 UPDATE users
 SET
     email = $2,
+    email_verified_at = CURRENT_TIMESTAMP,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
 RETURNING *;
@@ -75,3 +90,62 @@ RETURNING *;
 -- :exec indicates it doesn't return rows.
 DELETE FROM users
 WHERE id = $1;
+
+-- name: GetUserRole :one
+-- Fetches only the role, used to authorize requests against the current
+-- role rather than the one embedded in the token.
+SELECT role FROM users
+WHERE id = $1;
+
+-- name: UpdateUserRole :one
+-- Promotes or demotes a user. Restricted to administrators in the application.
+UPDATE users
+SET
+    role = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
+
+-- name: MarkUserEmailVerified :one
+UPDATE users
+SET
+    email_verified_at = COALESCE(email_verified_at, CURRENT_TIMESTAMP),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
+
+-- name: SoftDeleteUser :one
+-- Marks the account deleted; the purge job removes it after the grace period.
+UPDATE users
+SET
+    deleted_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: RestoreUser :one
+-- Cancels a pending deletion while the account is still in its grace period.
+UPDATE users
+SET
+    deleted_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at > sqlc.arg(deleted_after)
+RETURNING *;
+
+-- name: ListUsersDueForPurge :many
+SELECT * FROM users
+WHERE deleted_at IS NOT NULL AND deleted_at <= sqlc.arg(deleted_before)
+ORDER BY deleted_at
+LIMIT sqlc.arg(batch_size);
+
+-- name: LockUserDueForPurge :one
+-- Locks a deleted account whose grace period is over, so it cannot be
+-- restored while it is being purged. Returns no rows once it was restored.
+SELECT * FROM users
+WHERE id = $1 AND deleted_at IS NOT NULL AND deleted_at <= sqlc.arg(deleted_before)
+FOR UPDATE;
+
+-- name: PurgeDeletedUser :execrows
+-- Deletes the user only while it is still deleted past the grace period.
+DELETE FROM users
+WHERE id = $1 AND deleted_at IS NOT NULL AND deleted_at <= sqlc.arg(deleted_before);
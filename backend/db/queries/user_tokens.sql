@@ -0,0 +1,37 @@
+-- name: CreateUserToken :one
+INSERT INTO user_tokens (
+    user_id,
+    purpose,
+    token_hash,
+    new_email,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: ConsumeUserToken :one
+-- Marks the token as used and returns it in one statement, so the same
+-- token can never be redeemed twice.
+UPDATE user_tokens
+SET consumed_at = CURRENT_TIMESTAMP
+WHERE token_hash = $1
+  AND purpose = $2
+  AND consumed_at IS NULL
+  AND expires_at > CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: GetActiveUserToken :one
+-- Looks a token up without redeeming it, so a request that fails
+-- validation does not burn the token.
+SELECT * FROM user_tokens
+WHERE token_hash = $1
+  AND purpose = $2
+  AND consumed_at IS NULL
+  AND expires_at > CURRENT_TIMESTAMP;
+
+-- name: InvalidateUserTokens :exec
+-- Expires every outstanding token of a purpose, e.g. before issuing a new one.
+UPDATE user_tokens
+SET consumed_at = CURRENT_TIMESTAMP
+WHERE user_id = $1 AND purpose = $2 AND consumed_at IS NULL;
@@ -0,0 +1,114 @@
+-- name: ListUserSkills :many
+SELECT skill FROM user_skills WHERE user_id = $1 ORDER BY skill;
+
+-- name: DeleteUserSkills :exec
+DELETE FROM user_skills WHERE user_id = $1;
+
+-- name: AddUserSkills :exec
+INSERT INTO user_skills (user_id, skill)
+SELECT sqlc.arg(user_id)::uuid, unnest(sqlc.arg(skills)::text[]);
+
+-- name: ListUserAvailability :many
+SELECT * FROM user_availability WHERE user_id = $1 ORDER BY weekday, start_time;
+
+-- name: DeleteUserAvailability :exec
+DELETE FROM user_availability WHERE user_id = $1;
+
+-- name: CreateUserAvailability :one
+INSERT INTO user_availability (user_id, weekday, start_time, end_time)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetVolunteerProfile :one
+SELECT * FROM volunteer_profiles WHERE user_id = $1;
+
+-- name: UpsertVolunteerProfile :one
+INSERT INTO volunteer_profiles (user_id, location_lat, location_lng)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id) DO UPDATE
+SET
+    location_lat = EXCLUDED.location_lat,
+    location_lng = EXCLUDED.location_lng,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: ListPostSkills :many
+SELECT skill FROM post_skills WHERE post_id = $1 ORDER BY skill;
+
+-- name: DeletePostSkills :exec
+DELETE FROM post_skills WHERE post_id = $1;
+
+-- name: AddPostSkills :exec
+INSERT INTO post_skills (post_id, skill)
+SELECT sqlc.arg(post_id)::uuid, unnest(sqlc.arg(skills)::text[]);
+
+-- name: ListSuggestedVolunteers :many
+-- Ranks volunteering users for a post. The score weighs the share of needed
+-- skills they have (0.5), the share of upcoming shifts that fit their weekly
+-- availability (0.3) and how close they are within max_distance_m (0.2).
+-- The author and users who already applied are left out.
+WITH post AS (
+    SELECT
+        p.id,
+        p.user_id,
+        p.location_lat,
+        p.location_lng,
+        (SELECT COUNT(*) FROM post_skills ps WHERE ps.post_id = p.id) AS needed_skills,
+        (SELECT COUNT(*) FROM post_shifts s WHERE s.post_id = p.id AND s.ends_at > CURRENT_TIMESTAMP) AS upcoming_shifts
+    FROM posts p
+    WHERE p.id = sqlc.arg(post_id)
+),
+candidates AS (
+    SELECT
+        u.id AS user_id,
+        u.first_name,
+        u.last_name,
+        u.profile_url,
+        ARRAY(
+            SELECT us.skill FROM user_skills us
+            JOIN post_skills ps ON ps.skill = us.skill AND ps.post_id = post.id
+            WHERE us.user_id = u.id
+            ORDER BY us.skill
+        )::text[] AS matched_skills,
+        (
+            SELECT COUNT(*) FROM post_shifts s
+            WHERE s.post_id = post.id AND s.ends_at > CURRENT_TIMESTAMP
+              AND s.starts_at::date = s.ends_at::date
+              AND EXISTS (
+                SELECT 1 FROM user_availability a
+                WHERE a.user_id = u.id
+                  AND a.weekday = EXTRACT(DOW FROM s.starts_at)
+                  AND a.start_time <= s.starts_at::time
+                  AND a.end_time >= s.ends_at::time
+              )
+        ) AS available_shifts,
+        d.distance_m,
+        post.needed_skills,
+        post.upcoming_shifts
+    FROM post
+    JOIN users u ON u.is_volunteering AND u.deleted_at IS NULL AND u.id IS DISTINCT FROM post.user_id
+    LEFT JOIN volunteer_profiles vp ON vp.user_id = u.id
+    LEFT JOIN LATERAL (
+        SELECT earth_distance(ll_to_earth(vp.location_lat, vp.location_lng), ll_to_earth(post.location_lat, post.location_lng)) AS distance_m
+        WHERE vp.location_lat IS NOT NULL AND post.location_lat IS NOT NULL
+    ) d ON true
+    WHERE NOT EXISTS (
+        SELECT 1 FROM post_volunteers pv WHERE pv.post_id = post.id AND pv.user_id = u.id
+    )
+)
+SELECT
+    c.user_id,
+    c.first_name,
+    c.last_name,
+    c.profile_url,
+    c.matched_skills,
+    c.available_shifts,
+    c.distance_m,
+    (
+        0.5 * cardinality(c.matched_skills)::float8 / GREATEST(c.needed_skills, 1)
+        + 0.3 * c.available_shifts::float8 / GREATEST(c.upcoming_shifts, 1)
+        + 0.2 * GREATEST(1 - COALESCE(c.distance_m, sqlc.arg(max_distance_m)::float8) / sqlc.arg(max_distance_m)::float8, 0)
+    )::float8 AS score
+FROM candidates c
+ORDER BY score DESC, c.user_id
+LIMIT sqlc.arg(result_limit);
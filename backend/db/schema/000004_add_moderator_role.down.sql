@@ -0,0 +1,13 @@
+-- PostgreSQL cannot drop a single enum value, so the type is rebuilt without it.
+UPDATE users SET role = 'USER' WHERE role = 'MODERATOR';
+
+ALTER TYPE user_role RENAME TO user_role_old;
+CREATE TYPE user_role AS ENUM ('ADMIN', 'USER');
+
+ALTER TABLE users ALTER COLUMN role DROP DEFAULT;
+ALTER TABLE users ALTER COLUMN role TYPE user_role USING role::text::user_role;
+ALTER TABLE users ALTER COLUMN role SET DEFAULT 'USER';
+
+DROP TYPE user_role_old;
+
+COMMENT ON COLUMN users.role IS 'User''s role in the system (admin or user, default: user)';
@@ -0,0 +1,3 @@
+ALTER TYPE user_role ADD VALUE IF NOT EXISTS 'MODERATOR';
+
+COMMENT ON COLUMN users.role IS 'User''s role in the system (ADMIN, MODERATOR or USER, default: USER)';
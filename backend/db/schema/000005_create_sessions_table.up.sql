@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS sessions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    refresh_token_hash TEXT NOT NULL UNIQUE,
+    previous_token_hash TEXT,
+    user_agent TEXT,
+    ip_address TEXT,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id);
+CREATE INDEX IF NOT EXISTS idx_sessions_previous_token_hash ON sessions(previous_token_hash);
+
+COMMENT ON COLUMN sessions.refresh_token_hash IS 'SHA-256 hash of the current refresh token; the token itself is never stored';
+COMMENT ON COLUMN sessions.previous_token_hash IS 'Hash of the refresh token replaced by the last rotation, used to detect token reuse';
+COMMENT ON COLUMN sessions.expires_at IS 'Absolute expiry of the session; rotation does not extend it';
+COMMENT ON COLUMN sessions.revoked_at IS 'Set on logout, credential change or detected token reuse';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_user_tokens_user_id_purpose;
+DROP TABLE IF EXISTS user_tokens;
+DROP TYPE IF EXISTS user_token_purpose;
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
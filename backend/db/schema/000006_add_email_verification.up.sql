@@ -0,0 +1,25 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ;
+
+-- Accounts created before verification existed keep working.
+UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL;
+
+COMMENT ON COLUMN users.email_verified_at IS 'Timestamp of when the user confirmed their email address (NULL until verified)';
+
+CREATE TYPE user_token_purpose AS ENUM ('email_verification', 'email_change', 'password_reset');
+
+CREATE TABLE IF NOT EXISTS user_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    purpose user_token_purpose NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    new_email VARCHAR(255),
+    expires_at TIMESTAMPTZ NOT NULL,
+    consumed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_tokens_user_id_purpose ON user_tokens(user_id, purpose);
+
+COMMENT ON COLUMN user_tokens.token_hash IS 'SHA-256 hash of the one-time token sent by email';
+COMMENT ON COLUMN user_tokens.new_email IS 'Address being confirmed, only set for email_change tokens';
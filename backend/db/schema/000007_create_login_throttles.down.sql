@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_account_lockouts_user_id;
+DROP TABLE IF EXISTS account_lockouts;
+DROP TABLE IF EXISTS login_throttles;
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS login_throttles (
+    throttle_key TEXT PRIMARY KEY,
+    failed_attempts INTEGER NOT NULL DEFAULT 0,
+    last_failed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    blocked_until TIMESTAMPTZ
+);
+
+COMMENT ON COLUMN login_throttles.throttle_key IS 'What the failures are counted for: ''email:<address>'' or ''ip:<address>''';
+COMMENT ON COLUMN login_throttles.failed_attempts IS 'Consecutive failed logins; restarts after a day without failures';
+COMMENT ON COLUMN login_throttles.blocked_until IS 'No login attempt for this key is evaluated before this time';
+
+CREATE TABLE IF NOT EXISTS account_lockouts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    ip_address TEXT,
+    failed_attempts INTEGER NOT NULL,
+    locked_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    locked_until TIMESTAMPTZ NOT NULL,
+    unlocked_at TIMESTAMPTZ,
+    unlocked_by UUID,
+    CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_unlocked_by FOREIGN KEY (unlocked_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_account_lockouts_user_id ON account_lockouts(user_id);
+
+COMMENT ON COLUMN account_lockouts.ip_address IS 'Client address of the failed attempt that triggered the lockout';
+COMMENT ON COLUMN account_lockouts.failed_attempts IS 'Consecutive failures on the account when it was locked';
+COMMENT ON COLUMN account_lockouts.unlocked_at IS 'Set when an administrator lifts the lockout early';
+COMMENT ON COLUMN account_lockouts.unlocked_by IS 'Administrator who lifted the lockout';
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS system_settings;
+ALTER TABLE sessions DROP COLUMN IF EXISTS mfa_verified;
+DROP INDEX IF EXISTS idx_mfa_recovery_codes_user_id;
+DROP TABLE IF EXISTS mfa_recovery_codes;
+DROP TABLE IF EXISTS user_mfa;
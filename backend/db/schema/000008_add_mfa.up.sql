@@ -0,0 +1,42 @@
+CREATE TABLE IF NOT EXISTS user_mfa (
+    user_id UUID PRIMARY KEY,
+    secret TEXT NOT NULL,
+    enabled_at TIMESTAMPTZ,
+    last_used_step BIGINT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+COMMENT ON COLUMN user_mfa.secret IS 'Base32 TOTP shared secret (RFC 6238)';
+COMMENT ON COLUMN user_mfa.enabled_at IS 'NULL while enrollment is pending confirmation with a first code';
+COMMENT ON COLUMN user_mfa.last_used_step IS 'Time step of the last accepted code, so a code cannot be replayed';
+
+CREATE TABLE IF NOT EXISTS mfa_recovery_codes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    code_hash TEXT NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_mfa_recovery_codes_user_id ON mfa_recovery_codes(user_id);
+
+COMMENT ON COLUMN mfa_recovery_codes.code_hash IS 'SHA-256 hash of a single-use recovery code';
+
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS mfa_verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN sessions.mfa_verified IS 'Whether the login that started the session passed a second factor';
+
+CREATE TABLE IF NOT EXISTS system_settings (
+    key TEXT PRIMARY KEY,
+    value TEXT NOT NULL,
+    updated_by UUID,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_updated_by FOREIGN KEY (updated_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+COMMENT ON TABLE system_settings IS 'Runtime settings administrators can change without a deploy';
+
+INSERT INTO system_settings (key, value) VALUES ('require_admin_mfa', 'false')
+ON CONFLICT (key) DO NOTHING;
@@ -0,0 +1,13 @@
+-- PostgreSQL cannot drop a single enum value, so the type is rebuilt without it.
+DELETE FROM user_tokens WHERE purpose = 'oidc_login';
+
+ALTER TYPE user_token_purpose RENAME TO user_token_purpose_old;
+CREATE TYPE user_token_purpose AS ENUM ('email_verification', 'email_change', 'password_reset');
+
+ALTER TABLE user_tokens ALTER COLUMN purpose TYPE user_token_purpose USING purpose::text::user_token_purpose;
+
+DROP TYPE user_token_purpose_old;
+
+DROP TABLE IF EXISTS oidc_auth_requests;
+DROP INDEX IF EXISTS idx_user_identities_user_id;
+DROP TABLE IF EXISTS user_identities;
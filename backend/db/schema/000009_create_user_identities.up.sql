@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS user_identities (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    provider TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    email VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_login_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT uq_user_identities_provider_subject UNIQUE (provider, subject)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities(user_id);
+
+COMMENT ON COLUMN user_identities.provider IS 'Name of the configured OIDC provider';
+COMMENT ON COLUMN user_identities.subject IS 'Stable ''sub'' claim of the user at the provider';
+COMMENT ON COLUMN user_identities.email IS 'Email the provider reported at the last login';
+
+CREATE TABLE IF NOT EXISTS oidc_auth_requests (
+    state TEXT PRIMARY KEY,
+    provider TEXT NOT NULL,
+    nonce TEXT NOT NULL,
+    code_verifier TEXT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE oidc_auth_requests IS 'Authorization requests sent to an OIDC provider and not yet completed';
+COMMENT ON COLUMN oidc_auth_requests.code_verifier IS 'PKCE verifier whose S256 challenge was sent to the provider';
+
+ALTER TYPE user_token_purpose ADD VALUE IF NOT EXISTS 'oidc_login';
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS api_keys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    name TEXT NOT NULL,
+    prefix TEXT NOT NULL,
+    key_hash TEXT NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    mfa_verified BOOLEAN NOT NULL DEFAULT FALSE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_user_id ON api_keys(user_id);
+
+COMMENT ON COLUMN api_keys.prefix IS 'Leading characters of the key, shown so users can tell their keys apart';
+COMMENT ON COLUMN api_keys.key_hash IS 'SHA-256 hash of the full key; the key itself is only shown once';
+COMMENT ON COLUMN api_keys.scopes IS 'What the key may do, on top of the owner''s role';
+COMMENT ON COLUMN api_keys.mfa_verified IS 'Whether the session that created the key had passed a second factor';
+COMMENT ON COLUMN api_keys.last_used_at IS 'Updated at most once a minute';
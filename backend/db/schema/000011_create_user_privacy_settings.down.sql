@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS user_privacy_settings;
+DROP TYPE IF EXISTS profile_field_visibility;
@@ -0,0 +1,12 @@
+CREATE TYPE profile_field_visibility AS ENUM ('private', 'organizers', 'public');
+
+CREATE TABLE IF NOT EXISTS user_privacy_settings (
+    user_id UUID PRIMARY KEY,
+    phone_visibility profile_field_visibility NOT NULL DEFAULT 'organizers',
+    email_visibility profile_field_visibility NOT NULL DEFAULT 'private',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+COMMENT ON TYPE profile_field_visibility IS 'private: owner and admins; organizers: also authors of posts the user volunteered on; public: any signed-in user';
+COMMENT ON TABLE user_privacy_settings IS 'Who may see contact details on a public profile; users without a row get the column defaults';
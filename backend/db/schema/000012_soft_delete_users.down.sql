@@ -0,0 +1,9 @@
+-- Posts of purged accounts cannot satisfy NOT NULL again and are dropped.
+DELETE FROM posts WHERE user_id IS NULL;
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS fk_user;
+ALTER TABLE posts ADD CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE posts ALTER COLUMN user_id SET NOT NULL;
+COMMENT ON COLUMN posts.user_id IS NULL;
+
+DROP INDEX IF EXISTS idx_users_deleted_at;
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
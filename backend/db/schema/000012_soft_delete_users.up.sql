@@ -0,0 +1,13 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users(deleted_at) WHERE deleted_at IS NOT NULL;
+
+COMMENT ON COLUMN users.deleted_at IS 'Set when the account is deleted; it can be restored until the purge job removes it after the grace period';
+
+-- Purging an account must not take its public complaint history with it:
+-- authored posts are kept and detached from the author instead.
+ALTER TABLE posts ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS fk_user;
+ALTER TABLE posts ADD CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN posts.user_id IS 'Author; NULL once the author''s account has been purged';
@@ -0,0 +1,3 @@
+-- Keyset pagination walks these in either direction.
+CREATE INDEX IF NOT EXISTS idx_posts_created_at_id ON posts(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_users_created_at_id ON users(created_at, id) WHERE deleted_at IS NULL;
@@ -0,0 +1,17 @@
+-- PostgreSQL ships no Mongolian dictionary or stemmer, so words are only
+-- lower-cased and kept whole; searches match them by prefix to cover the
+-- suffixes Mongolian adds (Гэрэлтүүлэг finds гэрэлтүүлэгийн). The default
+-- parser reads Cyrillic as words as long as the database uses a UTF-8 locale.
+CREATE TEXT SEARCH CONFIGURATION mongolian (COPY = simple);
+
+COMMENT ON TEXT SEARCH CONFIGURATION mongolian IS 'Mongolian Cyrillic text: lower-cased words, no stemming or stop words';
+
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('mongolian', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('mongolian', coalesce(description, '')), 'B') ||
+    setweight(to_tsvector('mongolian', coalesce(address_text, '')), 'C')
+) STORED;
+
+COMMENT ON COLUMN posts.search_vector IS 'Weighted full-text document over title (A), description (B) and address_text (C)';
+
+CREATE INDEX IF NOT EXISTS idx_posts_search_vector ON posts USING GIN (search_vector);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_posts_location_point;
+DROP INDEX IF EXISTS idx_posts_location_earth;
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS chk_posts_location;
+DROP EXTENSION IF EXISTS earthdistance;
+DROP EXTENSION IF EXISTS cube;
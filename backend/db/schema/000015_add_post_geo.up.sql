@@ -0,0 +1,23 @@
+CREATE EXTENSION IF NOT EXISTS cube;
+CREATE EXTENSION IF NOT EXISTS earthdistance;
+
+-- Posts created without a location used to be stored at 0,0, and nothing
+-- checked the ranges. Those rows lose their coordinates instead of showing
+-- up off the coast of Africa.
+UPDATE posts SET location_lat = NULL, location_lng = NULL
+WHERE (location_lat = 0 AND location_lng = 0)
+   OR location_lat IS NULL OR location_lng IS NULL
+   OR location_lat NOT BETWEEN -90 AND 90
+   OR location_lng NOT BETWEEN -180 AND 180;
+
+ALTER TABLE posts ADD CONSTRAINT chk_posts_location CHECK (
+    (location_lat IS NULL AND location_lng IS NULL) OR
+    (location_lat BETWEEN -90 AND 90 AND location_lng BETWEEN -180 AND 180)
+);
+
+-- Radius searches go through earthdistance; bounding boxes and clustering
+-- through a plain (lng, lat) point.
+CREATE INDEX IF NOT EXISTS idx_posts_location_earth ON posts USING GIST (ll_to_earth(location_lat, location_lng))
+    WHERE location_lat IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_posts_location_point ON posts USING GIST (point(location_lng, location_lat))
+    WHERE location_lat IS NOT NULL;
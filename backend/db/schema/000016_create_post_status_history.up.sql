@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS post_status_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    post_id UUID NOT NULL,
+    from_status post_status,
+    to_status post_status NOT NULL,
+    changed_by UUID,
+    comment TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_post FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    CONSTRAINT fk_changed_by FOREIGN KEY (changed_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_status_history_post_id ON post_status_history(post_id, created_at);
+
+COMMENT ON TABLE post_status_history IS 'Every status a post has been moved to, and by whom';
+COMMENT ON COLUMN post_status_history.from_status IS 'NULL for the status a post was created with';
+COMMENT ON COLUMN post_status_history.changed_by IS 'NULL once the acting user''s account has been purged';
+
+-- Existing posts start their history with the status they have now.
+INSERT INTO post_status_history (post_id, from_status, to_status, changed_by, created_at)
+SELECT id, NULL, status, user_id, created_at FROM posts;
@@ -0,0 +1,2 @@
+ALTER TABLE post_images DROP CONSTRAINT IF EXISTS uq_post_images_position;
+ALTER TABLE post_images DROP COLUMN IF EXISTS position;
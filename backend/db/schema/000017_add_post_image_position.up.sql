@@ -0,0 +1,15 @@
+ALTER TABLE post_images ADD COLUMN IF NOT EXISTS position INT NOT NULL DEFAULT 0;
+
+-- Existing images keep the order they were uploaded in.
+UPDATE post_images pi
+SET position = ranked.position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY post_id ORDER BY created_at, id) - 1 AS position
+    FROM post_images
+) ranked
+WHERE ranked.id = pi.id;
+
+-- Checked at the end of each statement so images can swap places in one UPDATE.
+ALTER TABLE post_images ADD CONSTRAINT uq_post_images_position UNIQUE (post_id, position) DEFERRABLE INITIALLY IMMEDIATE;
+
+COMMENT ON COLUMN post_images.position IS 'Display order within the post, from 0; the image at 0 is the primary image and the post''s preview';
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS trigger_post_volunteers_count ON post_volunteers;
+DROP FUNCTION IF EXISTS sync_post_current_volunteers();
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS chk_posts_volunteer_capacity;
+COMMENT ON COLUMN posts.current_volunteers IS NULL;
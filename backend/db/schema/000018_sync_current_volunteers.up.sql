@@ -0,0 +1,40 @@
+-- current_volunteers becomes the number of approved applications.
+UPDATE posts p
+SET current_volunteers = (
+    SELECT COUNT(*) FROM post_volunteers pv
+    WHERE pv.post_id = p.id AND pv.status = 'approved'
+);
+
+-- Posts that already approved more volunteers than they asked for keep them.
+UPDATE posts SET max_volunteers = current_volunteers WHERE current_volunteers > max_volunteers;
+
+-- Concurrent approvals serialise on the post row, so the last one over the
+-- limit fails this check instead of overfilling the post.
+ALTER TABLE posts ADD CONSTRAINT chk_posts_volunteer_capacity
+    CHECK (current_volunteers >= 0 AND current_volunteers <= max_volunteers);
+
+CREATE OR REPLACE FUNCTION sync_post_current_volunteers()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        IF OLD.status = 'approved' THEN
+            UPDATE posts SET current_volunteers = current_volunteers - 1 WHERE id = OLD.post_id;
+        END IF;
+    END IF;
+    IF TG_OP <> 'DELETE' THEN
+        IF NEW.status = 'approved' THEN
+            UPDATE posts SET current_volunteers = current_volunteers + 1 WHERE id = NEW.post_id;
+        END IF;
+    END IF;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS trigger_post_volunteers_count ON post_volunteers;
+
+CREATE TRIGGER trigger_post_volunteers_count
+AFTER INSERT OR UPDATE OF status, post_id OR DELETE ON post_volunteers
+FOR EACH ROW
+EXECUTE FUNCTION sync_post_current_volunteers();
+
+COMMENT ON COLUMN posts.current_volunteers IS 'Approved volunteers on the post, maintained by trigger_post_volunteers_count';
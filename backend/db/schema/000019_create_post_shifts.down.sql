@@ -0,0 +1,29 @@
+DROP TRIGGER IF EXISTS trigger_shift_signups_count ON shift_signups;
+DROP FUNCTION IF EXISTS sync_post_shift_signed_up();
+DROP TABLE IF EXISTS shift_signups;
+DROP TABLE IF EXISTS post_shifts;
+
+CREATE OR REPLACE FUNCTION sync_post_current_volunteers()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        IF OLD.status = 'approved' THEN
+            UPDATE posts SET current_volunteers = current_volunteers - 1 WHERE id = OLD.post_id;
+        END IF;
+    END IF;
+    IF TG_OP <> 'DELETE' THEN
+        IF NEW.status = 'approved' THEN
+            UPDATE posts SET current_volunteers = current_volunteers + 1 WHERE id = NEW.post_id;
+        END IF;
+    END IF;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+UPDATE posts p
+SET current_volunteers = (
+    SELECT COUNT(*) FROM post_volunteers pv
+    WHERE pv.post_id = p.id AND pv.status = 'approved'
+);
+
+COMMENT ON COLUMN posts.current_volunteers IS 'Approved volunteers on the post, maintained by trigger_post_volunteers_count';
@@ -0,0 +1,96 @@
+CREATE TABLE IF NOT EXISTS post_shifts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    post_id UUID NOT NULL,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    meeting_point TEXT,
+    meeting_lat DOUBLE PRECISION,
+    meeting_lng DOUBLE PRECISION,
+    capacity INT NOT NULL,
+    signed_up INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_post FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    CONSTRAINT chk_post_shifts_time CHECK (ends_at > starts_at),
+    CONSTRAINT chk_post_shifts_capacity CHECK (capacity > 0 AND signed_up >= 0 AND signed_up <= capacity)
+);
+
+CREATE TABLE IF NOT EXISTS shift_signups (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    shift_id UUID NOT NULL,
+    volunteer_id UUID NOT NULL,
+    checked_in_at TIMESTAMPTZ,
+    check_in_lat DOUBLE PRECISION,
+    check_in_lng DOUBLE PRECISION,
+    checked_out_at TIMESTAMPTZ,
+    check_out_lat DOUBLE PRECISION,
+    check_out_lng DOUBLE PRECISION,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_shift FOREIGN KEY (shift_id) REFERENCES post_shifts(id) ON DELETE CASCADE,
+    CONSTRAINT fk_volunteer FOREIGN KEY (volunteer_id) REFERENCES post_volunteers(id) ON DELETE CASCADE,
+    CONSTRAINT chk_shift_signups_checkout CHECK (checked_out_at IS NULL OR checked_out_at >= checked_in_at),
+    UNIQUE (shift_id, volunteer_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_shifts_post_id ON post_shifts(post_id, starts_at);
+CREATE INDEX IF NOT EXISTS idx_shift_signups_volunteer_id ON shift_signups(volunteer_id);
+
+COMMENT ON TABLE post_shifts IS 'Time slots of a post that approved volunteers sign up for';
+COMMENT ON COLUMN post_shifts.signed_up IS 'Volunteers signed up for the shift, maintained by trigger_shift_signups_count';
+COMMENT ON TABLE shift_signups IS 'An approved volunteer''s place on a shift, with their check-in and check-out';
+COMMENT ON COLUMN shift_signups.checked_out_at IS 'Hours are credited for the part of [checked_in_at, checked_out_at] inside the shift';
+
+-- Like current_volunteers, a shift's sign-ups are counted on the shift row, so
+-- concurrent sign-ups for the last place serialise and the CHECK turns the
+-- loser away.
+CREATE OR REPLACE FUNCTION sync_post_shift_signed_up()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        UPDATE post_shifts SET signed_up = signed_up + 1 WHERE id = NEW.shift_id;
+    ELSE
+        UPDATE post_shifts SET signed_up = signed_up - 1 WHERE id = OLD.shift_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS trigger_shift_signups_count ON shift_signups;
+
+CREATE TRIGGER trigger_shift_signups_count
+AFTER INSERT OR DELETE ON shift_signups
+FOR EACH ROW
+EXECUTE FUNCTION sync_post_shift_signed_up();
+
+-- Volunteers who completed their shifts keep their place on the post.
+CREATE OR REPLACE FUNCTION sync_post_current_volunteers()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        IF OLD.status IN ('approved', 'completed') THEN
+            UPDATE posts SET current_volunteers = current_volunteers - 1 WHERE id = OLD.post_id;
+        END IF;
+    END IF;
+    IF TG_OP <> 'DELETE' THEN
+        IF NEW.status IN ('approved', 'completed') THEN
+            UPDATE posts SET current_volunteers = current_volunteers + 1 WHERE id = NEW.post_id;
+        END IF;
+    END IF;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+-- One statement, so chk_posts_volunteer_capacity holds for every row it writes.
+UPDATE posts p
+SET current_volunteers = c.volunteers,
+    max_volunteers = GREATEST(p.max_volunteers, c.volunteers)
+FROM (
+    SELECT p2.id, (
+        SELECT COUNT(*) FROM post_volunteers pv
+        WHERE pv.post_id = p2.id AND pv.status IN ('approved', 'completed')
+    ) AS volunteers
+    FROM posts p2
+) c
+WHERE c.id = p.id;
+
+COMMENT ON COLUMN posts.current_volunteers IS 'Approved and completed volunteers on the post, maintained by trigger_post_volunteers_count';
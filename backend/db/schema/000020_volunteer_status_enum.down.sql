@@ -0,0 +1,15 @@
+-- PostgreSQL cannot drop enum values, so 'withdrawn' and 'no_show' stay in
+-- volunteer_status and rows that use them keep their status as text.
+COMMENT ON COLUMN post_volunteers.status IS NULL;
+
+DROP TRIGGER IF EXISTS trigger_post_volunteers_count ON post_volunteers;
+
+ALTER TABLE post_volunteers ALTER COLUMN status DROP NOT NULL;
+ALTER TABLE post_volunteers ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE post_volunteers ALTER COLUMN status TYPE VARCHAR(50) USING status::text;
+ALTER TABLE post_volunteers ALTER COLUMN status SET DEFAULT 'pending';
+
+CREATE TRIGGER trigger_post_volunteers_count
+AFTER INSERT OR UPDATE OF status, post_id OR DELETE ON post_volunteers
+FOR EACH ROW
+EXECUTE FUNCTION sync_post_current_volunteers();
@@ -0,0 +1,25 @@
+-- Volunteers can leave a post themselves, and organizers can record who did
+-- not turn up. The new values are not used before this migration commits.
+ALTER TYPE volunteer_status ADD VALUE IF NOT EXISTS 'withdrawn';
+ALTER TYPE volunteer_status ADD VALUE IF NOT EXISTS 'no_show';
+
+-- Statuses outside the enum were never counted as volunteers, so they become
+-- rejections and current_volunteers does not change.
+UPDATE post_volunteers SET status = 'pending' WHERE status IS NULL;
+UPDATE post_volunteers SET status = 'rejected'
+WHERE status NOT IN ('pending', 'approved', 'completed', 'rejected');
+
+-- The trigger depends on the column, so it is recreated around the change.
+DROP TRIGGER IF EXISTS trigger_post_volunteers_count ON post_volunteers;
+
+ALTER TABLE post_volunteers ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE post_volunteers ALTER COLUMN status TYPE volunteer_status USING status::volunteer_status;
+ALTER TABLE post_volunteers ALTER COLUMN status SET DEFAULT 'pending';
+ALTER TABLE post_volunteers ALTER COLUMN status SET NOT NULL;
+
+CREATE TRIGGER trigger_post_volunteers_count
+AFTER INSERT OR UPDATE OF status, post_id OR DELETE ON post_volunteers
+FOR EACH ROW
+EXECUTE FUNCTION sync_post_current_volunteers();
+
+COMMENT ON COLUMN post_volunteers.status IS 'pending until the organizer approves or rejects; approved volunteers end as completed or no_show, or withdraw';
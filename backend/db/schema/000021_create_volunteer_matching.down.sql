@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS volunteer_profiles;
+DROP TABLE IF EXISTS user_availability;
+DROP TABLE IF EXISTS post_skills;
+DROP TABLE IF EXISTS user_skills;
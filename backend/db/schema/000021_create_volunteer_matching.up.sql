@@ -0,0 +1,52 @@
+-- Skills are free-form tags, stored trimmed and lower-cased so that a
+-- volunteer's skills and a post's needed skills compare with plain equality.
+CREATE TABLE IF NOT EXISTS user_skills (
+    user_id UUID NOT NULL,
+    skill VARCHAR(50) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, skill),
+    CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_user_skills_skill CHECK (skill <> '' AND skill = lower(btrim(skill)))
+);
+
+CREATE TABLE IF NOT EXISTS post_skills (
+    post_id UUID NOT NULL,
+    skill VARCHAR(50) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (post_id, skill),
+    CONSTRAINT fk_post FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    CONSTRAINT chk_post_skills_skill CHECK (skill <> '' AND skill = lower(btrim(skill)))
+);
+
+CREATE TABLE IF NOT EXISTS user_availability (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    weekday SMALLINT NOT NULL,
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_user_availability_weekday CHECK (weekday BETWEEN 0 AND 6),
+    CONSTRAINT chk_user_availability_time CHECK (end_time > start_time)
+);
+
+CREATE TABLE IF NOT EXISTS volunteer_profiles (
+    user_id UUID PRIMARY KEY,
+    location_lat DOUBLE PRECISION,
+    location_lng DOUBLE PRECISION,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_volunteer_profiles_location CHECK (
+        (location_lat IS NULL AND location_lng IS NULL) OR
+        (location_lat BETWEEN -90 AND 90 AND location_lng BETWEEN -180 AND 180)
+    )
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_skills_skill ON user_skills(skill);
+CREATE INDEX IF NOT EXISTS idx_user_availability_user_id ON user_availability(user_id, weekday);
+
+COMMENT ON TABLE user_skills IS 'Skills a user offers as a volunteer';
+COMMENT ON TABLE post_skills IS 'Skills a post needs from its volunteers';
+COMMENT ON TABLE user_availability IS 'Weekly time slots in which a user can volunteer';
+COMMENT ON COLUMN user_availability.weekday IS '0 is Sunday; times are in the database time zone';
+COMMENT ON TABLE volunteer_profiles IS 'Where a volunteer is based, for matching them to nearby posts';
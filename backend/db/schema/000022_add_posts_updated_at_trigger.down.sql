@@ -0,0 +1 @@
+DROP TRIGGER IF EXISTS trigger_posts_updated_at ON posts;
@@ -0,0 +1,10 @@
+-- Every change to a post bumps updated_at, including the ones made by other
+-- tables: trigger_post_volunteers_count changing current_volunteers and a
+-- deleted category clearing category_id. updated_at is the post's version
+-- for ETags and optimistic updates, so it has to move whenever the post does.
+DROP TRIGGER IF EXISTS trigger_posts_updated_at ON posts;
+
+CREATE TRIGGER trigger_posts_updated_at
+BEFORE UPDATE ON posts
+FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
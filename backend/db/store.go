@@ -2,17 +2,49 @@ package db
 
 import (
 	"context"
+	"fmt"
 
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func Init(db_url string, ctx *context.Context) (*Queries, error) {
-	conn, err := pgx.Connect(*ctx, db_url)
+// Store runs queries on a connection pool and groups them in transactions
+// with ExecTx.
+type Store struct {
+	*Queries
+	pool *pgxpool.Pool
+}
+
+func Init(db_url string, ctx *context.Context) (*Store, error) {
+	pool, err := pgxpool.New(*ctx, db_url)
 	if err != nil {
 		return nil, err
 	}
+	// The pool connects lazily; check the database is reachable at startup.
+	if err := pool.Ping(*ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	return &Store{Queries: New(pool), pool: pool}, nil
+}
 
-	queries := New(conn)
+// ExecTx runs fn in a transaction. It commits when fn returns nil and rolls
+// back otherwise, returning fn's error.
+func (s *Store) ExecTx(ctx context.Context, fn func(*Queries) error) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	if err := fn(s.WithTx(tx)); err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+		}
+		return err
+	}
+	return tx.Commit(ctx)
+}
 
-	return  queries, nil
+// Close closes every connection in the pool.
+func (s *Store) Close() {
+	s.pool.Close()
 }
@@ -24,7 +24,7 @@ INSERT INTO users (
 ) VALUES (
     $1, $2, $3, $4, $5, $6, $7, $8
 )
-RETURNING id, first_name, last_name, phone, is_volunteering, email, role, profile_url, password_hash, created_at, updated_at
+RETURNING id, first_name, last_name, phone, is_volunteering, email, role, profile_url, password_hash, created_at, updated_at, email_verified_at, deleted_at
 `
 
 type CreateUserParams struct {
@@ -64,6 +64,8 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.PasswordHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -81,7 +83,7 @@ func (q *Queries) DeleteUser(ctx context.Context, id pgtype.UUID) error {
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, first_name, last_name, phone, is_volunteering, email, role, profile_url, password_hash, created_at, updated_at FROM users
+SELECT id, first_name, last_name, phone, is_volunteering, email, role, profile_url, password_hash, created_at, updated_at, email_verified_at, deleted_at FROM users
 WHERE email = $1
 `
 
@@ -101,13 +103,15 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.PasswordHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
 
-SELECT id, first_name, last_name, phone, is_volunteering, email, role, profile_url, password_hash, created_at, updated_at FROM users
+SELECT id, first_name, last_name, phone, is_volunteering, email, role, profile_url, password_hash, created_at, updated_at, email_verified_at, deleted_at FROM users
 WHERE id = $1
 `
 
@@ -127,19 +131,63 @@ func (q *Queries) GetUserByID(ctx context.Context, id pgtype.UUID) (User, error)
 		&i.PasswordHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
+const getUserRole = `-- name: GetUserRole :one
+SELECT role FROM users
+WHERE id = $1
+`
+
+// Fetches only the role, used to authorize requests against the current
+// role rather than the one embedded in the token.
+func (q *Queries) GetUserRole(ctx context.Context, id pgtype.UUID) (UserRole, error) {
+	row := q.db.QueryRow(ctx, getUserRole, id)
+	var role UserRole
+	err := row.Scan(&role)
+	return role, err
+}
+
 const listUsers = `-- name: ListUsers :many
-SELECT id, first_name, last_name, phone, is_volunteering, email, role, profile_url, password_hash, created_at, updated_at FROM users
-ORDER BY created_at DESC
+SELECT id, first_name, last_name, phone, is_volunteering, email, role, profile_url, password_hash, created_at, updated_at, email_verified_at, deleted_at FROM users
+WHERE deleted_at IS NULL
+  AND ($1::user_role IS NULL OR role = $1)
+  AND ($2::boolean IS NULL OR is_volunteering = $2)
+  AND (
+    $3::timestamptz IS NULL
+    OR ($4::boolean AND (created_at, id) > ($3, $5::uuid))
+    OR (NOT $4::boolean AND (created_at, id) < ($3, $5::uuid))
+  )
+ORDER BY
+    CASE WHEN $4::boolean THEN created_at END ASC,
+    CASE WHEN $4::boolean THEN id END ASC,
+    CASE WHEN NOT $4::boolean THEN created_at END DESC,
+    CASE WHEN NOT $4::boolean THEN id END DESC
+LIMIT $6
 `
 
-// Selects all users. Consider adding pagination parameters (LIMIT, OFFSET)
-// for real-world applications if the user list can be large.
-func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
-	rows, err := q.db.Query(ctx, listUsers)
+type ListUsersParams struct {
+	Role            NullUserRole
+	IsVolunteering  pgtype.Bool
+	CursorCreatedAt pgtype.Timestamptz
+	SortAsc         bool
+	CursorID        pgtype.UUID
+	PageSize        int32
+}
+
+// Keyset pagination on (created_at, id), like ListPosts.
+func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, listUsers,
+		arg.Role,
+		arg.IsVolunteering,
+		arg.CursorCreatedAt,
+		arg.SortAsc,
+		arg.CursorID,
+		arg.PageSize,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -159,6 +207,8 @@ func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
 			&i.PasswordHash,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.EmailVerifiedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -170,8 +220,88 @@ func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
 	return items, nil
 }
 
+const listUsersDueForPurge = `-- name: ListUsersDueForPurge :many
+SELECT id, first_name, last_name, phone, is_volunteering, email, role, profile_url, password_hash, created_at, updated_at, email_verified_at, deleted_at FROM users
+WHERE deleted_at IS NOT NULL AND deleted_at <= $1
+ORDER BY deleted_at
+LIMIT $2
+`
+
+type ListUsersDueForPurgeParams struct {
+	DeletedBefore pgtype.Timestamptz
+	BatchSize     int32
+}
+
+func (q *Queries) ListUsersDueForPurge(ctx context.Context, arg ListUsersDueForPurgeParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, listUsersDueForPurge, arg.DeletedBefore, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.FirstName,
+			&i.LastName,
+			&i.Phone,
+			&i.IsVolunteering,
+			&i.Email,
+			&i.Role,
+			&i.ProfileUrl,
+			&i.PasswordHash,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.EmailVerifiedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockUserDueForPurge = `-- name: LockUserDueForPurge :one
+SELECT id, first_name, last_name, phone, is_volunteering, email, role, profile_url, password_hash, created_at, updated_at, email_verified_at, deleted_at FROM users
+WHERE id = $1 AND deleted_at IS NOT NULL AND deleted_at <= $2
+FOR UPDATE
+`
+
+type LockUserDueForPurgeParams struct {
+	ID            pgtype.UUID
+	DeletedBefore pgtype.Timestamptz
+}
+
+// Locks a deleted account whose grace period is over, so it cannot be
+// restored while it is being purged. Returns no rows once it was restored.
+func (q *Queries) LockUserDueForPurge(ctx context.Context, arg LockUserDueForPurgeParams) (User, error) {
+	row := q.db.QueryRow(ctx, lockUserDueForPurge, arg.ID, arg.DeletedBefore)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.FirstName,
+		&i.LastName,
+		&i.Phone,
+		&i.IsVolunteering,
+		&i.Email,
+		&i.Role,
+		&i.ProfileUrl,
+		&i.PasswordHash,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
 const loginRequest = `-- name: LoginRequest :one
-SELECT id, email, password_hash, role FROM users
+SELECT id, email, password_hash, role, deleted_at FROM users
 WHERE email = $1
 `
 
@@ -180,6 +310,7 @@ type LoginRequestRow struct {
 	Email        string
 	PasswordHash string
 	Role         UserRole
+	DeletedAt    pgtype.Timestamptz
 }
 
 // Specifically for authentication: fetches only necessary fields
@@ -192,6 +323,123 @@ func (q *Queries) LoginRequest(ctx context.Context, email string) (LoginRequestR
 		&i.Email,
 		&i.PasswordHash,
 		&i.Role,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const markUserEmailVerified = `-- name: MarkUserEmailVerified :one
+UPDATE users
+SET
+    email_verified_at = COALESCE(email_verified_at, CURRENT_TIMESTAMP),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING id, first_name, last_name, phone, is_volunteering, email, role, profile_url, password_hash, created_at, updated_at, email_verified_at, deleted_at
+`
+
+func (q *Queries) MarkUserEmailVerified(ctx context.Context, id pgtype.UUID) (User, error) {
+	row := q.db.QueryRow(ctx, markUserEmailVerified, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.FirstName,
+		&i.LastName,
+		&i.Phone,
+		&i.IsVolunteering,
+		&i.Email,
+		&i.Role,
+		&i.ProfileUrl,
+		&i.PasswordHash,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const purgeDeletedUser = `-- name: PurgeDeletedUser :execrows
+DELETE FROM users
+WHERE id = $1 AND deleted_at IS NOT NULL AND deleted_at <= $2
+`
+
+type PurgeDeletedUserParams struct {
+	ID            pgtype.UUID
+	DeletedBefore pgtype.Timestamptz
+}
+
+// Deletes the user only while it is still deleted past the grace period.
+func (q *Queries) PurgeDeletedUser(ctx context.Context, arg PurgeDeletedUserParams) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeletedUser, arg.ID, arg.DeletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restoreUser = `-- name: RestoreUser :one
+UPDATE users
+SET
+    deleted_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at > $2
+RETURNING id, first_name, last_name, phone, is_volunteering, email, role, profile_url, password_hash, created_at, updated_at, email_verified_at, deleted_at
+`
+
+type RestoreUserParams struct {
+	ID           pgtype.UUID
+	DeletedAfter pgtype.Timestamptz
+}
+
+// Cancels a pending deletion while the account is still in its grace period.
+func (q *Queries) RestoreUser(ctx context.Context, arg RestoreUserParams) (User, error) {
+	row := q.db.QueryRow(ctx, restoreUser, arg.ID, arg.DeletedAfter)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.FirstName,
+		&i.LastName,
+		&i.Phone,
+		&i.IsVolunteering,
+		&i.Email,
+		&i.Role,
+		&i.ProfileUrl,
+		&i.PasswordHash,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const softDeleteUser = `-- name: SoftDeleteUser :one
+UPDATE users
+SET
+    deleted_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, first_name, last_name, phone, is_volunteering, email, role, profile_url, password_hash, created_at, updated_at, email_verified_at, deleted_at
+`
+
+// Marks the account deleted; the purge job removes it after the grace period.
+func (q *Queries) SoftDeleteUser(ctx context.Context, id pgtype.UUID) (User, error) {
+	row := q.db.QueryRow(ctx, softDeleteUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.FirstName,
+		&i.LastName,
+		&i.Phone,
+		&i.IsVolunteering,
+		&i.Email,
+		&i.Role,
+		&i.ProfileUrl,
+		&i.PasswordHash,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -204,8 +452,8 @@ SET
     phone = $4,
     is_volunteering = $5,
     profile_url = $6
-WHERE id = $1
-RETURNING id, first_name, last_name, phone, is_volunteering, email, role, profile_url, password_hash, created_at, updated_at
+WHERE id = $1 AND updated_at = $7
+RETURNING id, first_name, last_name, phone, is_volunteering, email, role, profile_url, password_hash, created_at, updated_at, email_verified_at, deleted_at
 `
 
 type UpdateUserDetailsParams struct {
@@ -215,10 +463,12 @@ type UpdateUserDetailsParams struct {
 	Phone          pgtype.Text
 	IsVolunteering bool
 	ProfileUrl     pgtype.Text
+	UpdatedAt      pgtype.Timestamptz
 }
 
 // Updates general user details.
 // Excludes password_hash and role, which might have separate update logic.
+// Only updates the user while updated_at is still the value the caller read.
 func (q *Queries) UpdateUserDetails(ctx context.Context, arg UpdateUserDetailsParams) (User, error) {
 	row := q.db.QueryRow(ctx, updateUserDetails,
 		arg.ID,
@@ -227,6 +477,7 @@ func (q *Queries) UpdateUserDetails(ctx context.Context, arg UpdateUserDetailsPa
 		arg.Phone,
 		arg.IsVolunteering,
 		arg.ProfileUrl,
+		arg.UpdatedAt,
 	)
 	var i User
 	err := row.Scan(
@@ -241,6 +492,8 @@ func (q *Queries) UpdateUserDetails(ctx context.Context, arg UpdateUserDetailsPa
 		&i.PasswordHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -249,9 +502,10 @@ const updateUserEmail = `-- name: UpdateUserEmail :one
 UPDATE users
 SET
     email = $2,
+    email_verified_at = CURRENT_TIMESTAMP,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
-RETURNING id, first_name, last_name, phone, is_volunteering, email, role, profile_url, password_hash, created_at, updated_at
+RETURNING id, first_name, last_name, phone, is_volunteering, email, role, profile_url, password_hash, created_at, updated_at, email_verified_at, deleted_at
 `
 
 type UpdateUserEmailParams struct {
@@ -260,7 +514,8 @@ type UpdateUserEmailParams struct {
 }
 
 // Specific query to update user email.
-// Requires careful handling in application (e.g., re-verification).
+// Only called once the new address was confirmed through an email_change
+// token, so the address is marked verified at the same time.
 func (q *Queries) UpdateUserEmail(ctx context.Context, arg UpdateUserEmailParams) (User, error) {
 	row := q.db.QueryRow(ctx, updateUserEmail, arg.ID, arg.Email)
 	var i User
@@ -276,6 +531,8 @@ func (q *Queries) UpdateUserEmail(ctx context.Context, arg UpdateUserEmailParams
 		&i.PasswordHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -286,7 +543,7 @@ SET
     password_hash = $2,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
-RETURNING id, first_name, last_name, phone, is_volunteering, email, role, profile_url, password_hash, created_at, updated_at
+RETURNING id, first_name, last_name, phone, is_volunteering, email, role, profile_url, password_hash, created_at, updated_at, email_verified_at, deleted_at
 `
 
 type UpdateUserPasswordParams struct {
@@ -310,6 +567,44 @@ func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPassword
 		&i.PasswordHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const updateUserRole = `-- name: UpdateUserRole :one
+UPDATE users
+SET
+    role = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING id, first_name, last_name, phone, is_volunteering, email, role, profile_url, password_hash, created_at, updated_at, email_verified_at, deleted_at
+`
+
+type UpdateUserRoleParams struct {
+	ID   pgtype.UUID
+	Role UserRole
+}
+
+// Promotes or demotes a user. Restricted to administrators in the application.
+func (q *Queries) UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserRole, arg.ID, arg.Role)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.FirstName,
+		&i.LastName,
+		&i.Phone,
+		&i.IsVolunteering,
+		&i.Email,
+		&i.Role,
+		&i.ProfileUrl,
+		&i.PasswordHash,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -85,37 +85,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Failed to approve volunteer",
+                    "409": {
+                        "description": "The application is not pending, or the post has no free volunteer places",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/categories": {
-            "get": {
-                "description": "Retrieves categories.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Categories"
-                ],
-                "summary": "Get categories",
-                "responses": {
-                    "200": {
-                        "description": "Successfully retrieved categories",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/server.CategoryDTO"
-                            }
-                        }
                     },
                     "500": {
-                        "description": "Failed to retrieve categories",
+                        "description": "Failed to approve volunteer",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
@@ -123,117 +100,104 @@ const docTemplate = `{
                 }
             }
         },
-        "/categories/{categoryId}": {
-            "get": {
-                "description": "Retrieves category name.",
+        "/auth/confirm-email-change": {
+            "post": {
+                "description": "Redeems the one-time token sent to the new address by PUT /users/me/email and switches the account to it.\nAll sessions of the user are revoked and the previous address is notified.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Categories"
+                    "Authentication"
                 ],
-                "summary": "Get category name",
+                "summary": "Confirm email change",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Category ID",
-                        "name": "categoryId",
-                        "in": "path",
-                        "required": true
+                        "description": "Email change token",
+                        "name": "confirmation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.VerifyEmailRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved category name.",
+                        "description": "Email address changed",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/server.UserResponseDTO"
                         }
                     },
                     "400": {
-                        "description": "Invalid category ID format",
+                        "description": "Invalid request payload or token invalid, expired or already used",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Failed to retrieve category name",
+                    "404": {
+                        "description": "User not found",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/posts": {
-            "get": {
-                "description": "Retrieves a list of all posts, including associated images and volunteers.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Posts"
-                ],
-                "summary": "List all posts",
-                "responses": {
-                    "200": {
-                        "description": "Successfully retrieved list of posts",
+                    },
+                    "409": {
+                        "description": "This email is already in use",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/server.PostResponseDTO"
-                            }
+                            "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Failed to retrieve posts",
+                        "description": "Failed to change email",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/logout": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creates a new post. The authenticated user will be the owner.\nPost details are sent as a JSON string in the 'postData' form field.\nOptionally, up to 5 images can be uploaded via the 'postImages' form field.",
+                "description": "Revokes the session the access token belongs to. With 'all_sessions' set, every session of the user is revoked.",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Posts"
+                    "Authentication"
                 ],
-                "summary": "Create a new post (with optional images)",
+                "summary": "Logout",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Post creation details as a JSON string. Example: '{\\",
-                        "name": "postData",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "file",
-                        "description": "Optional post image files (max 5MB each, up to 5 files, types: jpeg, png, gif, webp)",
-                        "name": "postImages",
-                        "in": "formData"
+                        "description": "Logout options",
+                        "name": "logout",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/server.LogoutRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Successfully created post",
+                    "200": {
+                        "description": "message: Logged out successfully",
                         "schema": {
-                            "$ref": "#/definitions/server.PostResponseDTO"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid request (e.g., missing 'postData', invalid JSON, invalid image, too many images, missing required fields in postData)",
+                        "description": "Invalid request payload",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
@@ -245,7 +209,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Internal server error (e.g., failed to upload image, or create post/post_image record)",
+                        "description": "Failed to logout",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
@@ -253,40 +217,69 @@ const docTemplate = `{
                 }
             }
         },
-        "/posts/volunteers": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+        "/auth/mfa/verify": {
+            "post": {
+                "description": "Exchanges the 'mfa_token' returned by login plus a TOTP code or an unused recovery code for a session.\nFailed codes count towards the same login throttling as wrong passwords.\nAn account scheduled for deletion that logged in with restore_account set is restored here.",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Retrieves a list of all volunteer applications across all posts. (Consider admin-only access and pagination).",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Posts",
-                    "Volunteers"
+                    "Authentication"
+                ],
+                "summary": "Complete login with a second factor",
+                "parameters": [
+                    {
+                        "description": "MFA token and code",
+                        "name": "verification",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.VerifyMFARequest"
+                        }
+                    }
                 ],
-                "summary": "List all post volunteers",
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved list of post volunteers",
+                        "description": "Successfully logged in",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/server.PostVolunteerDTO"
-                            }
+                            "$ref": "#/definitions/server.LoginResponsePayloadDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload or missing fields",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Authentication required",
+                        "description": "MFA token invalid or expired, or code invalid",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Account has been deleted",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed login attempts",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the next attempt will be evaluated"
+                            }
                         }
                     },
                     "500": {
-                        "description": "Failed to retrieve post volunteers",
+                        "description": "Failed to verify code",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
@@ -294,76 +287,63 @@ const docTemplate = `{
                 }
             }
         },
-        "/posts/volunteers/{userId}": {
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+        "/auth/oidc/exchange": {
+            "post": {
+                "description": "Redeems the code the frontend received from the OIDC callback. Returns the same tokens as password login,\nor a 202 with an 'mfa_token' when the account has two-factor authentication enabled.",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Allows a post owner to remove a volunteer or a volunteer to remove their own application from a post.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Posts",
-                    "Volunteers"
+                    "Authentication"
                 ],
-                "summary": "Remove a volunteer from a post",
+                "summary": "Complete OIDC login",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Volunteer's User ID to remove",
-                        "name": "userId",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Post ID from which to remove the volunteer",
-                        "name": "postId",
-                        "in": "query",
-                        "required": true
+                        "description": "Login code",
+                        "name": "exchange",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.OIDCExchangeRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "message: Volunteer removed successfully",
+                        "description": "Login successful",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/server.LoginResponsePayloadDTO"
                         }
                     },
-                    "400": {
-                        "description": "Invalid user ID or post ID format, or missing postId query parameter",
+                    "202": {
+                        "description": "Complete the login at POST /auth/mfa/verify",
                         "schema": {
-                            "$ref": "#/definitions/server.ErrorResponse"
+                            "$ref": "#/definitions/server.MFAChallengeResponseDTO"
                         }
                     },
-                    "401": {
-                        "description": "Authentication required",
+                    "400": {
+                        "description": "Code is invalid, expired or has already been used",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden - not authorized to remove this volunteer",
+                    "409": {
+                        "description": "Account is scheduled for deletion",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Post not found",
+                    "429": {
+                        "description": "Account temporarily locked",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Failed to delete post volunteer",
+                        "description": "Login failed",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
@@ -371,60 +351,107 @@ const docTemplate = `{
                 }
             }
         },
-        "/posts/{postId}": {
+        "/auth/oidc/providers": {
             "get": {
-                "description": "Retrieves details for a specific post, including images and volunteers.",
+                "description": "Returns the names of the identity providers that can be used with GET /auth/oidc/{provider}/login.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Posts"
+                    "Authentication"
                 ],
-                "summary": "Get post by ID",
+                "summary": "List OIDC providers",
+                "responses": {
+                    "200": {
+                        "description": "Configured providers",
+                        "schema": {
+                            "$ref": "#/definitions/server.OIDCProvidersResponseDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/{provider}/callback": {
+            "get": {
+                "description": "Called by the identity provider. Verifies the ID token, links or creates the user and redirects to\nthe frontend with a one-time login code. The browser must carry the HttpOnly cookie set by the login\nendpoint, otherwise the error is 'invalid_state'. Existing accounts are only linked when the provider\nreports the email address as verified ('email_not_verified' otherwise) and the account has verified it\ntoo ('account_email_not_verified' otherwise; a password reset verifies it).",
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "OIDC callback",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Post ID",
-                        "name": "postId",
+                        "description": "Provider name",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "State issued by the login endpoint",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authorization code",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Redirect to the frontend with 'code' or 'error'"
+                    }
+                }
+            }
+        },
+        "/auth/oidc/{provider}/login": {
+            "get": {
+                "description": "Redirects the browser to the identity provider and sets a short-lived HttpOnly cookie that ties the\nattempt to this browser. After the user signs in, the provider returns to\nGET /auth/oidc/{provider}/callback, which redirects to the frontend at '/auth/oidc/callback' with a\none-time 'code' (or an 'error'). Exchange that code at POST /auth/oidc/exchange.",
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Start OIDC login",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider name",
+                        "name": "provider",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Successfully retrieved post",
+                    "302": {
+                        "description": "Redirect to the identity provider"
+                    },
+                    "404": {
+                        "description": "Unknown provider",
                         "schema": {
-                            "$ref": "#/definitions/server.PostResponseDTO"
+                            "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
-                    "400": {
-                        "description": "Invalid post ID format",
+                    "500": {
+                        "description": "Failed to start login",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Post not found",
-                        "schema": {
-                            "$ref": "#/definitions/server.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Failed to retrieve post",
+                    "502": {
+                        "description": "Identity provider unavailable",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     }
                 }
-            },
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Updates details of a specific post. Only the owner of the post can update it.",
+            }
+        },
+        "/auth/password/forgot": {
+            "post": {
+                "description": "Emails a one-time password reset link if an account exists for the address.\nThe response is the same whether or not the account exists.",
                 "consumes": [
                     "application/json"
                 ],
@@ -432,94 +459,66 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Posts"
+                    "Authentication"
                 ],
-                "summary": "Update post by ID",
+                "summary": "Request password reset",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Post ID",
-                        "name": "postId",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Post update details",
-                        "name": "postUpdateData",
+                        "description": "Account email",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/server.UpdatePostRequest"
+                            "$ref": "#/definitions/server.ForgotPasswordRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Successfully updated post",
+                    "202": {
+                        "description": "message: If an account exists for this email, a reset link has been sent",
                         "schema": {
-                            "$ref": "#/definitions/server.PostResponseDTO"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid request payload or post ID format",
-                        "schema": {
-                            "$ref": "#/definitions/server.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Authentication required",
-                        "schema": {
-                            "$ref": "#/definitions/server.ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden - not authorized to update this post",
-                        "schema": {
-                            "$ref": "#/definitions/server.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Post not found to update",
-                        "schema": {
-                            "$ref": "#/definitions/server.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Failed to update post",
+                        "description": "Invalid request payload or email empty",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+            }
+        },
+        "/auth/password/reset": {
+            "post": {
+                "description": "Redeems a password reset token and sets a new password. The new password must satisfy the password policy;\na rejected password does not use up the token. All sessions of the user are revoked.",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Deletes a specific post. Only the owner of the post can delete it.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Posts"
+                    "Authentication"
                 ],
-                "summary": "Delete post by ID",
+                "summary": "Reset password",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Post ID",
-                        "name": "postId",
-                        "in": "path",
-                        "required": true
+                        "description": "Reset token and new password",
+                        "name": "reset",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.ResetPasswordRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "message: Post deleted successfully",
+                        "description": "message: Password has been reset",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -528,31 +527,25 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid post ID format",
+                        "description": "Invalid request payload, new password empty or token invalid, expired or already used",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Authentication required",
-                        "schema": {
-                            "$ref": "#/definitions/server.ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden - not authorized to delete this post",
+                    "404": {
+                        "description": "User not found",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Post not found",
+                    "422": {
+                        "description": "New password does not meet the password policy",
                         "schema": {
-                            "$ref": "#/definitions/server.ErrorResponse"
+                            "$ref": "#/definitions/server.PasswordPolicyErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Failed to delete post",
+                        "description": "Failed to reset password",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
@@ -560,14 +553,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/reject_volunteer": {
+        "/auth/refresh": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Allows the owner of a post to reject a pending volunteer application.",
+                "description": "Exchanges a valid refresh token for a new short-lived access token and a new refresh token.\nRefresh tokens are single-use: presenting one that was already rotated revokes the whole session.",
                 "consumes": [
                     "application/json"
                 ],
@@ -575,54 +563,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Posts",
-                    "Volunteers"
+                    "Authentication"
                 ],
-                "summary": "Reject a volunteer for a post",
+                "summary": "Refresh access token",
                 "parameters": [
                     {
-                        "description": "Details of the post and volunteer to reject",
-                        "name": "rejectionData",
+                        "description": "Refresh token",
+                        "name": "refresh",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/server.ApproveRejectVolunteerRequest"
+                            "$ref": "#/definitions/server.RefreshTokenRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully rejected volunteer",
+                        "description": "New token pair",
                         "schema": {
-                            "$ref": "#/definitions/server.PostVolunteerDTO"
+                            "$ref": "#/definitions/server.TokenResponseDTO"
                         }
                     },
                     "400": {
-                        "description": "Invalid request payload or missing IDs",
+                        "description": "Invalid request payload or missing refresh token",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Authentication required",
-                        "schema": {
-                            "$ref": "#/definitions/server.ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden - not authorized to reject volunteers for this post",
-                        "schema": {
-                            "$ref": "#/definitions/server.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Post or volunteer application not found",
+                        "description": "Refresh token invalid, expired, revoked or reused",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Failed to reject volunteer",
+                        "description": "Failed to refresh token",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
@@ -630,39 +605,51 @@ const docTemplate = `{
                 }
             }
         },
-        "/users": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+        "/auth/verify-email": {
+            "post": {
+                "description": "Redeems the one-time token sent to the user's email address after registration.",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Retrieves a list of all users. (Note: Consider pagination for large datasets and admin-only access)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Authentication"
+                ],
+                "summary": "Verify email address",
+                "parameters": [
+                    {
+                        "description": "Verification token",
+                        "name": "verification",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.VerifyEmailRequest"
+                        }
+                    }
                 ],
-                "summary": "List all users",
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved list of users",
+                        "description": "Email address verified",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/server.UserResponseDTO"
-                            }
+                            "$ref": "#/definitions/server.UserResponseDTO"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Invalid request payload or token invalid, expired or already used",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Failed to list users",
+                        "description": "Failed to verify email",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
@@ -670,58 +657,51 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/by-email": {
-            "get": {
+        "/auth/verify-email/resend": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves user details for a given email address. (Consider admin-only access).",
+                "description": "Sends a new verification link to the current user's email address. Previously sent links stop working.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
-                ],
-                "summary": "Get user by email",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "example": "\"jane.doe@example.com\"",
-                        "description": "Email address of the user",
-                        "name": "email",
-                        "in": "query",
-                        "required": true
-                    }
+                    "Authentication"
                 ],
+                "summary": "Resend verification email",
                 "responses": {
-                    "200": {
-                        "description": "Successfully retrieved user",
+                    "202": {
+                        "description": "message: Verification email sent",
                         "schema": {
-                            "$ref": "#/definitions/server.UserResponseDTO"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Email query parameter is required",
+                    "401": {
+                        "description": "Authentication required",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "User not found",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "User with this email not found",
+                    "409": {
+                        "description": "Email address is already verified",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Failed to get user by email",
+                        "description": "Failed to send verification email",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
@@ -729,9 +709,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/login": {
+        "/categories": {
+            "get": {
+                "description": "Retrieves categories.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Get categories",
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved categories",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/server.CategoryDTO"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve categories",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            },
             "post": {
-                "description": "Authenticates a user with email and password, returns a JWT token and user details.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a new post category. Requires the categories:write permission.",
                 "consumes": [
                     "application/json"
                 ],
@@ -739,25 +751,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication"
+                    "Categories"
                 ],
-                "summary": "Login a user",
+                "summary": "Create a category",
                 "parameters": [
                     {
-                        "description": "User login credentials",
-                        "name": "credentials",
+                        "description": "Category details",
+                        "name": "category",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/server.LoginRequestPayload"
+                            "$ref": "#/definitions/server.CreateCategoryRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Successfully logged in",
+                    "201": {
+                        "description": "Successfully created category",
                         "schema": {
-                            "$ref": "#/definitions/server.LoginResponsePayloadDTO"
+                            "$ref": "#/definitions/server.CategoryDTO"
                         }
                     },
                     "400": {
@@ -767,50 +779,25 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Invalid email or password",
+                        "description": "Authentication required",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Login failed or failed to generate token",
+                    "403": {
+                        "description": "Missing permission",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/users/me": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Retrieves details for the currently authenticated user.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Users"
-                ],
-                "summary": "Get current user",
-                "responses": {
-                    "200": {
-                        "description": "Successfully retrieved user",
-                        "schema": {
-                            "$ref": "#/definitions/server.UserResponseDTO"
-                        }
                     },
-                    "401": {
-                        "description": "Authentication required or user not found",
+                    "409": {
+                        "description": "Category with this name or endpoint already exists",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Failed to get user",
+                        "description": "Failed to create category",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
@@ -818,62 +805,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/me/details": {
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Updates the first name, last name, phone, volunteering status, and profile URL for the authenticated user.",
-                "consumes": [
-                    "application/json"
-                ],
+        "/categories/{categoryId}": {
+            "get": {
+                "description": "Retrieves category name.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Categories"
                 ],
-                "summary": "Update current user's details",
+                "summary": "Get category name",
                 "parameters": [
                     {
-                        "description": "User details to update",
-                        "name": "userDetails",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/server.UpdateUserDetailsRequest"
-                        }
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Category ID",
+                        "name": "categoryId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully updated user details",
+                        "description": "Successfully retrieved category name.",
                         "schema": {
-                            "$ref": "#/definitions/server.UserResponseDTO"
+                            "type": "string"
                         }
                     },
                     "400": {
-                        "description": "Invalid request payload",
-                        "schema": {
-                            "$ref": "#/definitions/server.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Authentication required",
-                        "schema": {
-                            "$ref": "#/definitions/server.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "User not found to update",
+                        "description": "Invalid category ID format",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Failed to update user details",
+                        "description": "Failed to retrieve category name",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
@@ -881,116 +847,146 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/me/email": {
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Updates the email address for the authenticated user. May require re-verification in a real application.",
-                "consumes": [
-                    "application/json"
-                ],
+        "/posts": {
+            "get": {
+                "description": "Retrieves posts, including associated images and volunteer counts, one page at a time.\nPages are ordered by creation time; follow 'next_cursor' until it is omitted to read them all.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Posts"
                 ],
-                "summary": "Update current user's email",
+                "summary": "List posts",
                 "parameters": [
                     {
-                        "description": "New email address",
-                        "name": "emailUpdate",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/server.UpdateUserEmailRequest"
-                        }
+                        "type": "string",
+                        "description": "Only posts with this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only posts with this priority",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only posts of this type",
+                        "name": "post_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Only posts in this category",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Only posts by this user",
+                        "name": "author",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only posts created at or after this time (RFC 3339 or YYYY-MM-DD)",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only posts created before this time (RFC 3339 or YYYY-MM-DD)",
+                        "name": "created_before",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "-created_at"
+                        ],
+                        "type": "string",
+                        "description": "created_at (oldest first) or -created_at (newest first, default)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 1-100 (default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor from the previous page",
+                        "name": "cursor",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully updated user email",
+                        "description": "One page of posts",
                         "schema": {
-                            "$ref": "#/definitions/server.UserResponseDTO"
+                            "$ref": "#/definitions/server.PageDTO-server_PostResponseDTO"
                         }
                     },
                     "400": {
-                        "description": "Invalid request payload or email empty",
-                        "schema": {
-                            "$ref": "#/definitions/server.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Authentication required",
-                        "schema": {
-                            "$ref": "#/definitions/server.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "User not found to update email",
-                        "schema": {
-                            "$ref": "#/definitions/server.ErrorResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "This email is already in use",
+                        "description": "Invalid filter or cursor",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Failed to update user email",
+                        "description": "Failed to retrieve posts",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/users/me/password": {
-            "put": {
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Updates the password for the authenticated user.",
+                "description": "Creates a new post. The authenticated user will be the owner.\nPost details are sent as a JSON string in the 'postData' form field.\nOptionally, up to 5 images can be uploaded via the 'postImages' form field.\nThe post description will be used to automatically categorize the post using AI.",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Posts"
                 ],
-                "summary": "Update current user's password",
+                "summary": "Create a new post (with optional images and AI categorization)",
                 "parameters": [
                     {
-                        "description": "New password",
-                        "name": "passwordUpdate",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/server.UpdateUserPasswordRequest"
-                        }
+                        "type": "string",
+                        "description": "Post creation details as a JSON string. Example: '{\\",
+                        "name": "postData",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Optional post image files (max 5MB each, up to 5 files, types: jpeg, png, gif, webp)",
+                        "name": "postImages",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "message: Password updated successfully",
+                    "201": {
+                        "description": "Successfully created post",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/server.PostResponseDTO"
                         }
                     },
                     "400": {
-                        "description": "Invalid request payload or new password empty",
+                        "description": "Invalid request",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
@@ -1001,14 +997,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "User not found to update password",
-                        "schema": {
-                            "$ref": "#/definitions/server.ErrorResponse"
-                        }
-                    },
                     "500": {
-                        "description": "Failed to hash new password or update user password",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
@@ -1016,55 +1006,83 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/register": {
-            "post": {
-                "description": "Registers a new user. User details are sent as a JSON string in the 'userData' form field.\nOptionally, a profile image can be uploaded via the 'profileImage' form field.",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+        "/posts.geojson": {
+            "get": {
+                "description": "Returns the posts that have a location as a GeoJSON FeatureCollection of points, newest first, for GIS tools.\nTakes the same filters as GET /posts plus an optional 'bbox'. At most 10000 features are returned.",
                 "produces": [
-                    "application/json"
+                    "application/geo+json"
                 ],
                 "tags": [
-                    "Users"
+                    "Posts"
                 ],
-                "summary": "Create a new user (with optional profile image)",
+                "summary": "Export posts as GeoJSON",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User registration details as a JSON string. Example: '{\\",
-                        "name": "userData",
-                        "in": "formData",
-                        "required": true
+                        "description": "Bounding box as min_lng,min_lat,max_lng,max_lat",
+                        "name": "bbox",
+                        "in": "query"
                     },
                     {
-                        "type": "file",
-                        "description": "Optional profile image file (max 5MB, types: jpeg, png, gif, webp)",
-                        "name": "profileImage",
-                        "in": "formData"
+                        "type": "string",
+                        "description": "Only posts with this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only posts with this priority",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only posts of this type",
+                        "name": "post_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Only posts in this category",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Only posts by this user",
+                        "name": "author",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only posts created at or after this time (RFC 3339 or YYYY-MM-DD)",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only posts created before this time (RFC 3339 or YYYY-MM-DD)",
+                        "name": "created_before",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Successfully created user",
+                    "200": {
+                        "description": "GeoJSON FeatureCollection",
                         "schema": {
-                            "$ref": "#/definitions/server.UserResponseDTO"
+                            "$ref": "#/definitions/server.GeoJSONFeatureCollection"
                         }
                     },
                     "400": {
-                        "description": "Invalid request (e.g., missing 'userData', invalid JSON, invalid image, missing required fields in userData)",
-                        "schema": {
-                            "$ref": "#/definitions/server.ErrorResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "User with this email already exists",
+                        "description": "Invalid bounding box or filter",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error (e.g., failed to hash password, upload image, or create user)",
+                        "description": "Failed to export posts",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
@@ -1072,91 +1090,301 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{userID}": {
+        "/posts/in-bounds": {
             "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Retrieves user details for a given user ID.",
+                "description": "Retrieves posts inside 'bbox', nearest to 'lat'/'lng' (default: the centre of the box) first.\nWhen 'zoom' is below 14, nearby posts are grouped into clusters instead and 'clustered' is true.\nA clustered box may span at most 4096 cluster cells of 60 pixels at its zoom level, and at most that\nmany clusters are returned; larger boxes get a 400, so clients should send the zoom of the map they show.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Posts"
                 ],
-                "summary": "Get user by ID",
+                "summary": "List posts in a bounding box",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "User ID",
-                        "name": "userID",
-                        "in": "path",
+                        "example": "106.8,47.85,107.0,47.95",
+                        "description": "Bounding box as min_lng,min_lat,max_lng,max_lat",
+                        "name": "bbox",
+                        "in": "query",
                         "required": true
-                    }
-                ],
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Map zoom level, 0-22; below 14 the response is clustered",
+                        "name": "zoom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Latitude to sort by distance from",
+                        "name": "lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude to sort by distance from",
+                        "name": "lng",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only posts with this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only posts with this priority",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only posts of this type",
+                        "name": "post_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Only posts in this category",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of posts when not clustered, 1-100 (default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved user",
+                        "description": "Posts or clusters",
                         "schema": {
-                            "$ref": "#/definitions/server.UserResponseDTO"
+                            "$ref": "#/definitions/server.PostsInBoundsResponseDTO"
                         }
                     },
                     "400": {
-                        "description": "Invalid user ID format",
+                        "description": "Invalid bounding box, zoom or filter, or a box too large for its zoom level",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "500": {
+                        "description": "Failed to retrieve posts",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
+                    }
+                }
+            }
+        },
+        "/posts/nearby": {
+            "get": {
+                "description": "Retrieves posts within 'radius' metres of a point, nearest first. Posts without a location are never included.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Posts"
+                ],
+                "summary": "List nearby posts",
+                "parameters": [
+                    {
+                        "maximum": 90,
+                        "minimum": -90,
+                        "type": "number",
+                        "description": "Latitude of the point",
+                        "name": "lat",
+                        "in": "query",
+                        "required": true
                     },
-                    "404": {
-                        "description": "User not found",
+                    {
+                        "maximum": 180,
+                        "minimum": -180,
+                        "type": "number",
+                        "description": "Longitude of the point",
+                        "name": "lng",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Search radius in metres, up to 50000 (default 1000)",
+                        "name": "radius",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only posts with this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only posts with this priority",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only posts of this type",
+                        "name": "post_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Only posts in this category",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of posts, 1-100 (default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Posts ordered by distance",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/server.GeoPostDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid point, radius or filter",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Failed to get user",
+                        "description": "Failed to retrieve posts",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/posts/search": {
+            "get": {
+                "description": "Finds posts whose title, description or address contain every word of 'q', best matches first.\nWords match by prefix, so 'Гэрэлтүүлэг' also finds 'гэрэлтүүлэгийн'. Title matches rank above description and address matches.\nHighlights are HTML-escaped with matches wrapped in \u003cmark\u003e tags.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Posts"
+                ],
+                "summary": "Search posts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search text",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only posts with this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only posts with this priority",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only posts of this type",
+                        "name": "post_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Only posts in this category",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of results, 1-100 (default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Matching posts",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/server.PostSearchResultDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Missing search text or invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to search posts",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/volunteers/{userId}": {
             "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes a user account. Authenticated user can delete their own account. (Admin functionality can be added).",
+                "description": "Allows a post owner to remove a volunteer or a volunteer to remove their own application from a post.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Posts",
+                    "Volunteers"
                 ],
-                "summary": "Delete user by ID",
+                "summary": "Remove a volunteer from a post",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "User ID of the account to delete",
-                        "name": "userID",
+                        "description": "Volunteer's User ID to remove",
+                        "name": "userId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID from which to remove the volunteer",
+                        "name": "postId",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "message: User deleted successfully",
+                        "description": "message: Volunteer removed successfully",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1165,7 +1393,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid user ID format",
+                        "description": "Invalid user ID or post ID format, or missing postId query parameter",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
@@ -1177,19 +1405,19 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Forbidden - cannot delete another user's account",
+                        "description": "Forbidden - not authorized to remove this volunteer",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "User not found to delete",
+                        "description": "Post not found",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Failed to delete user",
+                        "description": "Failed to delete post volunteer",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
@@ -1197,97 +1425,126 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{userId}/posts": {
+        "/posts/{postId}": {
             "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Retrieves all posts created by a specific user.",
+                "description": "Retrieves details for a specific post, including images and volunteer counts. The author of the post\nlists its volunteers with GET /posts/{postId}/volunteers.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Posts"
                 ],
-                "summary": "Get posts by user ID",
+                "summary": "Get post by ID",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "User ID",
-                        "name": "userId",
+                        "description": "Post ID",
+                        "name": "postId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from an earlier response; a 304 is returned while it is current",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved user's posts",
+                        "description": "Successfully retrieved post",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/server.PostResponseDTO"
+                            "$ref": "#/definitions/server.PostResponseDTO"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the post, for If-Match and If-None-Match"
                             }
                         }
                     },
+                    "304": {
+                        "description": "Post has not changed"
+                    },
                     "400": {
-                        "description": "Invalid user ID format",
+                        "description": "Invalid post ID format",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Post not found",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Failed to retrieve user posts",
+                        "description": "Failed to retrieve post",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/users/{userId}/stats": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves statistics for a specific user, including post count, volunteer count, and approved post count.",
+                "description": "Updates details of a specific post. Only the owner of the post can update it.\nA changed status must be an allowed transition, see POST /posts/{postId}/status; it is recorded in the post's history.\nA complaint cannot be changed to another post_type.\nOmitted or empty fields keep their current value; title and description cannot be set empty.\nUse PATCH /posts/{postId} to clear optional fields.\nThe If-Match header must carry the post's current ETag so concurrent edits are not overwritten.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users",
-                    "Stats"
+                    "Posts"
                 ],
-                "summary": "Get user statistics",
+                "summary": "Update post by ID",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "User ID",
-                        "name": "userId",
+                        "description": "Post ID",
+                        "name": "postId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the post from GET /posts/{postId}",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Post update details",
+                        "name": "postUpdateData",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.UpdatePostRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved user statistics",
+                        "description": "Successfully updated post",
                         "schema": {
-                            "$ref": "#/definitions/db.GetUserStatsRow"
+                            "$ref": "#/definitions/server.PostResponseDTO"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the post"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid user ID format",
+                        "description": "Invalid request payload or post ID format",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
@@ -1298,200 +1555,5612 @@ const docTemplate = `{
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden - not authorized to update this post",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found to update",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Status transition or post_type change not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Post was modified since the ETag was issued",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header missing",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
                     "500": {
-                        "description": "Failed to retrieve user stats",
+                        "description": "Failed to update post",
                         "schema": {
                             "$ref": "#/definitions/server.ErrorResponse"
                         }
                     }
                 }
-            }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a specific post. Only the owner of the post or a moderator (posts:moderate) can delete it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Posts"
+                ],
+                "summary": "Delete post by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "message: Post deleted successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid post ID format",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not authorized to delete this post",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete post",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Applies a JSON Merge Patch (RFC 7396) to a post. Only the owner of the post can update it.\nKeys that are absent stay unchanged. An explicit null clears preview_url, category_id and address_text,\nand clears the location when given for both location_lat and location_lng; the other fields cannot be null.\nA changed status must be an allowed transition, see POST /posts/{postId}/status.\nA complaint cannot be changed to another post_type.\nThe If-Match header must carry the post's current ETag so concurrent edits are not overwritten.",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Posts"
+                ],
+                "summary": "Partially update post",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the post from GET /posts/{postId}",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "postPatch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.PatchPostRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully updated post",
+                        "schema": {
+                            "$ref": "#/definitions/server.PostResponseDTO"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the post"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid patch document, field value or post ID format",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not authorized to update this post",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Status transition or post_type change not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Post was modified since the ETag was issued",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header missing",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update post",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{postId}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns every status the post has been in, oldest first, with who moved it there and their comment.\nThe first entry is the status the post was created with and has no from_status.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Posts"
+                ],
+                "summary": "Get post status history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Status transitions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/server.PostStatusHistoryDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid post ID format",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve post history",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{postId}/images": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the images of a post in display order. The first one is the primary image, which is also the post's preview.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Posts"
+                ],
+                "summary": "List post images",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Images in display order",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/server.PostImageDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid post ID format",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve post images",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Uploads images and appends them to the post. A post has at most 5 images.\nWhen the post had none, the first new image becomes its primary image and preview. Only the author can add images.\nThe If-Match header must carry the post's current ETag so concurrent changes are not overwritten.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Posts"
+                ],
+                "summary": "Add post images",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the post from GET /posts/{postId}",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Image files (max 5MB each, types: jpeg, png, gif, webp)",
+                        "name": "postImages",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "All images of the post in display order",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/server.PostImageDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "No images, too many images, or an invalid image",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the author of the post",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Images were changed concurrently",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Post was modified since the ETag was issued",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header missing",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to add images",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{postId}/images/order": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sets the display order of the post's images. 'image_ids' must list every image of the post exactly once;\nthe first one becomes the primary image and the post's preview. Only the author can reorder images.\nThe If-Match header must carry the post's current ETag so concurrent changes are not overwritten.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Posts"
+                ],
+                "summary": "Reorder post images",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the post from GET /posts/{postId}",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Every image ID in the new order",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.ReorderPostImagesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Images in the new order",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/server.PostImageDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload or image list",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the author of the post",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Images were changed concurrently",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Post was modified since the ETag was issued",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header missing",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to reorder images",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{postId}/images/{imageId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes the image and its stored file. The images after it move up; when the primary image is removed the next\none becomes primary and the post's preview. The author or a moderator (posts:moderate) can delete images.\nThe If-Match header must carry the post's current ETag so concurrent changes are not overwritten.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Posts"
+                ],
+                "summary": "Delete post image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Image ID",
+                        "name": "imageId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the post from GET /posts/{postId}",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Remaining images in display order",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/server.PostImageDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid post or image ID format",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the author of the post or a moderator",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post or image not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Post was modified since the ETag was issued",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header missing",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete image",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{postId}/images/{imageId}/primary": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Moves the image to the front of the post's images, making it the primary image and the post's preview.\nThe other images keep their relative order. Only the author can choose the primary image.\nThe If-Match header must carry the post's current ETag so concurrent changes are not overwritten.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Posts"
+                ],
+                "summary": "Set primary post image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Image ID",
+                        "name": "imageId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the post from GET /posts/{postId}",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Images in the new order",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/server.PostImageDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid post or image ID format",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the author of the post",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post or image not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Images were changed concurrently",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Post was modified since the ETag was issued",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header missing",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to reorder images",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{postId}/shifts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the shifts of a post in start order, with how many volunteers signed up for each.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Posts",
+                    "Volunteers"
+                ],
+                "summary": "List post shifts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Shifts of the post",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/server.PostShiftDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid post ID format",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve shifts",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a time slot with its own capacity and optional meeting point. Approved volunteers of the post sign up\nfor shifts. A shift lasts at most 24 hours and its capacity cannot exceed the post's max_volunteers.\nOnly the author of a pending or in-progress post can add shifts.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Posts",
+                    "Volunteers"
+                ],
+                "summary": "Add a shift to a post",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Shift details",
+                        "name": "shift",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.CreateShiftRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Shift created",
+                        "schema": {
+                            "$ref": "#/definitions/server.PostShiftDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload, times, capacity or meeting point",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the author of the post",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The post is no longer active",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create shift",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{postId}/shifts/{shiftId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a shift and its sign-ups. Shifts that have started are kept, since their check-ins count towards volunteered hours.\nOnly the author of the post can delete shifts.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Posts",
+                    "Volunteers"
+                ],
+                "summary": "Delete a shift",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Shift ID",
+                        "name": "shiftId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "message: Shift deleted successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid post or shift ID format",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the author of the post",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post or shift not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The shift has already started",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete shift",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{postId}/shifts/{shiftId}/check-in": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Records the check-in time and, optionally, where the volunteer is. Check-in opens 30 minutes before the shift starts and closes when it ends.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Posts",
+                    "Volunteers"
+                ],
+                "summary": "Check in to a shift",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Shift ID",
+                        "name": "shiftId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Optional current location",
+                        "name": "location",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/server.ShiftCheckRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Checked in",
+                        "schema": {
+                            "$ref": "#/definitions/server.ShiftSignupDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format or location",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Shift not found or not signed up",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Already checked in or outside the check-in window",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to check in",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{postId}/shifts/{shiftId}/check-out": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Records the check-out time and, optionally, where the volunteer is. The time inside the shift between check-in\nand check-out counts towards the user's volunteered hours. Once no other shift of the post is still to come,\na volunteer who has checked out of every shift they signed up for on the post is marked completed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Posts",
+                    "Volunteers"
+                ],
+                "summary": "Check out of a shift",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Shift ID",
+                        "name": "shiftId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Optional current location",
+                        "name": "location",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/server.ShiftCheckRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Checked out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ShiftSignupDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format or location",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Shift not found or not signed up",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Not checked in, or already checked out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to check out",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{postId}/shifts/{shiftId}/signup": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Takes a place on a shift. Only approved volunteers of the post can sign up, and only until the shift ends.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Posts",
+                    "Volunteers"
+                ],
+                "summary": "Sign up for a shift",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Shift ID",
+                        "name": "shiftId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Signed up",
+                        "schema": {
+                            "$ref": "#/definitions/server.ShiftSignupDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid post or shift ID format",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an approved volunteer of the post",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Shift not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Already signed up, shift is full or shift is over",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to sign up",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Gives up a place on a shift that has not been checked in to.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Posts",
+                    "Volunteers"
+                ],
+                "summary": "Withdraw from a shift",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Shift ID",
+                        "name": "shiftId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "message: Withdrawn from shift",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid post or shift ID format",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Shift not found or not signed up",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Already checked in",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to withdraw",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{postId}/shifts/{shiftId}/signups": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the volunteers signed up for a shift with their check-in and check-out. Only the author of the post can see them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Posts",
+                    "Volunteers"
+                ],
+                "summary": "List shift sign-ups",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Shift ID",
+                        "name": "shiftId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sign-ups in the order they were made",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/server.ShiftSignupDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid post or shift ID format",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the author of the post",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post or shift not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve sign-ups",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{postId}/skills": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the skills the post needs from its volunteers.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Posts",
+                    "Volunteers"
+                ],
+                "summary": "Get needed skills of a post",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Needed skills",
+                        "schema": {
+                            "$ref": "#/definitions/server.PostSkillsDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid post ID format",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve skills",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces the skills the post needs from its volunteers. Skills are stored trimmed and lower-cased.\nOnly the author of the post can change them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Posts",
+                    "Volunteers"
+                ],
+                "summary": "Update needed skills of a post",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Needed skills",
+                        "name": "skills",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.PostSkillsDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated needed skills",
+                        "schema": {
+                            "$ref": "#/definitions/server.PostSkillsDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid post ID format or skills",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the author of the post",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update skills",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{postId}/status": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Moves a post to another status and records the transition in its history.\nPending posts can be started or cancelled, posts in progress can be paused, resolved or cancelled,\nand paused posts can be resumed or cancelled, by the author or a moderator (posts:moderate).\nOnly moderators can resolve a complaint or reopen a resolved or cancelled post.\nThe If-Match header must carry the post's current ETag so concurrent changes are not overwritten.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Posts"
+                ],
+                "summary": "Change post status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the post from GET /posts/{postId}",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "New status and an optional comment",
+                        "name": "statusChange",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.ChangePostStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The recorded transition",
+                        "schema": {
+                            "$ref": "#/definitions/server.PostStatusHistoryDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload or post ID format",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Caller may not make this transition",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Transition not allowed from the current status, or the status changed concurrently",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Post was modified since the ETag was issued",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header missing",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to change post status",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{postId}/suggested-volunteers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ranks users who are volunteering and have not applied yet. The score (0-1) weighs the share of the\npost's needed skills they have (0.5), the share of its upcoming shifts that fit their weekly availability\n(0.3) and how close they are based to the post, fading out at 50 km (0.2). With explain=true and an AI\nmodel configured, the top suggestions are re-ranked by the model with a short explanation each; no\nnames or contact details are sent to it. ai_ranked reports whether that happened.\nOnly the author of the post can see suggestions.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Posts",
+                    "Volunteers"
+                ],
+                "summary": "Suggest volunteers for a post",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of suggestions, 1-100 (default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Re-rank and explain the suggestions with the AI model",
+                        "name": "explain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Suggestions, best first",
+                        "schema": {
+                            "$ref": "#/definitions/server.SuggestedVolunteersDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid post ID format or query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the author of the post",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to suggest volunteers",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{postId}/volunteers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Counts the post's volunteer applications by status. The post's organizer also gets the applications\nwith the volunteers' profiles, one page at a time; phone and email are shown for approved volunteers\nwhose privacy settings let organizers see them. Follow 'next_cursor' until it is omitted to read every page.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Posts",
+                    "Volunteers"
+                ],
+                "summary": "List post volunteers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "approved",
+                            "completed",
+                            "rejected",
+                            "withdrawn",
+                            "no_show"
+                        ],
+                        "type": "string",
+                        "description": "Only applications with this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "-created_at"
+                        ],
+                        "type": "string",
+                        "description": "created_at (oldest first) or -created_at (newest first, default)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 1-100 (default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor from the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Counts, and for the organizer one page of volunteers",
+                        "schema": {
+                            "$ref": "#/definitions/server.PostVolunteersDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid post ID, filter or cursor",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve post volunteers",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a pending volunteer application for the caller, which the post's author can then approve or reject.\nOnly posts whose category takes volunteers, that are pending or in progress and that have a free place\naccept applications. Authors cannot volunteer on their own posts.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Posts",
+                    "Volunteers"
+                ],
+                "summary": "Volunteer on a post",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Optional notes for the organizer",
+                        "name": "application",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/server.ApplyVolunteerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Application created",
+                        "schema": {
+                            "$ref": "#/definitions/server.PostVolunteerDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid post ID format or request payload",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Email not verified, or the caller is the author of the post",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Already applied, post does not take volunteers, or no free places",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to apply",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{postId}/volunteers/withdraw": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Withdraws the caller's pending or approved application. An approved volunteer's place on the post and\ntheir shift places that were not checked in to are freed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Posts",
+                    "Volunteers"
+                ],
+                "summary": "Withdraw from a post",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Application withdrawn",
+                        "schema": {
+                            "$ref": "#/definitions/server.PostVolunteerDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid post ID format",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Volunteer application not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The application is no longer pending or approved",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to withdraw",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{postId}/volunteers/{userId}/complete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Marks an approved volunteer completed. Volunteers are also completed automatically when they check out\nof their last shift. Shift places they did not check in to are freed. Only the author of the post can complete volunteers.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Posts",
+                    "Volunteers"
+                ],
+                "summary": "Complete a volunteer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Volunteer's user ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Volunteer completed",
+                        "schema": {
+                            "$ref": "#/definitions/server.PostVolunteerDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid post or user ID format",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the author of the post",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post or volunteer application not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The volunteer is not approved",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to complete volunteer",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{postId}/volunteers/{userId}/no-show": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Marks an approved volunteer who did not turn up as no_show, which frees their place on the post and\ntheir shift places that were not checked in to. Only the author of the post can mark no-shows.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Posts",
+                    "Volunteers"
+                ],
+                "summary": "Mark a volunteer as no-show",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Post ID",
+                        "name": "postId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Volunteer's user ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Volunteer marked as no-show",
+                        "schema": {
+                            "$ref": "#/definitions/server.PostVolunteerDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid post or user ID format",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Not the author of the post",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post or volunteer application not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The volunteer is not approved",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to mark volunteer as no-show",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reject_volunteer": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Allows the owner of a post to reject a pending volunteer application.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Posts",
+                    "Volunteers"
+                ],
+                "summary": "Reject a volunteer for a post",
+                "parameters": [
+                    {
+                        "description": "Details of the post and volunteer to reject",
+                        "name": "rejectionData",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.ApproveRejectVolunteerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully rejected volunteer",
+                        "schema": {
+                            "$ref": "#/definitions/server.PostVolunteerDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload or missing IDs",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - not authorized to reject volunteers for this post",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post or volunteer application not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The application is not pending",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to reject volunteer",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/settings/security": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the security settings administrators can change at runtime. Requires the 'users:manage' permission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Settings"
+                ],
+                "summary": "Get security settings",
+                "responses": {
+                    "200": {
+                        "description": "Current security settings",
+                        "schema": {
+                            "$ref": "#/definitions/server.SecuritySettingsDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve security settings",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Turns the requirement for administrators to use two-factor authentication on or off.\nAdministrators whose session did not pass 2FA lose their privileges until they log in with it.\nTo avoid locking everyone out, only an administrator whose own session passed 2FA can turn it on.\nRequires the 'users:manage' permission.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Settings"
+                ],
+                "summary": "Update security settings",
+                "parameters": [
+                    {
+                        "description": "New security settings",
+                        "name": "settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.SecuritySettingsDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated security settings",
+                        "schema": {
+                            "$ref": "#/definitions/server.SecuritySettingsDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Caller's session did not pass two-factor authentication",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update security settings",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tiles/{z}/{x}/{y}.mvt": {
+            "get": {
+                "description": "Returns a Mapbox Vector Tile (XYZ scheme) with a 'posts' point layer. Features carry id, title, status,\npriority, post_type, category_id, category, address_text, created_at and updated_at properties.\nTakes the same filters as GET /posts. At most 5000 features are included per tile.",
+                "produces": [
+                    "application/vnd.mapbox-vector-tile"
+                ],
+                "tags": [
+                    "Posts"
+                ],
+                "summary": "Posts vector tile",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Zoom level, 0-22",
+                        "name": "z",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Tile column",
+                        "name": "x",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Tile row",
+                        "name": "y",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only posts with this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only posts with this priority",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only posts of this type",
+                        "name": "post_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Only posts in this category",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Only posts by this user",
+                        "name": "author",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only posts created at or after this time (RFC 3339 or YYYY-MM-DD)",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only posts created before this time (RFC 3339 or YYYY-MM-DD)",
+                        "name": "created_before",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Vector tile",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid tile coordinates or filter",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to render tile",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the public profiles of users, newest first. Callers with the 'users:manage' permission receive full UserResponse records instead.\nFollow 'next_cursor' until it is omitted to read every page.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "List users",
+                "parameters": [
+                    {
+                        "enum": [
+                            "ADMIN",
+                            "MODERATOR",
+                            "USER"
+                        ],
+                        "type": "string",
+                        "description": "Only users with this role",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only users who are (or are not) volunteering",
+                        "name": "is_volunteering",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "-created_at"
+                        ],
+                        "type": "string",
+                        "description": "created_at (oldest first) or -created_at (newest first, default)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 1-100 (default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor from the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "One page of users",
+                        "schema": {
+                            "$ref": "#/definitions/server.PageDTO-server_PublicUserProfileDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter or cursor",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to list users",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/by-email": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves user details for a given email address. Requires the 'users:manage' permission so addresses cannot be enumerated.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get user by email",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"jane.doe@example.com\"",
+                        "description": "Email address of the user",
+                        "name": "email",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved user",
+                        "schema": {
+                            "$ref": "#/definitions/server.UserResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Email query parameter is required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User with this email not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get user by email",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/login": {
+            "post": {
+                "description": "Authenticates a user with email and password, returns a short-lived JWT access token, a refresh token and user details.\nRepeated failures for an email or client address are answered with exponential back-off, and enough\nconsecutive failures lock the account temporarily. Both are reported as 429 with a Retry-After header.\nAccounts with two-factor authentication get a 202 with a short-lived 'mfa_token' instead of a session.\nAn account scheduled for deletion is only restored once the second factor has been checked too.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Login a user",
+                "parameters": [
+                    {
+                        "description": "User login credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.LoginRequestPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully logged in",
+                        "schema": {
+                            "$ref": "#/definitions/server.LoginResponsePayloadDTO"
+                        }
+                    },
+                    "202": {
+                        "description": "Password accepted; complete the login at POST /auth/mfa/verify",
+                        "schema": {
+                            "$ref": "#/definitions/server.MFAChallengeResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload or missing fields",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid email or password",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Account is scheduled for deletion and restore_account was not set",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Account has been deleted",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed login attempts",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until the next attempt will be evaluated"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Login failed or failed to generate token",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves details for the currently authenticated user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get current user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag from an earlier response; a 304 is returned while it is current",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved user",
+                        "schema": {
+                            "$ref": "#/definitions/server.UserResponseDTO"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the user, for If-Match and If-None-Match"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "User has not changed"
+                    },
+                    "401": {
+                        "description": "Authentication required or user not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get user",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/api-keys": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the current user's API keys, including revoked and expired ones, newest first. Secrets are never returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "List API keys",
+                "responses": {
+                    "200": {
+                        "description": "API keys",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/server.APIKeyDTO"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Request made with an API key",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve API keys",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a key for calling the API without logging in; send it in the 'X-API-Key' header instead of a Bearer token.\nThe key acts as its owner with these scopes: 'read' (GET requests), 'write' (all other requests) and any\npermission of the owner's role, e.g. 'posts:moderate'. The key is only returned once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Create an API key",
+                "parameters": [
+                    {
+                        "description": "Name, scopes and lifetime (1-365 days, default 90)",
+                        "name": "apiKey",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.CreateAPIKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "API key created",
+                        "schema": {
+                            "$ref": "#/definitions/server.APIKeyCreatedResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid name, scopes or lifetime",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission scope not held by the caller, or request made with an API key",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create API key",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/api-keys/{keyID}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revokes the key immediately. Revoked keys stay listed for reference.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Revoke an API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "API key ID",
+                        "name": "keyID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "message: API key revoked",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid API key ID format",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Request made with an API key",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "API key not found or already revoked",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to revoke API key",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/details": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates the first name, last name, phone, volunteering status, and profile URL for the authenticated user.\nTurning volunteering on requires a verified email address.\nThe If-Match header must carry the user's current ETag from GET /users/me.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update current user's details",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of the user from GET /users/me",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "User details to update",
+                        "name": "userDetails",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.UpdateUserDetailsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully updated user details",
+                        "schema": {
+                            "$ref": "#/definitions/server.UserResponseDTO"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the user"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Email address not verified",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found to update",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "User was modified since the ETag was issued",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header missing",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update user details",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/email": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Emails a confirmation link to the new address. The email is only changed once the link is redeemed\nthrough POST /auth/confirm-email-change, which also revokes all sessions of the user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update current user's email",
+                "parameters": [
+                    {
+                        "description": "New email address and current password",
+                        "name": "emailUpdate",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.UpdateUserEmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "message: Confirmation email sent to the new address",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload, email or current password empty, or email unchanged",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Current password is incorrect",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found to update email",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "This email is already in use",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update user email",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Downloads a ZIP archive with the current user's profile (profile.json), posts (posts.json),\nvolunteer history (volunteering.json), shift sign-ups with check-ins (shifts.json), skills, availability\nand location (volunteer_profile.json) and the uploaded profile and post images (files/).\nRequires a logged-in session; API keys cannot export account data.",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Export account data",
+                "responses": {
+                    "200": {
+                        "description": "ZIP archive",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Request made with an API key",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to export account data",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/mfa": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns whether two-factor authentication is enabled, how many recovery codes are left and whether the current session passed 2FA.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get two-factor status",
+                "responses": {
+                    "200": {
+                        "description": "Two-factor status",
+                        "schema": {
+                            "$ref": "#/definitions/server.MFAStatusDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve two-factor status",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/mfa/disable": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Turns two-factor authentication off after checking the current password and a TOTP or recovery code.\nAdministrators cannot disable it while two-factor authentication is required for administrators.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Disable two-factor authentication",
+                "parameters": [
+                    {
+                        "description": "Current password and code",
+                        "name": "disable",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.DisableMFARequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "message: Two-factor authentication disabled",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload, missing fields or invalid code",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Current password is incorrect or 2FA is required for administrators",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Two-factor authentication is not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to disable two-factor authentication",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/mfa/enable": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Confirms the secret from POST /users/me/mfa/setup with a current code and turns two-factor authentication on.\nReturns single-use recovery codes, which are only shown once. Other sessions of the user are revoked.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Enable two-factor authentication",
+                "parameters": [
+                    {
+                        "description": "Code from the authenticator app",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.MFACodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Two-factor authentication enabled",
+                        "schema": {
+                            "$ref": "#/definitions/server.MFARecoveryCodesResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload, no enrollment in progress or invalid code",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Two-factor authentication is already enabled",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to enable two-factor authentication",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/mfa/recovery-codes": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Invalidates all existing recovery codes and returns a new set after checking a TOTP or recovery code.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Regenerate recovery codes",
+                "parameters": [
+                    {
+                        "description": "Current TOTP or recovery code",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.MFACodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "New recovery codes",
+                        "schema": {
+                            "$ref": "#/definitions/server.MFARecoveryCodesResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload or invalid code",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Two-factor authentication is not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to regenerate recovery codes",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/mfa/setup": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Generates a new TOTP secret for the current user. Two-factor authentication is only turned on once\na code from the authenticator app is confirmed through POST /users/me/mfa/enable.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Start two-factor enrollment",
+                "responses": {
+                    "200": {
+                        "description": "Secret and otpauth URL to add to an authenticator app",
+                        "schema": {
+                            "$ref": "#/definitions/server.MFASetupResponseDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Two-factor authentication is already enabled",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to start two-factor enrollment",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/password": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates the password for the authenticated user after checking the current one.\nThe new password must satisfy the password policy. All other sessions of the user are revoked.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update current user's password",
+                "parameters": [
+                    {
+                        "description": "Current and new password",
+                        "name": "passwordUpdate",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.UpdateUserPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "message: Password updated successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload or current/new password empty",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Current password is incorrect",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found to update password",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "New password does not meet the password policy",
+                        "schema": {
+                            "$ref": "#/definitions/server.PasswordPolicyErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to hash new password or update user password",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/privacy": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns who may see the current user's phone number and email address on their public profile.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get privacy settings",
+                "responses": {
+                    "200": {
+                        "description": "Privacy settings",
+                        "schema": {
+                            "$ref": "#/definitions/server.PrivacySettingsDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve privacy settings",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sets who may see the current user's phone number and email address: 'private', 'organizers' or 'public'.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update privacy settings",
+                "parameters": [
+                    {
+                        "description": "New privacy settings",
+                        "name": "settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.PrivacySettingsDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated privacy settings",
+                        "schema": {
+                            "$ref": "#/definitions/server.PrivacySettingsDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid visibility value",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update privacy settings",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/volunteer-profile": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the current user's skills, weekly availability and location used to suggest them to organizers.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users",
+                    "Volunteers"
+                ],
+                "summary": "Get volunteer profile",
+                "responses": {
+                    "200": {
+                        "description": "Volunteer profile",
+                        "schema": {
+                            "$ref": "#/definitions/server.VolunteerProfileDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve volunteer profile",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces the current user's skills, weekly availability and location. Skills are stored trimmed and\nlower-cased. Availability weekdays run from 0 (Sunday) to 6 (Saturday) and times are HH:MM in the\nserver's time zone. Omit the location, or send 0,0, to clear it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users",
+                    "Volunteers"
+                ],
+                "summary": "Update volunteer profile",
+                "parameters": [
+                    {
+                        "description": "New volunteer profile",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.VolunteerProfileDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated volunteer profile",
+                        "schema": {
+                            "$ref": "#/definitions/server.VolunteerProfileDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid skills, availability or location",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update volunteer profile",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/volunteering": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the caller's volunteer applications with their posts and organizers, one page at a time.\nFollow 'next_cursor' until it is omitted to read every page.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users",
+                    "Volunteers"
+                ],
+                "summary": "List my volunteering",
+                "parameters": [
+                    {
+                        "enum": [
+                            "pending",
+                            "approved",
+                            "completed",
+                            "rejected",
+                            "withdrawn",
+                            "no_show"
+                        ],
+                        "type": "string",
+                        "description": "Only applications with this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "-created_at"
+                        ],
+                        "type": "string",
+                        "description": "created_at (oldest first) or -created_at (newest first, default)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 1-100 (default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor from the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "One page of applications",
+                        "schema": {
+                            "$ref": "#/definitions/server.PageDTO-server_VolunteeringDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter or cursor",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve volunteering",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/register": {
+            "post": {
+                "description": "Registers a new user. User details are sent as a JSON string in the 'userData' form field.\nSelf-registered accounts always get the USER role; any 'role' in userData is ignored.\nA verification link is emailed to the address. Creating posts and volunteering require a verified email,\nso 'is_volunteering' is ignored at registration.\nOptionally, a profile image can be uploaded via the 'profileImage' form field.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Create a new user (with optional profile image)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User registration details as a JSON string. Example: '{\\",
+                        "name": "userData",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Optional profile image file (max 5MB, types: jpeg, png, gif, webp)",
+                        "name": "profileImage",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully created user",
+                        "schema": {
+                            "$ref": "#/definitions/server.UserResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request (e.g., missing 'userData', invalid JSON, invalid image, missing required fields in userData)",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "User with this email already exists",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Password does not meet the password policy",
+                        "schema": {
+                            "$ref": "#/definitions/server.PasswordPolicyErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error (e.g., failed to hash password, upload image, or create user)",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userID}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the public profile of a user. Contact details are included as the user's privacy settings allow.\nThe user themselves and callers with the 'users:manage' permission receive the full UserResponse instead.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get user by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "User ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved user",
+                        "schema": {
+                            "$ref": "#/definitions/server.PublicUserProfileDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID format",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to get user",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Schedules a user account for deletion. Authenticated users can delete their own account; administrators (users:manage) can delete any account.\nThe account is signed out everywhere and can be restored until purge_after, after which its profile, images and volunteer records are removed for good.\nPosts authored by the account are kept without an author.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Delete user by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "User ID of the account to delete",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Account scheduled for deletion",
+                        "schema": {
+                            "$ref": "#/definitions/server.AccountDeletionResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID format",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - cannot delete another user's account",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found to delete",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete user",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userID}/lockouts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns every lockout recorded for the account, newest first. Requires the 'users:manage' permission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "List account lockouts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "User ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Lockout history",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/server.AccountLockoutDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID format",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve lockouts",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userID}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancels the deletion of an account that is still within its grace period. Requires the 'users:manage' permission.\nUsers can restore their own account by logging in with 'restore_account' set.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Restore a deleted account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "User ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Account restored",
+                        "schema": {
+                            "$ref": "#/definitions/server.UserResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID format",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "No restorable deletion for this user",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to restore account",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userID}/role": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Promotes or demotes a user to ADMIN, MODERATOR or USER. Requires the users:manage permission.\nAdministrators cannot change their own role, so the last administrator cannot lock everyone out.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users",
+                    "Admin"
+                ],
+                "summary": "Change a user's role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "User ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New role",
+                        "name": "roleUpdate",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/server.UpdateUserRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully updated user role",
+                        "schema": {
+                            "$ref": "#/definitions/server.UserResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID, payload or role",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission or attempt to change own role",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update user role",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userID}/unlock": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lifts any active lockout of the account and clears its failed login counter. Requires the 'users:manage' permission.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Unlock a user account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "User ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "message: Account unlocked",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID format",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Missing permission",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to unlock account",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/posts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the posts created by a specific user, newest first, one page at a time.\nAccepts the same filters and paging parameters as GET /posts, except 'author'.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Posts"
+                ],
+                "summary": "Get posts by user ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only posts with this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only posts with this priority",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only posts of this type",
+                        "name": "post_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Only posts in this category",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only posts created at or after this time (RFC 3339 or YYYY-MM-DD)",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only posts created before this time (RFC 3339 or YYYY-MM-DD)",
+                        "name": "created_before",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "-created_at"
+                        ],
+                        "type": "string",
+                        "description": "created_at (oldest first) or -created_at (newest first, default)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 1-100 (default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor from the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "One page of the user's posts",
+                        "schema": {
+                            "$ref": "#/definitions/server.PageDTO-server_PostResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID, filter or cursor",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve user posts",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves statistics for a specific user, including post count, volunteer count, approved post count and hours volunteered on shifts.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users",
+                    "Stats"
+                ],
+                "summary": "Get user statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved user statistics",
+                        "schema": {
+                            "$ref": "#/definitions/db.GetUserStatsRow"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID format",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication required",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve user stats",
+                        "schema": {
+                            "$ref": "#/definitions/server.ErrorResponse"
+                        }
+                    }
+                }
+            }
         }
     },
     "definitions": {
         "db.GetUserStatsRow": {
             "type": "object",
             "properties": {
-                "approvedPosts": {
-                    "type": "integer"
+                "approvedPosts": {
+                    "type": "integer"
+                },
+                "userPostsCount": {
+                    "type": "integer"
+                },
+                "userVolunteerCount": {
+                    "type": "integer"
+                },
+                "volunteeredHours": {
+                    "type": "number"
+                }
+            }
+        },
+        "db.ProfileFieldVisibility": {
+            "type": "string",
+            "enum": [
+                "private",
+                "organizers",
+                "public"
+            ],
+            "x-enum-varnames": [
+                "ProfileFieldVisibilityPrivate",
+                "ProfileFieldVisibilityOrganizers",
+                "ProfileFieldVisibilityPublic"
+            ]
+        },
+        "password.Violation": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Password must be at least 8 characters long"
+                },
+                "rule": {
+                    "type": "string",
+                    "example": "min_length"
+                }
+            }
+        },
+        "server.APIKeyCreatedResponseDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "key": {
+                    "type": "string",
+                    "example": "hk_Zx81kQ2a..."
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Weekly report script"
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "hk_Zx81kQ2a"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "read"
+                    ]
+                }
+            }
+        },
+        "server.APIKeyDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Weekly report script"
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "hk_Zx81kQ2a"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "read"
+                    ]
+                }
+            }
+        },
+        "server.AccountDeletionResponseDTO": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Account scheduled for deletion"
+                },
+                "purge_after": {
+                    "type": "string",
+                    "example": "2023-02-01T12:00:00Z"
+                }
+            }
+        },
+        "server.AccountLockoutDTO": {
+            "type": "object",
+            "properties": {
+                "failed_attempts": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "ip_address": {
+                    "type": "string",
+                    "example": "203.0.113.7"
+                },
+                "locked_at": {
+                    "type": "string"
+                },
+                "locked_until": {
+                    "type": "string"
+                },
+                "unlocked_at": {
+                    "type": "string"
+                },
+                "unlocked_by": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "server.ApplyVolunteerRequest": {
+            "type": "object",
+            "properties": {
+                "notes": {
+                    "type": "string",
+                    "example": "I can bring gloves and bags."
+                }
+            }
+        },
+        "server.ApproveRejectVolunteerRequest": {
+            "type": "object",
+            "properties": {
+                "post_id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "a1b2c3d4-e5f6-7777-8888-99990000aaaa"
+                },
+                "volunteer_user_id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "a1b2c3d4-e5f6-7777-8888-99990000bbbb"
+                }
+            }
+        },
+        "server.AvailabilitySlotDTO": {
+            "type": "object",
+            "properties": {
+                "end_time": {
+                    "type": "string",
+                    "example": "13:00"
+                },
+                "start_time": {
+                    "type": "string",
+                    "example": "09:00"
+                },
+                "weekday": {
+                    "type": "integer",
+                    "maximum": 6,
+                    "minimum": 0,
+                    "example": 6
+                }
+            }
+        },
+        "server.CategoryDTO": {
+            "type": "object",
+            "properties": {
+                "can_volunteer": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "endpoint": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.ChangePostStatusRequest": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "example": "Хогийг ачиж дууслаа"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "Шийдвэрлэгдсэн"
+                }
+            }
+        },
+        "server.CreateAPIKeyRequest": {
+            "type": "object",
+            "properties": {
+                "expires_in_days": {
+                    "type": "integer",
+                    "example": 90
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Weekly report script"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "read"
+                    ]
+                }
+            }
+        },
+        "server.CreateCategoryRequest": {
+            "type": "object",
+            "properties": {
+                "can_volunteer": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Хог хаягдлын цэг, цэвэрлэгээ"
+                },
+                "endpoint": {
+                    "type": "string",
+                    "example": "waste"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Хог хаягдал"
+                }
+            }
+        },
+        "server.CreateShiftRequest": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "ends_at": {
+                    "type": "string",
+                    "example": "2025-06-01T12:00:00+08:00"
+                },
+                "meeting_lat": {
+                    "type": "number",
+                    "example": 47.9187
+                },
+                "meeting_lng": {
+                    "type": "number",
+                    "example": 106.917
+                },
+                "meeting_point": {
+                    "type": "string",
+                    "example": "Main gate of the park"
+                },
+                "starts_at": {
+                    "type": "string",
+                    "example": "2025-06-01T09:00:00+08:00"
+                }
+            }
+        },
+        "server.DisableMFARequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                },
+                "current_password": {
+                    "type": "string",
+                    "example": "strongpassword123"
+                }
+            }
+        },
+        "server.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.ForgotPasswordRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "john.doe@example.com"
+                }
+            }
+        },
+        "server.GeoJSONFeature": {
+            "type": "object",
+            "properties": {
+                "geometry": {
+                    "$ref": "#/definitions/server.GeoJSONPoint"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "properties": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "type": {
+                    "type": "string",
+                    "example": "Feature"
+                }
+            }
+        },
+        "server.GeoJSONFeatureCollection": {
+            "type": "object",
+            "properties": {
+                "features": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.GeoJSONFeature"
+                    }
+                },
+                "type": {
+                    "type": "string",
+                    "example": "FeatureCollection"
+                }
+            }
+        },
+        "server.GeoJSONPoint": {
+            "type": "object",
+            "properties": {
+                "coordinates": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "type": {
+                    "type": "string",
+                    "example": "Point"
+                }
+            }
+        },
+        "server.GeoPostDTO": {
+            "type": "object",
+            "properties": {
+                "address_text": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "distance_m": {
+                    "description": "From the search point",
+                    "type": "number",
+                    "example": 350.5
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "location_lat": {
+                    "type": "number",
+                    "example": 47.9187
+                },
+                "location_lng": {
+                    "type": "number",
+                    "example": 106.917
+                },
+                "post_type": {
+                    "type": "string"
+                },
+                "preview_url": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.LoginRequestPayload": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "john.doe@example.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "strongpassword123"
+                },
+                "restore_account": {
+                    "description": "Restores an account scheduled for deletion instead of rejecting the login",
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "server.LoginResponsePayloadDTO": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2023-01-01T12:15:00Z"
+                },
+                "refresh_token": {
+                    "type": "string",
+                    "example": "5q8b0Qm1..."
+                },
+                "token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                },
+                "user": {
+                    "$ref": "#/definitions/server.UserResponseDTO"
+                }
+            }
+        },
+        "server.LogoutRequest": {
+            "type": "object",
+            "properties": {
+                "all_sessions": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "server.MFAChallengeResponseDTO": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "mfa_required": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "mfa_token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                }
+            }
+        },
+        "server.MFACodeRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "server.MFARecoveryCodesResponseDTO": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "k3j9a-x7q2m"
+                    ]
+                }
+            }
+        },
+        "server.MFASetupResponseDTO": {
+            "type": "object",
+            "properties": {
+                "otpauth_url": {
+                    "type": "string",
+                    "example": "otpauth://totp/Hackathon:john.doe@example.com?secret=JBSWY3DPEHPK3PXP\u0026issuer=Hackathon"
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+                }
+            }
+        },
+        "server.MFAStatusDTO": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "enabled_at": {
+                    "type": "string"
+                },
+                "recovery_codes_remaining": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "session_verified": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "server.OIDCExchangeRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "Xq3v9kT0..."
+                }
+            }
+        },
+        "server.OIDCProvidersResponseDTO": {
+            "type": "object",
+            "properties": {
+                "providers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "google",
+                        "stub"
+                    ]
+                }
+            }
+        },
+        "server.PageDTO-server_PostResponseDTO": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.PostResponseDTO"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "example": "eyJ0IjoiMjAyMy0wMS0wMVQxMjowMDowMFoiLCJpZCI6ImExYjJjM2Q0In0"
+                }
+            }
+        },
+        "server.PageDTO-server_PublicUserProfileDTO": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.PublicUserProfileDTO"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "example": "eyJ0IjoiMjAyMy0wMS0wMVQxMjowMDowMFoiLCJpZCI6ImExYjJjM2Q0In0"
+                }
+            }
+        },
+        "server.PageDTO-server_VolunteeringDTO": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.VolunteeringDTO"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "example": "eyJ0IjoiMjAyMy0wMS0wMVQxMjowMDowMFoiLCJpZCI6ImExYjJjM2Q0In0"
+                }
+            }
+        },
+        "server.PasswordPolicyErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "Password does not meet the password policy"
+                },
+                "violations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/password.Violation"
+                    }
+                }
+            }
+        },
+        "server.PatchPostRequest": {
+            "type": "object",
+            "properties": {
+                "address_text": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": "Sukhbaatar Square, Ulaanbaatar"
+                },
+                "category_id": {
+                    "type": "string",
+                    "format": "uuid",
+                    "x-nullable": true
+                },
+                "description": {
+                    "type": "string",
+                    "example": "The local park needs volunteers urgently."
+                },
+                "location_lat": {
+                    "type": "number",
+                    "x-nullable": true,
+                    "example": 47.92
+                },
+                "location_lng": {
+                    "type": "number",
+                    "x-nullable": true,
+                    "example": 106.925
+                },
+                "max_volunteers": {
+                    "type": "integer",
+                    "example": 15
+                },
+                "post_type": {
+                    "type": "string",
+                    "example": "хандив"
+                },
+                "preview_url": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": "http://example.com/new_image.jpg"
+                },
+                "priority": {
+                    "type": "string",
+                    "example": "өндөр"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "Шийдвэрлэгдэж байгаа"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Urgent: Park Cleanup Drive"
+                }
+            }
+        },
+        "server.PostClusterDTO": {
+            "type": "object",
+            "properties": {
+                "bbox": {
+                    "description": "Bounds of the posts in the cluster as min_lng,min_lat,max_lng,max_lat, for zooming in",
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "location_lat": {
+                    "description": "Centre of the posts in the cluster",
+                    "type": "number",
+                    "example": 47.9187
+                },
+                "location_lng": {
+                    "type": "number",
+                    "example": 106.917
+                },
+                "post_id": {
+                    "description": "Set when the cluster holds a single post",
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "server.PostImageDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "is_primary": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "position": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "url": {
+                    "type": "string",
+                    "example": "http://localhost:9000/hackathon/post_images/9b2e.../1700000000_abc.jpg"
+                }
+            }
+        },
+        "server.PostResponseDTO": {
+            "type": "object",
+            "properties": {
+                "address_text": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "description": "Omitted for uncategorized posts",
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "current_volunteers": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "images": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "location_lat": {
+                    "type": "number"
+                },
+                "location_lng": {
+                    "type": "number"
+                },
+                "max_volunteers": {
+                    "type": "integer"
+                },
+                "post_type": {
+                    "type": "string"
+                },
+                "preview_url": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "server.PostSearchResultDTO": {
+            "type": "object",
+            "properties": {
+                "address_highlight": {
+                    "type": "string"
                 },
-                "userPostsCount": {
-                    "type": "integer"
+                "address_text": {
+                    "type": "string"
                 },
-                "userVolunteerCount": {
-                    "type": "integer"
+                "category_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "post_type": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "number",
+                    "example": 0.42
+                },
+                "snippet": {
+                    "type": "string",
+                    "example": "…гудамжны \u003cmark\u003eгэрэлтүүлэг\u003c/mark\u003e долоо хоног асахгүй…"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "title_highlight": {
+                    "type": "string",
+                    "example": "\u003cmark\u003eГэрэлтүүлэг\u003c/mark\u003e ажиллахгүй байна"
                 }
             }
         },
-        "server.ApproveRejectVolunteerRequest": {
+        "server.PostShiftDTO": {
             "type": "object",
             "properties": {
-                "post_id": {
+                "capacity": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "string",
-                    "format": "uuid",
-                    "example": "a1b2c3d4-e5f6-7777-8888-99990000aaaa"
+                    "format": "uuid"
                 },
-                "volunteer_user_id": {
+                "meeting_lat": {
+                    "type": "number"
+                },
+                "meeting_lng": {
+                    "type": "number"
+                },
+                "meeting_point": {
+                    "type": "string"
+                },
+                "post_id": {
                     "type": "string",
-                    "format": "uuid",
-                    "example": "a1b2c3d4-e5f6-7777-8888-99990000bbbb"
+                    "format": "uuid"
+                },
+                "signed_up": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "starts_at": {
+                    "type": "string"
                 }
             }
         },
-        "server.CategoryDTO": {
+        "server.PostSkillsDTO": {
             "type": "object",
             "properties": {
-                "can_volunteer": {
-                    "type": "boolean"
+                "skills": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "first aid",
+                        "driving"
+                    ]
+                }
+            }
+        },
+        "server.PostStatusHistoryDTO": {
+            "type": "object",
+            "properties": {
+                "changed_by": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "changed_by_name": {
+                    "type": "string",
+                    "example": "Бат Дорж"
+                },
+                "comment": {
+                    "type": "string",
+                    "example": "Хогийг ачиж дууслаа"
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "description": {
+                "from_status": {
+                    "type": "string",
+                    "example": "Шийдвэрлэгдэж байгаа"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "post_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "to_status": {
+                    "type": "string",
+                    "example": "Шийдвэрлэгдсэн"
+                }
+            }
+        },
+        "server.PostVolunteerDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
                     "type": "string"
                 },
-                "endpoint": {
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "notes": {
                     "type": "string"
                 },
+                "post_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "pending"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "server.PostVolunteerEntryDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "john.doe@example.com"
+                },
+                "first_name": {
+                    "type": "string",
+                    "example": "John"
+                },
                 "id": {
                     "type": "string",
                     "format": "uuid"
                 },
-                "name": {
+                "last_name": {
+                    "type": "string",
+                    "example": "Doe"
+                },
+                "notes": {
                     "type": "string"
                 },
+                "phone": {
+                    "type": "string",
+                    "example": "99119911"
+                },
+                "post_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "profile_url": {
+                    "type": "string",
+                    "example": "http://example.com/profile.jpg"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "pending"
+                },
                 "updated_at": {
                     "type": "string"
+                },
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "server.PostVolunteersDTO": {
+            "type": "object",
+            "properties": {
+                "counts": {
+                    "$ref": "#/definitions/server.VolunteerCountsDTO"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.PostVolunteerEntryDTO"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "server.PostsInBoundsResponseDTO": {
+            "type": "object",
+            "properties": {
+                "clustered": {
+                    "type": "boolean"
+                },
+                "clusters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.PostClusterDTO"
+                    }
+                },
+                "posts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.GeoPostDTO"
+                    }
+                }
+            }
+        },
+        "server.PrivacySettingsDTO": {
+            "type": "object",
+            "properties": {
+                "email_visibility": {
+                    "enum": [
+                        "private",
+                        "organizers",
+                        "public"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/db.ProfileFieldVisibility"
+                        }
+                    ],
+                    "example": "private"
+                },
+                "phone_visibility": {
+                    "enum": [
+                        "private",
+                        "organizers",
+                        "public"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/db.ProfileFieldVisibility"
+                        }
+                    ],
+                    "example": "organizers"
+                }
+            }
+        },
+        "server.PublicUserProfileDTO": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "john.doe@example.com"
+                },
+                "first_name": {
+                    "type": "string",
+                    "example": "John"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "is_volunteering": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "joined_at": {
+                    "type": "string",
+                    "example": "2023-01-01T12:00:00Z"
+                },
+                "last_name": {
+                    "type": "string",
+                    "example": "Doe"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "99119911"
+                },
+                "profile_url": {
+                    "type": "string",
+                    "example": "http://example.com/profile.jpg"
+                },
+                "stats": {
+                    "$ref": "#/definitions/server.UserStatsDTO"
+                }
+            }
+        },
+        "server.RefreshTokenRequest": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "type": "string",
+                    "example": "5q8b0Qm1..."
                 }
             }
         },
-        "server.ErrorResponse": {
+        "server.ReorderPostImagesRequest": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "string"
+                "image_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "server.LoginRequestPayload": {
+        "server.ResetPasswordRequest": {
             "type": "object",
             "properties": {
-                "email": {
+                "new_password": {
                     "type": "string",
-                    "example": "john.doe@example.com"
+                    "example": "newStrongPassword456"
                 },
-                "password": {
+                "token": {
                     "type": "string",
-                    "example": "strongpassword123"
+                    "example": "Xq3v9kT0..."
                 }
             }
         },
-        "server.LoginResponsePayloadDTO": {
+        "server.SecuritySettingsDTO": {
             "type": "object",
             "properties": {
-                "token": {
-                    "type": "string",
-                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                "require_admin_mfa": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "server.ShiftCheckRequest": {
+            "type": "object",
+            "properties": {
+                "lat": {
+                    "type": "number",
+                    "example": 47.9188
                 },
-                "user": {
-                    "$ref": "#/definitions/server.UserResponseDTO"
+                "lng": {
+                    "type": "number",
+                    "example": 106.9171
                 }
             }
         },
-        "server.PostResponseDTO": {
+        "server.ShiftSignupDTO": {
             "type": "object",
             "properties": {
-                "address_text": {
+                "check_in_lat": {
+                    "type": "number"
+                },
+                "check_in_lng": {
+                    "type": "number"
+                },
+                "check_out_lat": {
+                    "type": "number"
+                },
+                "check_out_lng": {
+                    "type": "number"
+                },
+                "checked_in_at": {
                     "type": "string"
                 },
-                "category_id": {
+                "checked_out_at": {
                     "type": "string"
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "current_volunteers": {
-                    "type": "integer"
-                },
-                "description": {
+                "first_name": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string",
                     "format": "uuid"
                 },
-                "images": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "last_name": {
+                    "type": "string"
                 },
-                "location_lat": {
-                    "type": "number"
+                "shift_id": {
+                    "type": "string",
+                    "format": "uuid"
                 },
-                "location_lng": {
-                    "type": "number"
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "server.SuggestedVolunteerDTO": {
+            "type": "object",
+            "properties": {
+                "available_shifts": {
+                    "type": "integer",
+                    "example": 2
                 },
-                "max_volunteers": {
-                    "type": "integer"
+                "distance_m": {
+                    "type": "number",
+                    "example": 1250.5
                 },
-                "post_type": {
-                    "type": "string"
+                "explanation": {
+                    "type": "string",
+                    "example": "Has first aid training and lives nearby."
                 },
-                "preview_url": {
-                    "type": "string"
+                "first_name": {
+                    "type": "string",
+                    "example": "John"
                 },
-                "priority": {
-                    "type": "string"
+                "last_name": {
+                    "type": "string",
+                    "example": "Doe"
                 },
-                "status": {
-                    "type": "string"
+                "matched_skills": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "first aid"
+                    ]
                 },
-                "title": {
+                "profile_url": {
                     "type": "string"
                 },
-                "updated_at": {
-                    "type": "string"
+                "score": {
+                    "type": "number",
+                    "example": 0.72
                 },
                 "user_id": {
                     "type": "string",
                     "format": "uuid"
+                }
+            }
+        },
+        "server.SuggestedVolunteersDTO": {
+            "type": "object",
+            "properties": {
+                "ai_ranked": {
+                    "type": "boolean"
                 },
-                "volunteers": {
+                "items": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/server.PostVolunteerDTO"
+                        "$ref": "#/definitions/server.SuggestedVolunteerDTO"
                     }
                 }
             }
         },
-        "server.PostVolunteerDTO": {
+        "server.TokenResponseDTO": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string",
-                    "format": "uuid"
-                },
-                "notes": {
-                    "type": "string"
-                },
-                "post_id": {
+                "expires_at": {
                     "type": "string",
-                    "format": "uuid"
+                    "example": "2023-01-01T12:15:00Z"
                 },
-                "status": {
+                "refresh_token": {
                     "type": "string",
-                    "example": "pending"
-                },
-                "updated_at": {
-                    "type": "string"
+                    "example": "5q8b0Qm1..."
                 },
-                "user_id": {
+                "token": {
                     "type": "string",
-                    "format": "uuid"
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
                 }
             }
         },
@@ -1506,10 +7175,6 @@ const docTemplate = `{
                     "type": "string",
                     "format": "uuid"
                 },
-                "current_volunteers": {
-                    "type": "integer",
-                    "example": 5
-                },
                 "description": {
                     "type": "string",
                     "example": "Updated details: The local park needs volunteers urgently."
@@ -1576,6 +7241,10 @@ const docTemplate = `{
         "server.UpdateUserEmailRequest": {
             "type": "object",
             "properties": {
+                "current_password": {
+                    "type": "string",
+                    "example": "strongpassword123"
+                },
                 "email": {
                     "type": "string",
                     "example": "new.email@example.com"
@@ -1585,12 +7254,25 @@ const docTemplate = `{
         "server.UpdateUserPasswordRequest": {
             "type": "object",
             "properties": {
+                "current_password": {
+                    "type": "string",
+                    "example": "strongpassword123"
+                },
                 "new_password": {
                     "type": "string",
                     "example": "newStrongPassword456"
                 }
             }
         },
+        "server.UpdateUserRoleRequest": {
+            "type": "object",
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "example": "MODERATOR"
+                }
+            }
+        },
         "server.UserResponseDTO": {
             "type": "object",
             "properties": {
@@ -1598,10 +7280,19 @@ const docTemplate = `{
                     "type": "string",
                     "example": "2023-01-01T12:00:00Z"
                 },
+                "deleted_at": {
+                    "description": "Set while the account awaits purging",
+                    "type": "string",
+                    "example": "2023-02-01T12:00:00Z"
+                },
                 "email": {
                     "type": "string",
                     "example": "john.doe@example.com"
                 },
+                "email_verified_at": {
+                    "type": "string",
+                    "example": "2023-01-01T12:30:00Z"
+                },
                 "first_name": {
                     "type": "string",
                     "example": "John"
@@ -1636,6 +7327,164 @@ const docTemplate = `{
                     "example": "2023-01-01T13:00:00Z"
                 }
             }
+        },
+        "server.UserStatsDTO": {
+            "type": "object",
+            "properties": {
+                "completed_volunteer_count": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "posts_count": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "resolved_posts_count": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "volunteer_count": {
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "server.VerifyEmailRequest": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string",
+                    "example": "Xq3v9kT0..."
+                }
+            }
+        },
+        "server.VerifyMFARequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                },
+                "mfa_token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                }
+            }
+        },
+        "server.VolunteerCountsDTO": {
+            "type": "object",
+            "properties": {
+                "approved": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "completed": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "no_show": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "pending": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "rejected": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "withdrawn": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "server.VolunteerProfileDTO": {
+            "type": "object",
+            "properties": {
+                "availability": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/server.AvailabilitySlotDTO"
+                    }
+                },
+                "location_lat": {
+                    "type": "number",
+                    "example": 47.9187
+                },
+                "location_lng": {
+                    "type": "number",
+                    "example": 106.917
+                },
+                "skills": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "first aid",
+                        "driving"
+                    ]
+                }
+            }
+        },
+        "server.VolunteeringDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "organizer_first_name": {
+                    "type": "string",
+                    "example": "Jane"
+                },
+                "organizer_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "organizer_last_name": {
+                    "type": "string",
+                    "example": "Doe"
+                },
+                "post_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "post_preview_url": {
+                    "type": "string"
+                },
+                "post_status": {
+                    "type": "string",
+                    "example": "Хүлээгдэж байгаа"
+                },
+                "post_title": {
+                    "type": "string",
+                    "example": "Clean up the park"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "pending"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
         }
     },
     "securityDefinitions": {
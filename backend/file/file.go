@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,7 +16,7 @@ import (
 
 type FileStore interface {
 	UploadProfileImage(ctx context.Context, fileContent []byte, originalFilename string, contentType string, userID uuid.UUID) (objectURL string, objectName string, err error)
-	UploadPostImage(ctx context.Context, fileContent []byte, originalFilename string, contentType string, userID uuid.UUID) (objectURL string, objectName string, err error)
+	UploadPostImage(ctx context.Context, fileContent []byte, originalFilename string, contentType string, postID uuid.UUID) (objectURL string, objectName string, err error)
 	DeleteObject(ctx context.Context, objectName string) error // Optional: for rollbacks or deletions
 }
 
@@ -65,6 +67,15 @@ func NewMinioStore(cfg MinioConfig) (*MinioStore, error) {
 // UploadProfileImage uploads a user's profile image to MinIO.
 // It takes fileContent as []byte to simplify handling after initial read/validation in the handler.
 func (s *MinioStore) UploadProfileImage(ctx context.Context, fileContent []byte, originalFilename string, contentType string, userID uuid.UUID) (objectURL string, objectName string, err error) {
+	return s.uploadImage(ctx, "user_profiles/"+userID.String(), fileContent, originalFilename, contentType)
+}
+
+// UploadPostImage uploads an image of a post to MinIO, next to the post's other images.
+func (s *MinioStore) UploadPostImage(ctx context.Context, fileContent []byte, originalFilename string, contentType string, postID uuid.UUID) (objectURL string, objectName string, err error) {
+	return s.uploadImage(ctx, "post_images/"+postID.String(), fileContent, originalFilename, contentType)
+}
+
+func (s *MinioStore) uploadImage(ctx context.Context, dir string, fileContent []byte, originalFilename string, contentType string) (objectURL string, objectName string, err error) {
 	fileExt := filepath.Ext(originalFilename)
 	if fileExt == "" { // Fallback if no extension
 		switch contentType {
@@ -76,8 +87,8 @@ func (s *MinioStore) UploadProfileImage(ctx context.Context, fileContent []byte,
 		}
 	}
 
-	objectName = fmt.Sprintf("user_profiles/%s/%d_%s%s",
-		userID.String(),
+	objectName = fmt.Sprintf("%s/%d_%s%s",
+		dir,
 		time.Now().UnixNano(),
 		uuid.New().String(), // Another UUID for uniqueness within the timestamp
 		fileExt,
@@ -106,3 +117,21 @@ func (s *MinioStore) DeleteObject(ctx context.Context, objectName string) error
 	return nil
 }
 
+// ObjectNameFromURL returns the object name behind a URL produced by this
+// store, or false when the URL points elsewhere.
+func (s *MinioStore) ObjectNameFromURL(objectURL string) (string, bool) {
+	prefix := fmt.Sprintf("%s/%s/", s.publicURLBase, s.bucketName)
+	if !strings.HasPrefix(objectURL, prefix) || len(objectURL) == len(prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(objectURL, prefix), true
+}
+
+// GetObject opens an object for reading. The caller must close it.
+func (s *MinioStore) GetObject(ctx context.Context, objectName string) (io.ReadCloser, error) {
+	obj, err := s.client.GetObject(ctx, s.bucketName, objectName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object '%s' from bucket '%s': %w", objectName, s.bucketName, err)
+	}
+	return obj, nil
+}
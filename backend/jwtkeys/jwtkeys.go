@@ -0,0 +1,235 @@
+// Package jwtkeys holds the asymmetric keys access tokens are signed with.
+//
+// Keys are PEM files in JWT_KEYS_DIR named <kid>.pem. Private keys (RSA or
+// Ed25519, PKCS#1 or PKCS#8) can sign and verify; public keys (PKIX) only
+// verify. To rotate, add the new key, point JWT_ACTIVE_KEY_ID at it and keep
+// the old file (its public half is enough) until tokens it signed expire.
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dukunuu/hackathon_backend/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minRSABits is the smallest RSA modulus accepted for signing or verification.
+const minRSABits = 2048
+
+// Key is one verification key, with its private half when it may sign.
+type Key struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Public  crypto.PublicKey
+	private crypto.Signer
+}
+
+// KeySet is the active signing key plus every key still accepted for
+// verification.
+type KeySet struct {
+	active *Key
+	keys   map[string]*Key
+}
+
+// JWK is a public key in RFC 7517 form.
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+// JWKS is the document served at /.well-known/jwks.json.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// Init loads the key set from cfg. Without JWT_KEYS_DIR outside production a
+// throwaway Ed25519 key is generated, so tokens do not survive a restart.
+func Init(cfg *config.Config) (*KeySet, error) {
+	if cfg.JWT_KEYS_DIR == "" {
+		if cfg.IsProduction() {
+			return nil, errors.New("JWT_KEYS_DIR must be set in production")
+		}
+		slog.Warn("JWT_KEYS_DIR is not set; signing tokens with an ephemeral key that is lost on restart")
+		return Ephemeral()
+	}
+	return LoadDir(cfg.JWT_KEYS_DIR, cfg.JWT_ACTIVE_KEY_ID)
+}
+
+// Ephemeral returns a key set with a single freshly generated Ed25519 key.
+func Ephemeral() (*KeySet, error) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+	key, err := newKey("ephemeral", priv)
+	if err != nil {
+		return nil, err
+	}
+	return &KeySet{active: key, keys: map[string]*Key{key.ID: key}}, nil
+}
+
+// LoadDir reads every *.pem file in dir. activeID selects the signing key and
+// may be empty when dir holds exactly one private key.
+func LoadDir(dir, activeID string) (*KeySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no *.pem keys found in %s", dir)
+	}
+
+	set := &KeySet{keys: make(map[string]*Key)}
+	var signers []*Key
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		kid := strings.TrimSuffix(filepath.Base(path), ".pem")
+		key, err := parseKey(kid, data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		set.keys[kid] = key
+		if key.private != nil {
+			signers = append(signers, key)
+		}
+	}
+
+	switch {
+	case activeID != "":
+		key, ok := set.keys[activeID]
+		if !ok || key.private == nil {
+			return nil, fmt.Errorf("JWT_ACTIVE_KEY_ID '%s' does not name a private key in %s", activeID, dir)
+		}
+		set.active = key
+	case len(signers) == 1:
+		set.active = signers[0]
+	default:
+		return nil, fmt.Errorf("%s holds %d private keys; set JWT_ACTIVE_KEY_ID to choose the signing key", dir, len(signers))
+	}
+
+	slog.Info("Loaded JWT signing keys", "activeKid", set.active.ID, "keys", set.IDs())
+	return set, nil
+}
+
+func parseKey(kid string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var parsed any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type '%s'", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newKey(kid, parsed)
+}
+
+func newKey(kid string, parsed any) (*Key, error) {
+	key := &Key{ID: kid}
+	switch k := parsed.(type) {
+	case ed25519.PrivateKey:
+		key.Method, key.Public, key.private = jwt.SigningMethodEdDSA, k.Public(), k
+	case ed25519.PublicKey:
+		key.Method, key.Public = jwt.SigningMethodEdDSA, k
+	case *rsa.PrivateKey:
+		key.Method, key.Public, key.private = jwt.SigningMethodRS256, &k.PublicKey, k
+	case *rsa.PublicKey:
+		key.Method, key.Public = jwt.SigningMethodRS256, k
+	default:
+		return nil, fmt.Errorf("unsupported key type %T (expected RSA or Ed25519)", parsed)
+	}
+	if pub, ok := key.Public.(*rsa.PublicKey); ok && pub.N.BitLen() < minRSABits {
+		return nil, fmt.Errorf("RSA key is %d bits; at least %d are required", pub.N.BitLen(), minRSABits)
+	}
+	return key, nil
+}
+
+// IDs lists the key IDs in the set in alphabetical order.
+func (s *KeySet) IDs() []string {
+	ids := make([]string, 0, len(s.keys))
+	for id := range s.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Sign signs claims with the active key and sets the kid header.
+func (s *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(s.active.Method, claims)
+	token.Header["kid"] = s.active.ID
+	return token.SignedString(s.active.private)
+}
+
+// Keyfunc resolves the verification key for a token by its kid header and
+// rejects tokens whose algorithm does not match that key.
+func (s *KeySet) Keyfunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key '%s'", kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return key.Public, nil
+}
+
+// Methods lists the algorithms tokens may be signed with.
+func (s *KeySet) Methods() []string {
+	return []string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}
+}
+
+// JWKS returns the public keys of the set.
+func (s *KeySet) JWKS() JWKS {
+	doc := JWKS{Keys: make([]JWK, 0, len(s.keys))}
+	for _, id := range s.IDs() {
+		key := s.keys[id]
+		jwk := JWK{Use: "sig", Alg: key.Method.Alg(), Kid: key.ID}
+		switch pub := key.Public.(type) {
+		case ed25519.PublicKey:
+			jwk.Kty, jwk.Crv = "OKP", "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		}
+		doc.Keys = append(doc.Keys, jwk)
+	}
+	return doc
+}
@@ -0,0 +1,60 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+
+	"github.com/dukunuu/hackathon_backend/config"
+)
+
+// Message is a plain-text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers transactional emails such as verification links and
+// password resets.
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Init builds the Sender selected by MAIL_DRIVER.
+func Init(cfg *config.Config) (Sender, error) {
+	switch cfg.MAIL_DRIVER {
+	case "smtp":
+		return NewSMTPSender(SMTPConfig{
+			Host:     cfg.SMTP_HOST,
+			Port:     cfg.SMTP_PORT,
+			Username: cfg.SMTP_USERNAME,
+			Password: cfg.SMTP_PASSWORD,
+			From:     cfg.MAIL_FROM,
+		})
+	case "file":
+		return NewFileSender(cfg.MAIL_FILE_DIR, cfg.MAIL_FROM)
+	case "memory":
+		return NewMemorySender(), nil
+	default:
+		return nil, fmt.Errorf("unknown MAIL_DRIVER '%s' (expected smtp, file or memory)", cfg.MAIL_DRIVER)
+	}
+}
+
+// render formats msg as an RFC 5322 message with a UTF-8 plain-text body.
+func render(from string, msg Message) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return buf.Bytes()
+}
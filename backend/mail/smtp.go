@@ -0,0 +1,52 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// SMTPSender delivers mail through an SMTP relay. smtp.SendMail upgrades the
+// connection with STARTTLS whenever the server offers it.
+type SMTPSender struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+func NewSMTPSender(cfg SMTPConfig) (*SMTPSender, error) {
+	if cfg.Host == "" || cfg.From == "" {
+		return nil, fmt.Errorf("SMTP_HOST and MAIL_FROM must be set for the smtp mail driver")
+	}
+
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	return &SMTPSender{
+		addr: net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		auth: auth,
+		from: cfg.From,
+	}, nil
+}
+
+func (s *SMTPSender) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := smtp.SendMail(s.addr, s.auth, s.from, []string{msg.To}, render(s.from, msg)); err != nil {
+		return fmt.Errorf("failed to send mail to %s via %s: %w", msg.To, s.addr, err)
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+// Package mvt encodes point features as Mapbox Vector Tiles (spec version
+// 2.1) in the Web Mercator XYZ tiling scheme used by web maps and QGIS.
+// Only points are supported, which is all the posts map needs.
+package mvt
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+)
+
+const (
+	// Extent is the number of coordinate units across a tile.
+	Extent = 4096
+	// Buffer is how far outside the tile, in coordinate units, points are
+	// still encoded so markers on the edge are not cut off.
+	Buffer = 64
+
+	MaxZoom = 22
+
+	version = 2
+)
+
+// Protobuf field numbers from vector_tile.proto.
+const (
+	tileLayers = 3
+
+	layerName     = 1
+	layerFeatures = 2
+	layerKeys     = 3
+	layerValues   = 4
+	layerExtent   = 5
+	layerVersion  = 15
+
+	featureTags     = 2
+	featureType     = 3
+	featureGeometry = 4
+
+	valueString = 1
+	valueDouble = 3
+	valueSint   = 6
+	valueBool   = 7
+
+	geomTypePoint = 1
+	cmdMoveTo     = 1
+)
+
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+)
+
+// Tile addresses a tile in the XYZ scheme: X grows eastwards and Y
+// southwards from the top-left corner.
+type Tile struct {
+	Z, X, Y uint32
+}
+
+// Validate reports whether the tile exists at its zoom level.
+func (t Tile) Validate() error {
+	if t.Z > MaxZoom {
+		return fmt.Errorf("zoom must be between 0 and %d", MaxZoom)
+	}
+	if n := uint32(1) << t.Z; t.X >= n || t.Y >= n {
+		return fmt.Errorf("tile %d/%d/%d is outside the zoom level's grid", t.Z, t.X, t.Y)
+	}
+	return nil
+}
+
+// Bounds returns the longitude and latitude range covered by the tile and
+// its buffer.
+func (t Tile) Bounds() (minLng, minLat, maxLng, maxLat float64) {
+	n := math.Exp2(float64(t.Z))
+	buffer := float64(Buffer) / Extent
+	minLng = math.Max(-180, tileLng(float64(t.X)-buffer, n))
+	maxLng = math.Min(180, tileLng(float64(t.X)+1+buffer, n))
+	maxLat = tileLat(math.Max(0, float64(t.Y)-buffer), n)
+	minLat = tileLat(math.Min(n, float64(t.Y)+1+buffer), n)
+	return minLng, minLat, maxLng, maxLat
+}
+
+func tileLng(x, n float64) float64 {
+	return x/n*360 - 180
+}
+
+func tileLat(y, n float64) float64 {
+	return math.Atan(math.Sinh(math.Pi*(1-2*y/n))) * 180 / math.Pi
+}
+
+// project converts a longitude and latitude to coordinates within the tile.
+func (t Tile) project(lng, lat float64) (int64, int64) {
+	n := math.Exp2(float64(t.Z))
+	latRad := lat * math.Pi / 180
+	x := (lng + 180) / 360 * n
+	y := (1 - math.Log(math.Tan(latRad)+1/math.Cos(latRad))/math.Pi) / 2 * n
+	return int64(math.Round((x - float64(t.X)) * Extent)), int64(math.Round((y - float64(t.Y)) * Extent))
+}
+
+// Feature is a point with properties. Property values must be strings,
+// float64, int64 or bool.
+type Feature struct {
+	Lng, Lat   float64
+	Properties map[string]any
+}
+
+// Layer is a named set of features.
+type Layer struct {
+	Name     string
+	Features []Feature
+}
+
+// Encode renders the layers as a tile. Features outside the tile and its
+// buffer are left out.
+func Encode(t Tile, layers ...Layer) ([]byte, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	var tile []byte
+	for _, l := range layers {
+		layer, err := encodeLayer(t, l)
+		if err != nil {
+			return nil, fmt.Errorf("layer %s: %w", l.Name, err)
+		}
+		tile = appendBytesField(tile, tileLayers, layer)
+	}
+	return tile, nil
+}
+
+func encodeLayer(t Tile, l Layer) ([]byte, error) {
+	var keys []string
+	keyIndex := map[string]uint64{}
+	var values [][]byte
+	valueIndex := map[any]uint64{}
+
+	var features [][]byte
+	for _, f := range l.Features {
+		x, y := t.project(f.Lng, f.Lat)
+		if x < -Buffer || x > Extent+Buffer || y < -Buffer || y > Extent+Buffer {
+			continue
+		}
+
+		names := make([]string, 0, len(f.Properties))
+		for k := range f.Properties {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+
+		var tags []byte
+		for _, k := range names {
+			v := f.Properties[k]
+			ki, ok := keyIndex[k]
+			if !ok {
+				ki = uint64(len(keys))
+				keyIndex[k] = ki
+				keys = append(keys, k)
+			}
+			vi, ok := valueIndex[v]
+			if !ok {
+				encoded, err := encodeValue(v)
+				if err != nil {
+					return nil, fmt.Errorf("property %s: %w", k, err)
+				}
+				vi = uint64(len(values))
+				valueIndex[v] = vi
+				values = append(values, encoded)
+			}
+			tags = binary.AppendUvarint(tags, ki)
+			tags = binary.AppendUvarint(tags, vi)
+		}
+
+		var geometry []byte
+		geometry = binary.AppendUvarint(geometry, cmdMoveTo|1<<3)
+		geometry = binary.AppendUvarint(geometry, zigzag(x))
+		geometry = binary.AppendUvarint(geometry, zigzag(y))
+
+		var feature []byte
+		feature = appendBytesField(feature, featureTags, tags)
+		feature = appendVarintField(feature, featureType, geomTypePoint)
+		feature = appendBytesField(feature, featureGeometry, geometry)
+		features = append(features, feature)
+	}
+
+	var layer []byte
+	layer = appendVarintField(layer, layerVersion, version)
+	layer = appendBytesField(layer, layerName, []byte(l.Name))
+	for _, f := range features {
+		layer = appendBytesField(layer, layerFeatures, f)
+	}
+	for _, k := range keys {
+		layer = appendBytesField(layer, layerKeys, []byte(k))
+	}
+	for _, v := range values {
+		layer = appendBytesField(layer, layerValues, v)
+	}
+	layer = appendVarintField(layer, layerExtent, Extent)
+	return layer, nil
+}
+
+func encodeValue(v any) ([]byte, error) {
+	switch v := v.(type) {
+	case string:
+		return appendBytesField(nil, valueString, []byte(v)), nil
+	case float64:
+		b := binary.AppendUvarint(nil, valueDouble<<3|wireFixed64)
+		return binary.LittleEndian.AppendUint64(b, math.Float64bits(v)), nil
+	case int64:
+		return appendVarintField(nil, valueSint, zigzag(v)), nil
+	case bool:
+		var u uint64
+		if v {
+			u = 1
+		}
+		return appendVarintField(nil, valueBool, u), nil
+	}
+	return nil, fmt.Errorf("unsupported value type %T", v)
+}
+
+func zigzag(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
+
+func appendVarintField(b []byte, field int, v uint64) []byte {
+	b = binary.AppendUvarint(b, uint64(field)<<3|wireVarint)
+	return binary.AppendUvarint(b, v)
+}
+
+func appendBytesField(b []byte, field int, v []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(field)<<3|wireBytes)
+	b = binary.AppendUvarint(b, uint64(len(v)))
+	return append(b, v...)
+}
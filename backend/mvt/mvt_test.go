@@ -0,0 +1,177 @@
+package mvt
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestZigzag(t *testing.T) {
+	tests := []struct {
+		in   int64
+		want uint64
+	}{
+		{in: 0, want: 0},
+		{in: -1, want: 1},
+		{in: 1, want: 2},
+		{in: -2, want: 3},
+		{in: 2, want: 4},
+		{in: 2048, want: 4096},
+		{in: -64, want: 127},
+		{in: math.MaxInt32, want: math.MaxUint32 - 1},
+		{in: math.MinInt32, want: math.MaxUint32},
+	}
+	for _, tt := range tests {
+		if got := zigzag(tt.in); got != tt.want {
+			t.Errorf("zigzag(%d) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestEncodeKnownTile(t *testing.T) {
+	// One point at 0,0 in tile 0/0/0 lands in the middle of the tile, 2048,2048.
+	got, err := Encode(Tile{}, Layer{
+		Name:     "posts",
+		Features: []Feature{{Lng: 0, Lat: 0, Properties: map[string]any{"kind": "pin"}}},
+	})
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	feature := []byte{
+		0x12, 0x02, 0x00, 0x00, // tags: key 0, value 0
+		0x18, 0x01, // type: POINT
+		0x22, 0x05, 0x09, 0x80, 0x20, 0x80, 0x20, // geometry: MoveTo(1), zigzag 2048 twice
+	}
+	layer := []byte{0x78, 0x02} // version 2
+	layer = append(layer, 0x0a, 0x05, 'p', 'o', 's', 't', 's')
+	layer = append(layer, 0x12, byte(len(feature)))
+	layer = append(layer, feature...)
+	layer = append(layer, 0x1a, 0x04, 'k', 'i', 'n', 'd')
+	layer = append(layer, 0x22, 0x05, 0x0a, 0x03, 'p', 'i', 'n')
+	layer = append(layer, 0x28, 0x80, 0x20) // extent 4096
+	want := append([]byte{0x1a, byte(len(layer))}, layer...)
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("Encode =\n% x\nwant\n% x", got, want)
+	}
+}
+
+func TestEncodeSharesKeysAndValues(t *testing.T) {
+	props := map[string]any{"status": "open", "priority": int64(2)}
+	got, err := encodeLayer(Tile{}, Layer{Name: "p", Features: []Feature{
+		{Lng: 10, Lat: 10, Properties: props},
+		{Lng: 20, Lat: 20, Properties: map[string]any{"status": "open", "priority": int64(3)}},
+	}})
+	if err != nil {
+		t.Fatalf("encodeLayer: %v", err)
+	}
+	// Keys are sorted per feature: priority (0), status (1). The second
+	// feature reuses both keys and the "open" value and adds one value.
+	for _, tags := range [][]byte{
+		{0x12, 0x04, 0x00, 0x00, 0x01, 0x01},
+		{0x12, 0x04, 0x00, 0x02, 0x01, 0x01},
+	} {
+		if !bytes.Contains(got, tags) {
+			t.Errorf("layer does not contain tags % x", tags)
+		}
+	}
+	if n := bytes.Count(got, []byte("status")); n != 1 {
+		t.Errorf("key 'status' encoded %d times, want 1", n)
+	}
+	if n := bytes.Count(got, []byte("open")); n != 1 {
+		t.Errorf("value 'open' encoded %d times, want 1", n)
+	}
+}
+
+func TestEncodeValue(t *testing.T) {
+	tests := []struct {
+		name string
+		in   any
+		want []byte
+	}{
+		{name: "string", in: "ab", want: []byte{0x0a, 0x02, 'a', 'b'}},
+		{name: "double", in: 1.5, want: []byte{0x19, 0, 0, 0, 0, 0, 0, 0xf8, 0x3f}},
+		{name: "negative sint", in: int64(-1), want: []byte{0x30, 0x01}},
+		{name: "positive sint", in: int64(300), want: []byte{0x30, 0xd8, 0x04}},
+		{name: "true", in: true, want: []byte{0x38, 0x01}},
+		{name: "false", in: false, want: []byte{0x38, 0x00}},
+	}
+	for _, tt := range tests {
+		got, err := encodeValue(tt.in)
+		if err != nil {
+			t.Fatalf("encodeValue(%s): %v", tt.name, err)
+		}
+		if !bytes.Equal(got, tt.want) {
+			t.Errorf("encodeValue(%s) = % x, want % x", tt.name, got, tt.want)
+		}
+	}
+	if _, err := encodeValue(42); err == nil {
+		t.Error("encodeValue(int) returned no error")
+	}
+}
+
+func TestProject(t *testing.T) {
+	tests := []struct {
+		name     string
+		tile     Tile
+		lng, lat float64
+		x, y     int64
+	}{
+		{name: "centre of the world", tile: Tile{}, lng: 0, lat: 0, x: 2048, y: 2048},
+		{name: "top-left corner", tile: Tile{Z: 1}, lng: -180, lat: tileLat(0, 2), x: 0, y: 0},
+		{name: "shared corner from the east tile", tile: Tile{Z: 1, X: 1}, lng: 0, lat: 0, x: 0, y: Extent},
+		{name: "middle of an eastern tile", tile: Tile{Z: 1, X: 1}, lng: 90, lat: tileLat(0.5, 2), x: 2048, y: 2048},
+	}
+	for _, tt := range tests {
+		x, y := tt.tile.project(tt.lng, tt.lat)
+		if x != tt.x || y != tt.y {
+			t.Errorf("%s: project = %d,%d, want %d,%d", tt.name, x, y, tt.x, tt.y)
+		}
+	}
+}
+
+func TestEncodeDropsFeaturesOutsideBuffer(t *testing.T) {
+	tile := Tile{Z: 1, X: 1, Y: 0}
+	got, err := encodeLayer(tile, Layer{Name: "p", Features: []Feature{
+		{Lng: 90, Lat: 45},  // inside
+		{Lng: -90, Lat: 45}, // western tile
+		{Lng: 90, Lat: -45}, // southern tile
+	}})
+	if err != nil {
+		t.Fatalf("encodeLayer: %v", err)
+	}
+	if n := bytes.Count(got, []byte{0x18, 0x01}); n != 1 {
+		t.Errorf("layer holds %d features, want 1", n)
+	}
+}
+
+func TestTileValidate(t *testing.T) {
+	tests := []struct {
+		tile Tile
+		ok   bool
+	}{
+		{tile: Tile{}, ok: true},
+		{tile: Tile{Z: 1, X: 1, Y: 1}, ok: true},
+		{tile: Tile{Z: 1, X: 2, Y: 0}, ok: false},
+		{tile: Tile{Z: 1, X: 0, Y: 2}, ok: false},
+		{tile: Tile{Z: MaxZoom, X: 1<<MaxZoom - 1, Y: 1<<MaxZoom - 1}, ok: true},
+		{tile: Tile{Z: MaxZoom + 1}, ok: false},
+	}
+	for _, tt := range tests {
+		if err := tt.tile.Validate(); (err == nil) != tt.ok {
+			t.Errorf("Tile%+v.Validate() = %v, want ok=%v", tt.tile, err, tt.ok)
+		}
+	}
+}
+
+func TestTileBounds(t *testing.T) {
+	minLng, minLat, maxLng, maxLat := Tile{}.Bounds()
+	const mercatorLimit = 85.0511287798
+	if minLng != -180 || maxLng != 180 {
+		t.Errorf("world longitude = %v..%v, want -180..180", minLng, maxLng)
+	}
+	if math.Abs(minLat+mercatorLimit) > 1e-9 || math.Abs(maxLat-mercatorLimit) > 1e-9 {
+		t.Errorf("world latitude = %v..%v, want ±%v", minLat, maxLat, mercatorLimit)
+	}
+}
@@ -0,0 +1,162 @@
+// Package oidc signs users in with external OpenID Connect providers using
+// the authorization code flow with PKCE.
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"github.com/dukunuu/hackathon_backend/config"
+	"golang.org/x/oauth2"
+)
+
+// ErrNonceMismatch means the ID token was not issued for this login attempt.
+var ErrNonceMismatch = errors.New("oidc: ID token nonce does not match")
+
+// Identity is what a provider tells us about the user who signed in.
+type Identity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	GivenName     string
+	FamilyName    string
+	Name          string
+}
+
+// Provider is a configured identity provider. Discovery happens on first
+// use so an unreachable provider does not stop the API from starting.
+type Provider struct {
+	Name string
+
+	cfg         config.OIDCProviderConfig
+	redirectURL string
+	httpClient  *http.Client
+
+	mu       sync.Mutex
+	oauth    *oauth2.Config
+	verifier *gooidc.IDTokenVerifier
+}
+
+// Registry holds the providers configured through OIDC_PROVIDERS.
+type Registry struct {
+	providers map[string]*Provider
+}
+
+// Init builds a Registry from cfg. Callback URLs are
+// <API_BASE_URL>/api/v1/auth/oidc/<name>/callback.
+func Init(cfg *config.Config) *Registry {
+	reg := &Registry{providers: make(map[string]*Provider)}
+	for _, pc := range cfg.OIDC_PROVIDERS {
+		reg.providers[pc.Name] = &Provider{
+			Name:        pc.Name,
+			cfg:         pc,
+			redirectURL: fmt.Sprintf("%s/api/v1/auth/oidc/%s/callback", cfg.API_BASE_URL, pc.Name),
+			httpClient:  &http.Client{Timeout: 10 * time.Second},
+		}
+	}
+	return reg
+}
+
+// Get returns the provider registered under name.
+func (r *Registry) Get(name string) (*Provider, bool) {
+	p, ok := r.providers[name]
+	return p, ok
+}
+
+// Names lists the configured providers in alphabetical order.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (p *Provider) discover() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.oauth != nil {
+		return nil
+	}
+
+	// The context outlives this call: go-oidc keeps it to refresh signing keys.
+	ctx := gooidc.ClientContext(context.Background(), p.httpClient)
+	provider, err := gooidc.NewProvider(ctx, p.cfg.Issuer)
+	if err != nil {
+		return fmt.Errorf("oidc: discovery for provider '%s' failed: %w", p.Name, err)
+	}
+	p.oauth = &oauth2.Config{
+		ClientID:     p.cfg.ClientID,
+		ClientSecret: p.cfg.ClientSecret,
+		Endpoint:     provider.Endpoint(),
+		RedirectURL:  p.redirectURL,
+		Scopes:       p.cfg.Scopes,
+	}
+	p.verifier = provider.Verifier(&gooidc.Config{ClientID: p.cfg.ClientID})
+	return nil
+}
+
+// AuthCodeURL returns the provider URL the user is sent to. verifier is the
+// PKCE code verifier; only its S256 challenge leaves the server.
+func (p *Provider) AuthCodeURL(state, nonce, verifier string) (string, error) {
+	if err := p.discover(); err != nil {
+		return "", err
+	}
+	return p.oauth.AuthCodeURL(state,
+		oauth2.S256ChallengeOption(verifier),
+		oauth2.SetAuthURLParam("nonce", nonce),
+	), nil
+}
+
+// Exchange redeems the authorization code and returns the verified identity
+// from the ID token.
+func (p *Provider) Exchange(ctx context.Context, code, verifier, nonce string) (Identity, error) {
+	if err := p.discover(); err != nil {
+		return Identity{}, err
+	}
+
+	ctx = gooidc.ClientContext(ctx, p.httpClient)
+	token, err := p.oauth.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return Identity{}, fmt.Errorf("oidc: code exchange failed: %w", err)
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return Identity{}, errors.New("oidc: token response did not include an id_token")
+	}
+
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return Identity{}, fmt.Errorf("oidc: invalid ID token: %w", err)
+	}
+	if idToken.Nonce != nonce {
+		return Identity{}, ErrNonceMismatch
+	}
+
+	var claims struct {
+		Email         string `json:"email"`
+		EmailVerified any    `json:"email_verified"`
+		GivenName     string `json:"given_name"`
+		FamilyName    string `json:"family_name"`
+		Name          string `json:"name"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return Identity{}, fmt.Errorf("oidc: failed to decode ID token claims: %w", err)
+	}
+
+	return Identity{
+		Subject:       idToken.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified == true || claims.EmailVerified == "true",
+		GivenName:     claims.GivenName,
+		FamilyName:    claims.FamilyName,
+		Name:          claims.Name,
+	}, nil
+}
@@ -0,0 +1,124 @@
+// Package password holds the password policy shared by every endpoint that
+// sets a password.
+package password
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"net/mail"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// MinLength follows NIST SP 800-63B for user-chosen secrets.
+	MinLength = 8
+	// MaxLength is the number of bytes bcrypt actually hashes.
+	MaxLength = 72
+
+	// Personal values shorter than this are too common to ban in passwords.
+	minPersonalTokenLength = 3
+)
+
+// Rule names reported in a Violation.
+const (
+	RuleMinLength      = "min_length"
+	RuleMaxLength      = "max_length"
+	RuleCommonPassword = "common_password"
+	RulePersonalInfo   = "personal_info"
+)
+
+// Violation is a single policy rule the password did not satisfy.
+type Violation struct {
+	Rule    string `json:"rule" example:"min_length"`
+	Message string `json:"message" example:"Password must be at least 8 characters long"`
+}
+
+// UserInfo is the account data a password must not contain.
+type UserInfo struct {
+	Email     string
+	FirstName string
+	LastName  string
+}
+
+//go:embed common_passwords.txt
+var commonPasswordsFile string
+
+var commonPasswords = loadWordlist(commonPasswordsFile)
+
+func loadWordlist(contents string) map[string]struct{} {
+	words := make(map[string]struct{})
+	scanner := bufio.NewScanner(strings.NewReader(contents))
+	for scanner.Scan() {
+		word := strings.TrimSpace(scanner.Text())
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		words[strings.ToLower(word)] = struct{}{}
+	}
+	return words
+}
+
+// Validate checks pw against every rule of the policy and returns all the
+// violations, or nil if the password is acceptable.
+func Validate(pw string, user UserInfo) []Violation {
+	var violations []Violation
+
+	if utf8.RuneCountInString(pw) < MinLength {
+		violations = append(violations, Violation{
+			Rule:    RuleMinLength,
+			Message: fmt.Sprintf("Password must be at least %d characters long", MinLength),
+		})
+	}
+	if len(pw) > MaxLength {
+		violations = append(violations, Violation{
+			Rule:    RuleMaxLength,
+			Message: fmt.Sprintf("Password must be at most %d bytes long", MaxLength),
+		})
+	}
+
+	lower := strings.ToLower(pw)
+	if _, ok := commonPasswords[lower]; ok {
+		violations = append(violations, Violation{
+			Rule:    RuleCommonPassword,
+			Message: "Password is too common or has appeared in a data breach",
+		})
+	}
+
+	for _, token := range personalTokens(user) {
+		if strings.Contains(lower, token) {
+			violations = append(violations, Violation{
+				Rule:    RulePersonalInfo,
+				Message: "Password must not contain your email address or name",
+			})
+			break
+		}
+	}
+
+	return violations
+}
+
+// personalTokens returns the lower-cased email, its local part and the
+// user's names, skipping values too short to be meaningful.
+func personalTokens(user UserInfo) []string {
+	candidates := []string{user.FirstName, user.LastName}
+	if addr, err := mail.ParseAddress(user.Email); err == nil {
+		email := strings.ToLower(addr.Address)
+		candidates = append(candidates, email)
+		if at := strings.LastIndex(email, "@"); at > 0 {
+			candidates = append(candidates, email[:at])
+		}
+	} else if user.Email != "" {
+		candidates = append(candidates, user.Email)
+	}
+
+	var tokens []string
+	for _, c := range candidates {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if utf8.RuneCountInString(c) >= minPersonalTokenLength {
+			tokens = append(tokens, c)
+		}
+	}
+	return tokens
+}
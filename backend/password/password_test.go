@@ -0,0 +1,62 @@
+package password
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func rules(violations []Violation) []string {
+	var names []string
+	for _, v := range violations {
+		names = append(names, v.Rule)
+	}
+	return names
+}
+
+func TestValidate(t *testing.T) {
+	user := UserInfo{Email: "Bataa.Dorj@example.mn", FirstName: "Bold", LastName: "Bo"}
+
+	tests := []struct {
+		name string
+		pw   string
+		user UserInfo
+		want []string
+	}{
+		{name: "empty", pw: "", want: []string{RuleMinLength}},
+		{name: "one below minimum", pw: "xk7#pq2", want: []string{RuleMinLength}},
+		{name: "exactly minimum", pw: "xk7#pq2m", want: nil},
+		{name: "minimum counts runes not bytes", pw: "хөх7#тэн", want: nil},
+		{name: "seven multi-byte runes", pw: "хөх7#тэ", want: []string{RuleMinLength}},
+		{name: "exactly maximum bytes", pw: strings.Repeat("k", MaxLength), want: nil},
+		{name: "one byte over maximum", pw: strings.Repeat("k", MaxLength+1), want: []string{RuleMaxLength}},
+		{name: "maximum counts bytes not runes", pw: strings.Repeat("ө", 37), want: []string{RuleMaxLength}},
+		{name: "common password", pw: "password", want: []string{RuleCommonPassword}},
+		{name: "common password ignores case", pw: "PassW0rd", want: []string{RuleCommonPassword}},
+		{name: "short and common", pw: "123456", want: []string{RuleMinLength, RuleCommonPassword}},
+		{name: "contains email", pw: "x" + "bataa.dorj@example.mn", user: user, want: []string{RulePersonalInfo}},
+		{name: "contains email local part", pw: "my-BATAA.DORJ-pw", user: user, want: []string{RulePersonalInfo}},
+		{name: "contains first name", pw: "xx-bold-2024", user: user, want: []string{RulePersonalInfo}},
+		{name: "short last name is ignored", pw: "bo-xk7#pq2m", user: user, want: nil},
+		{name: "personal info reported once", pw: "bold-bataa.dorj", user: user, want: []string{RulePersonalInfo}},
+		{name: "unparsable email still checked", pw: "not-an-email-xk7", user: UserInfo{Email: "not-an-email"}, want: []string{RulePersonalInfo}},
+		{name: "no user info", pw: "correct horse battery staple", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rules(Validate(tt.pw, tt.user))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate(%q) rules = %v, want %v", tt.pw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadWordlist(t *testing.T) {
+	words := loadWordlist("# comment\n\n  Hunter2  \nqwerty\n")
+	want := map[string]struct{}{"hunter2": {}, "qwerty": {}}
+	if !reflect.DeepEqual(words, want) {
+		t.Errorf("loadWordlist = %v, want %v", words, want)
+	}
+}
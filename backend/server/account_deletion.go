@@ -0,0 +1,199 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dukunuu/hackathon_backend/db"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	accountPurgeInterval  = time.Hour
+	accountPurgeBatchSize = 50
+)
+
+// AccountDeletionResponseDTO is returned when an account is scheduled for deletion.
+// swagger:model AccountDeletionResponse
+type AccountDeletionResponseDTO struct {
+	Message    string    `json:"message" example:"Account scheduled for deletion"`
+	PurgeAfter time.Time `json:"purge_after" example:"2023-02-01T12:00:00Z"`
+}
+
+// purgeAfter is when a deleted account stops being restorable.
+func (s *Server) purgeAfter(user db.User) time.Time {
+	return user.DeletedAt.Time.Add(s.accountDeletionGrace)
+}
+
+// restoreAccount cancels the pending deletion of the user. It returns
+// pgx.ErrNoRows when the grace period is over.
+func (s *Server) restoreAccount(ctx context.Context, userID pgtype.UUID) (db.User, error) {
+	return s.db.RestoreUser(ctx, db.RestoreUserParams{
+		ID:           userID,
+		DeletedAfter: pgtype.Timestamptz{Time: time.Now().Add(-s.accountDeletionGrace), Valid: true},
+	})
+}
+
+// restoreAccountOnLogin cancels the pending deletion of a user who logged in
+// with restore_account set. Logins call it once every factor has been
+// checked, so a password alone cannot restore an account with two-factor
+// authentication. On failure it writes the error response and reports false.
+func (s *Server) restoreAccountOnLogin(w http.ResponseWriter, r *http.Request, userID pgtype.UUID) (db.User, bool) {
+	user, err := s.restoreAccount(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			respondWithError(w, http.StatusGone, "Account has been deleted")
+			return db.User{}, false
+		}
+		slog.Error("Failed to restore account", "error", err, "userID", userID)
+		respondWithError(w, http.StatusInternalServerError, "Login failed")
+		return db.User{}, false
+	}
+	slog.Info("Account restored on login", "userID", userID)
+	return user, true
+}
+
+// runAccountPurge periodically removes accounts whose grace period is over.
+func (s *Server) runAccountPurge(ctx context.Context) {
+	ticker := time.NewTicker(accountPurgeInterval)
+	defer ticker.Stop()
+	for {
+		s.purgeDeletedAccounts(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Server) purgeDeletedAccounts(ctx context.Context) {
+	users, err := s.db.ListUsersDueForPurge(ctx, db.ListUsersDueForPurgeParams{
+		DeletedBefore: pgtype.Timestamptz{Time: time.Now().Add(-s.accountDeletionGrace), Valid: true},
+		BatchSize:     accountPurgeBatchSize,
+	})
+	if err != nil {
+		slog.Error("Failed to list accounts due for purge", "error", err)
+		return
+	}
+	for _, user := range users {
+		purged, err := s.purgeAccount(ctx, user)
+		if err != nil {
+			slog.Error("Failed to purge deleted account", "error", err, "userID", user.ID)
+			continue
+		}
+		if purged {
+			slog.Info("Purged deleted account", "userID", user.ID)
+		}
+	}
+}
+
+// purgeAccount removes the user and then their stored files. Posts stay as
+// anonymous complaint history; everything else tied to the account goes with
+// it. The account row is locked and re-checked in the transaction, so a
+// restore that races the purge either wins and keeps everything, or finds the
+// account gone. Objects are only removed once the rows are; one that fails
+// to delete is logged and left behind rather than taken from a live account.
+// It reports false when the account was restored in the meantime.
+func (s *Server) purgeAccount(ctx context.Context, user db.User) (bool, error) {
+	deletedBefore := pgtype.Timestamptz{Time: time.Now().Add(-s.accountDeletionGrace), Valid: true}
+
+	var urls []string
+	err := s.db.ExecTx(ctx, func(q *db.Queries) error {
+		user, err := q.LockUserDueForPurge(ctx, db.LockUserDueForPurgeParams{ID: user.ID, DeletedBefore: deletedBefore})
+		if err != nil {
+			return err
+		}
+		if user.ProfileUrl.Valid {
+			urls = append(urls, user.ProfileUrl.String)
+		}
+		images, err := q.ListUserPostImages(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		for _, img := range images {
+			urls = append(urls, img.ImageUrl)
+		}
+		previews, err := q.ListUserPostPreviewUrls(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		for _, preview := range previews {
+			urls = append(urls, preview.String)
+		}
+
+		if err := q.DeleteUserPostImages(ctx, user.ID); err != nil {
+			return err
+		}
+		if err := q.ClearUserPostPreviews(ctx, user.ID); err != nil {
+			return err
+		}
+		deleted, err := q.PurgeDeletedUser(ctx, db.PurgeDeletedUserParams{ID: user.ID, DeletedBefore: deletedBefore})
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			return pgx.ErrNoRows
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	for _, u := range urls {
+		objectName, ok := s.filestore.ObjectNameFromURL(u)
+		if !ok {
+			continue // Not one of our uploads, e.g. an external avatar URL
+		}
+		if err := s.filestore.DeleteObject(ctx, objectName); err != nil {
+			slog.Error("Failed to delete object of purged account", "error", err, "userID", user.ID, "object", objectName)
+		}
+	}
+	return true, nil
+}
+
+// handleRestoreUser cancels the pending deletion of an account.
+// @Summary Restore a deleted account
+// @Description Cancels the deletion of an account that is still within its grace period. Requires the 'users:manage' permission.
+// @Description Users can restore their own account by logging in with 'restore_account' set.
+// @Tags Users
+// @Produce json
+// @Param userID path string true "User ID" format(uuid)
+// @Success 200 {object} UserResponseDTO "Account restored"
+// @Failure 400 {object} ErrorResponse "Invalid user ID format"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Missing permission"
+// @Failure 404 {object} ErrorResponse "No restorable deletion for this user"
+// @Failure 500 {object} ErrorResponse "Failed to restore account"
+// @Security BearerAuth
+// @Router /users/{userID}/restore [post]
+func (s *Server) handleRestoreUser(w http.ResponseWriter, r *http.Request) {
+	targetUserID, err := parseUUIDFromParam(r, "userID")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user ID format")
+		return
+	}
+
+	user, err := s.restoreAccount(r.Context(), targetUserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "No restorable deletion for this user")
+			return
+		}
+		slog.Error("Failed to restore account", "error", err, "userID", targetUserID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to restore account")
+		return
+	}
+
+	slog.Info("Account restored by administrator", "userID", user.ID)
+	respondWithJSON(w, http.StatusOK, ToUserResponseDTO(user))
+}
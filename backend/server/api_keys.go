@@ -0,0 +1,329 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dukunuu/hackathon_backend/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	apiKeyPrefix    = "hk_"
+	apiKeyPrefixLen = len(apiKeyPrefix) + 8 // Characters kept in clear for display
+
+	apiKeyDefaultExpiryDays = 90
+	apiKeyMaxExpiryDays     = 365
+	apiKeyMaxNameLength     = 100
+)
+
+// Scopes that are not role permissions. Permission scopes (e.g.
+// 'posts:moderate') additionally let the key use that permission of its
+// owner's role.
+const (
+	ScopeRead  = "read"  // GET and HEAD requests
+	ScopeWrite = "write" // Every other method
+)
+
+// CreateAPIKeyRequest defines the expected JSON body for creating an API key.
+// swagger:model CreateAPIKeyRequest
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name" example:"Weekly report script"`
+	Scopes        []string `json:"scopes" example:"read"`
+	ExpiresInDays int      `json:"expires_in_days,omitempty" example:"90"`
+}
+
+// APIKeyDTO describes an API key without its secret.
+// swagger:model APIKeyDTO
+type APIKeyDTO struct {
+	ID         uuid.UUID  `json:"id" format:"uuid"`
+	Name       string     `json:"name" example:"Weekly report script"`
+	Prefix     string     `json:"prefix" example:"hk_Zx81kQ2a"`
+	Scopes     []string   `json:"scopes" example:"read"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// APIKeyCreatedResponseDTO is returned once when a key is created; the key
+// cannot be retrieved again.
+// swagger:model APIKeyCreatedResponse
+type APIKeyCreatedResponseDTO struct {
+	APIKeyDTO
+	Key string `json:"key" example:"hk_Zx81kQ2a..."`
+}
+
+func toAPIKeyDTO(k db.ApiKey) APIKeyDTO {
+	dto := APIKeyDTO{
+		ID:        k.ID.Bytes,
+		Name:      k.Name,
+		Prefix:    k.Prefix,
+		Scopes:    k.Scopes,
+		ExpiresAt: k.ExpiresAt.Time,
+		CreatedAt: k.CreatedAt.Time,
+	}
+	if dto.Scopes == nil {
+		dto.Scopes = []string{}
+	}
+	if k.LastUsedAt.Valid {
+		dto.LastUsedAt = &k.LastUsedAt.Time
+	}
+	if k.RevokedAt.Valid {
+		dto.RevokedAt = &k.RevokedAt.Time
+	}
+	return dto
+}
+
+// isValidAPIKeyScope reports whether scope is read, write or a permission
+// some role can hold.
+func isValidAPIKeyScope(scope string) bool {
+	if scope == ScopeRead || scope == ScopeWrite {
+		return true
+	}
+	for _, perms := range rolePermissions {
+		if slices.Contains(perms, Permission(scope)) {
+			return true
+		}
+	}
+	return false
+}
+
+func getAPIKeyScopesFromContext(ctx context.Context) ([]string, bool) {
+	scopes, ok := ctx.Value(APIKeyScopesKey).([]string)
+	return scopes, ok
+}
+
+// apiKeyAllows reports whether the caller may use perm as far as API key
+// scopes are concerned. Requests authenticated with a session always may.
+func apiKeyAllows(ctx context.Context, perm Permission) bool {
+	scopes, ok := getAPIKeyScopesFromContext(ctx)
+	return !ok || slices.Contains(scopes, string(perm))
+}
+
+// authenticateAPIKey resolves an X-API-Key header into the same request
+// context AuthMiddleware builds for access tokens, minus the session.
+func (s *Server) authenticateAPIKey(w http.ResponseWriter, r *http.Request, key string, next http.Handler) {
+	apiKey, err := s.db.GetActiveAPIKey(r.Context(), hashToken(key))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			respondWithError(w, http.StatusUnauthorized, "API key is invalid, revoked or has expired")
+			return
+		}
+		slog.Error("Failed to validate API key", "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to authorize request")
+		return
+	}
+
+	required := ScopeWrite
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		required = ScopeRead
+	}
+	if !slices.Contains(apiKey.Scopes, required) {
+		respondWithError(w, http.StatusForbidden, "API key is missing scope: "+required)
+		return
+	}
+
+	mfaRequired := false
+	if apiKey.Role == db.UserRoleADMIN && !apiKey.MfaVerified {
+		mfaRequired, err = s.adminMFARequired(r.Context())
+		if err != nil {
+			slog.Error("Failed to read admin MFA setting", "error", err)
+			respondWithError(w, http.StatusInternalServerError, "Failed to authorize request")
+			return
+		}
+	}
+
+	if err := s.db.TouchAPIKey(r.Context(), apiKey.ID); err != nil {
+		slog.Error("Failed to record API key use", "error", err, "apiKeyID", apiKey.ID)
+	}
+
+	ctx := context.WithValue(r.Context(), UserIDKey, apiKey.UserID)
+	ctx = context.WithValue(ctx, UserRoleKey, apiKey.Role)
+	ctx = context.WithValue(ctx, MFAVerifiedKey, apiKey.MfaVerified)
+	ctx = context.WithValue(ctx, MFARequiredKey, mfaRequired)
+	ctx = context.WithValue(ctx, APIKeyScopesKey, apiKey.Scopes)
+
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// RequireSession rejects requests authenticated with an API key. Credential,
+// session and key management stay reserved for interactive logins. It must
+// be mounted after AuthMiddleware.
+func (s *Server) RequireSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := getAPIKeyScopesFromContext(r.Context()); ok {
+			respondWithError(w, http.StatusForbidden, "This action requires a logged-in session and cannot be performed with an API key")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleCreateAPIKey creates a personal API key for the current user.
+// @Summary Create an API key
+// @Description Creates a key for calling the API without logging in; send it in the 'X-API-Key' header instead of a Bearer token.
+// @Description The key acts as its owner with these scopes: 'read' (GET requests), 'write' (all other requests) and any
+// @Description permission of the owner's role, e.g. 'posts:moderate'. The key is only returned once.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param apiKey body CreateAPIKeyRequest true "Name, scopes and lifetime (1-365 days, default 90)"
+// @Success 201 {object} APIKeyCreatedResponseDTO "API key created"
+// @Failure 400 {object} ErrorResponse "Invalid name, scopes or lifetime"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Permission scope not held by the caller, or request made with an API key"
+// @Failure 500 {object} ErrorResponse "Failed to create API key"
+// @Security BearerAuth
+// @Router /users/me/api-keys [post]
+func (s *Server) handleCreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromContext(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > apiKeyMaxNameLength {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("name is required and must be at most %d characters", apiKeyMaxNameLength))
+		return
+	}
+	if len(req.Scopes) == 0 {
+		respondWithError(w, http.StatusBadRequest, "At least one scope is required")
+		return
+	}
+	slices.Sort(req.Scopes)
+	req.Scopes = slices.Compact(req.Scopes)
+	for _, scope := range req.Scopes {
+		if !isValidAPIKeyScope(scope) {
+			respondWithError(w, http.StatusBadRequest, "Unknown scope: "+scope)
+			return
+		}
+		if scope != ScopeRead && scope != ScopeWrite && !hasPermission(r.Context(), Permission(scope)) {
+			respondWithError(w, http.StatusForbidden, "You cannot grant a permission you do not have: "+scope)
+			return
+		}
+	}
+	if req.ExpiresInDays == 0 {
+		req.ExpiresInDays = apiKeyDefaultExpiryDays
+	}
+	if req.ExpiresInDays < 1 || req.ExpiresInDays > apiKeyMaxExpiryDays {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("expires_in_days must be between 1 and %d", apiKeyMaxExpiryDays))
+		return
+	}
+
+	token, _, err := newOpaqueToken()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to create API key")
+		return
+	}
+	key := apiKeyPrefix + token
+
+	apiKey, err := s.db.CreateAPIKey(r.Context(), db.CreateAPIKeyParams{
+		UserID:      userID,
+		Name:        req.Name,
+		Prefix:      key[:apiKeyPrefixLen],
+		KeyHash:     hashToken(key),
+		Scopes:      req.Scopes,
+		MfaVerified: getMFAVerifiedFromContext(r.Context()),
+		ExpiresAt:   pgtype.Timestamptz{Time: time.Now().AddDate(0, 0, req.ExpiresInDays), Valid: true},
+	})
+	if err != nil {
+		slog.Error("Failed to create API key", "error", err, "userID", userID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to create API key")
+		return
+	}
+
+	slog.Info("API key created", "userID", userID, "apiKeyID", apiKey.ID, "scopes", apiKey.Scopes)
+	respondWithJSON(w, http.StatusCreated, APIKeyCreatedResponseDTO{APIKeyDTO: toAPIKeyDTO(apiKey), Key: key})
+}
+
+// handleListAPIKeys lists the current user's API keys.
+// @Summary List API keys
+// @Description Returns the current user's API keys, including revoked and expired ones, newest first. Secrets are never returned.
+// @Tags Users
+// @Produce json
+// @Success 200 {array} APIKeyDTO "API keys"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Request made with an API key"
+// @Failure 500 {object} ErrorResponse "Failed to retrieve API keys"
+// @Security BearerAuth
+// @Router /users/me/api-keys [get]
+func (s *Server) handleListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromContext(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	keys, err := s.db.ListUserAPIKeys(r.Context(), userID)
+	if err != nil {
+		slog.Error("Failed to list API keys", "error", err, "userID", userID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve API keys")
+		return
+	}
+
+	dtos := make([]APIKeyDTO, len(keys))
+	for i, k := range keys {
+		dtos[i] = toAPIKeyDTO(k)
+	}
+	respondWithJSON(w, http.StatusOK, dtos)
+}
+
+// handleRevokeAPIKey revokes one of the current user's API keys.
+// @Summary Revoke an API key
+// @Description Revokes the key immediately. Revoked keys stay listed for reference.
+// @Tags Users
+// @Produce json
+// @Param keyID path string true "API key ID" format(uuid)
+// @Success 200 {object} map[string]string "message: API key revoked"
+// @Failure 400 {object} ErrorResponse "Invalid API key ID format"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Request made with an API key"
+// @Failure 404 {object} ErrorResponse "API key not found or already revoked"
+// @Failure 500 {object} ErrorResponse "Failed to revoke API key"
+// @Security BearerAuth
+// @Router /users/me/api-keys/{keyID} [delete]
+func (s *Server) handleRevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromContext(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	keyID, err := parseUUIDFromParam(r, "keyID")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid API key ID format")
+		return
+	}
+
+	revoked, err := s.db.RevokeAPIKey(r.Context(), db.RevokeAPIKeyParams{ID: keyID, UserID: userID})
+	if err != nil {
+		slog.Error("Failed to revoke API key", "error", err, "userID", userID, "apiKeyID", keyID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to revoke API key")
+		return
+	}
+	if revoked == 0 {
+		respondWithError(w, http.StatusNotFound, "API key not found or already revoked")
+		return
+	}
+
+	slog.Info("API key revoked", "userID", userID, "apiKeyID", keyID)
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "API key revoked"})
+}
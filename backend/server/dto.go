@@ -10,16 +10,18 @@ import (
 // UserResponseDTO is the data transfer object for user responses.
 // swagger:model UserResponse
 type UserResponseDTO struct {
-	ID             uuid.UUID `json:"id" example:"a1b2c3d4-e5f6-7777-8888-99990000abcd"`
-	FirstName      string    `json:"first_name" example:"John"`
-	LastName       string    `json:"last_name" example:"Doe"`
-	Phone          *string   `json:"phone,omitempty" example:"99119911"`
-	IsVolunteering bool      `json:"is_volunteering" example:"false"`
-	Email          string    `json:"email" example:"john.doe@example.com"`
-	Role           string    `json:"role" example:"USER"` // Changed from interface{} to string
-	ProfileUrl     *string   `json:"profile_url,omitempty" example:"http://example.com/profile.jpg"`
-	CreatedAt      time.Time `json:"created_at" example:"2023-01-01T12:00:00Z"`
-	UpdatedAt      time.Time `json:"updated_at" example:"2023-01-01T13:00:00Z"`
+	ID              uuid.UUID  `json:"id" example:"a1b2c3d4-e5f6-7777-8888-99990000abcd"`
+	FirstName       string     `json:"first_name" example:"John"`
+	LastName        string     `json:"last_name" example:"Doe"`
+	Phone           *string    `json:"phone,omitempty" example:"99119911"`
+	IsVolunteering  bool       `json:"is_volunteering" example:"false"`
+	Email           string     `json:"email" example:"john.doe@example.com"`
+	Role            string     `json:"role" example:"USER"` // Changed from interface{} to string
+	ProfileUrl      *string    `json:"profile_url,omitempty" example:"http://example.com/profile.jpg"`
+	CreatedAt       time.Time  `json:"created_at" example:"2023-01-01T12:00:00Z"`
+	UpdatedAt       time.Time  `json:"updated_at" example:"2023-01-01T13:00:00Z"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty" example:"2023-01-01T12:30:00Z"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty" example:"2023-02-01T12:00:00Z"` // Set while the account awaits purging
 }
 
 // ToUserResponseDTO converts a db.User to a UserResponseDTO.
@@ -49,20 +51,32 @@ func ToUserResponseDTO(user db.User) UserResponseDTO {
 		updatedAt = user.UpdatedAt.Time
 	}
 
+	var emailVerifiedAt *time.Time
+	if user.EmailVerifiedAt.Valid {
+		emailVerifiedAt = &user.EmailVerifiedAt.Time
+	}
+
+	var deletedAt *time.Time
+	if user.DeletedAt.Valid {
+		deletedAt = &user.DeletedAt.Time
+	}
+
 	// Handle Role conversion from interface{} to string
 	var roleStr = string(user.Role)
 
 	return UserResponseDTO{
-		ID:             userID,
-		FirstName:      user.FirstName,
-		LastName:       user.LastName,
-		Phone:          phone,
-		IsVolunteering: user.IsVolunteering,
-		Email:          user.Email,
-		Role:           roleStr, // Assign the converted string
-		ProfileUrl:     profileURL,
-		CreatedAt:      createdAt,
-		UpdatedAt:      updatedAt,
+		ID:              userID,
+		FirstName:       user.FirstName,
+		LastName:        user.LastName,
+		Phone:           phone,
+		IsVolunteering:  user.IsVolunteering,
+		Email:           user.Email,
+		Role:            roleStr, // Assign the converted string
+		ProfileUrl:      profileURL,
+		CreatedAt:       createdAt,
+		UpdatedAt:       updatedAt,
+		EmailVerifiedAt: emailVerifiedAt,
+		DeletedAt:       deletedAt,
 	}
 }
 
@@ -78,15 +92,26 @@ func ToUserResponseDTOs(users []db.User) []UserResponseDTO {
 // LoginResponsePayloadDTO defines the JSON response for successful login using DTO.
 // swagger:model LoginResponse
 type LoginResponsePayloadDTO struct {
-	Token string          `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
-	User  UserResponseDTO `json:"user"`
+	Token        string          `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	RefreshToken string          `json:"refresh_token" example:"5q8b0Qm1..."`
+	ExpiresAt    time.Time       `json:"expires_at" example:"2023-01-01T12:15:00Z"`
+	User         UserResponseDTO `json:"user"`
 }
 
 // ToLoginResponsePayloadDTO creates a LoginResponsePayloadDTO.
-func ToLoginResponsePayloadDTO(token string, user db.User) LoginResponsePayloadDTO {
+func ToLoginResponsePayloadDTO(tokens sessionTokens, user db.User) LoginResponsePayloadDTO {
 	return LoginResponsePayloadDTO{
-		Token: token,
-		User:  ToUserResponseDTO(user),
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresAt:    tokens.ExpiresAt,
+		User:         ToUserResponseDTO(user),
 	}
 }
 
+// TokenResponseDTO defines the JSON response for a refreshed token pair.
+// swagger:model TokenResponse
+type TokenResponseDTO struct {
+	Token        string    `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	RefreshToken string    `json:"refresh_token" example:"5q8b0Qm1..."`
+	ExpiresAt    time.Time `json:"expires_at" example:"2023-01-01T12:15:00Z"`
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const errResourceModified = "The resource was modified since you fetched it; reload it and try again"
+
+// versionETag returns the strong ETag of a resource from its updated_at.
+// Posts and users have a trigger that bumps updated_at on every change, and
+// their update queries only apply while it still has the value the client
+// read, so it is the version.
+func versionETag(updatedAt pgtype.Timestamptz) string {
+	return `"` + strconv.FormatInt(updatedAt.Time.UnixMicro(), 36) + `"`
+}
+
+// etagListContains reports whether an If-Match or If-None-Match header lists
+// etag. Weak comparison ignores the W/ prefix; strong comparison never
+// matches a weak tag.
+func etagListContains(header, etag string, weak bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if strings.HasPrefix(candidate, "W/") {
+			if !weak {
+				continue
+			}
+			candidate = strings.TrimPrefix(candidate, "W/")
+		}
+		if candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// respondWithTaggedJSON is respondWithJSON with an ETag header. A GET whose
+// If-None-Match lists the current ETag gets an empty 304 instead.
+func respondWithTaggedJSON(w http.ResponseWriter, r *http.Request, code int, etag string, payload any) {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		if inm := r.Header.Get("If-None-Match"); inm != "" && etagListContains(inm, etag, true) {
+			w.Header().Set("ETag", etag)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		http.Error(w, "Failed to marshal JSON response", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(body)
+}
+
+// checkIfMatch makes an update conditional on the client having seen the
+// current representation. It answers 428 when If-Match is missing and 412
+// when it does not list the current ETag, and reports whether the update may
+// go ahead.
+func checkIfMatch(w http.ResponseWriter, r *http.Request, etag string) bool {
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		respondWithError(w, http.StatusPreconditionRequired, "If-Match header is required; send the ETag of the resource you are updating")
+		return false
+	}
+	if !etagListContains(ifMatch, etag, false) {
+		w.Header().Set("ETag", etag)
+		respondWithError(w, http.StatusPreconditionFailed, errResourceModified)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestVersionETag(t *testing.T) {
+	at := time.Date(2025, 5, 1, 12, 0, 0, 123456000, time.UTC)
+	a := versionETag(pgtype.Timestamptz{Time: at, Valid: true})
+	b := versionETag(pgtype.Timestamptz{Time: at.In(time.FixedZone("UTC+8", 8*3600)), Valid: true})
+	if a != b {
+		t.Errorf("same instant gave %s and %s", a, b)
+	}
+	if a[0] != '"' || a[len(a)-1] != '"' {
+		t.Errorf("ETag %s is not quoted", a)
+	}
+	if c := versionETag(pgtype.Timestamptz{Time: at.Add(time.Microsecond), Valid: true}); c == a {
+		t.Errorf("ETag did not change when updated_at moved by 1µs: %s", c)
+	}
+}
+
+func TestETagListContains(t *testing.T) {
+	const etag = `"abc"`
+
+	tests := []struct {
+		name   string
+		header string
+		weak   bool
+		want   bool
+	}{
+		{name: "exact", header: `"abc"`, want: true},
+		{name: "other tag", header: `"abd"`, want: false},
+		{name: "unquoted", header: `abc`, want: false},
+		{name: "wildcard", header: `*`, want: true},
+		{name: "in a list", header: `"x", "abc"`, want: true},
+		{name: "list without spaces", header: `"x","abc"`, want: true},
+		{name: "list without the tag", header: `"x", "y"`, want: false},
+		{name: "weak tag, strong comparison", header: `W/"abc"`, want: false},
+		{name: "weak tag, weak comparison", header: `W/"abc"`, weak: true, want: true},
+		{name: "weak tag in a list, weak comparison", header: `"x", W/"abc"`, weak: true, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := etagListContains(tt.header, etag, tt.weak); got != tt.want {
+				t.Errorf("etagListContains(%q, weak=%v) = %v, want %v", tt.header, tt.weak, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckIfMatch(t *testing.T) {
+	const etag = `"abc"`
+
+	tests := []struct {
+		name     string
+		ifMatch  string
+		ok       bool
+		wantCode int
+	}{
+		{name: "missing", wantCode: http.StatusPreconditionRequired},
+		{name: "current", ifMatch: `"abc"`, ok: true},
+		{name: "wildcard", ifMatch: `*`, ok: true},
+		{name: "listed", ifMatch: `"old", "abc"`, ok: true},
+		{name: "stale", ifMatch: `"old"`, wantCode: http.StatusPreconditionFailed},
+		{name: "weak", ifMatch: `W/"abc"`, wantCode: http.StatusPreconditionFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPut, "/", nil)
+			if tt.ifMatch != "" {
+				r.Header.Set("If-Match", tt.ifMatch)
+			}
+			w := httptest.NewRecorder()
+
+			if got := checkIfMatch(w, r, etag); got != tt.ok {
+				t.Fatalf("checkIfMatch = %v, want %v", got, tt.ok)
+			}
+			if tt.ok {
+				if w.Body.Len() != 0 {
+					t.Errorf("allowed update wrote a response: %s", w.Body)
+				}
+				return
+			}
+			if w.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantCode)
+			}
+			if tt.wantCode == http.StatusPreconditionFailed && w.Header().Get("ETag") != etag {
+				t.Errorf("ETag = %q, want %q", w.Header().Get("ETag"), etag)
+			}
+		})
+	}
+}
+
+func TestRespondWithTaggedJSON(t *testing.T) {
+	const etag = `"abc"`
+
+	tests := []struct {
+		name        string
+		method      string
+		ifNoneMatch string
+		wantCode    int
+	}{
+		{name: "no condition", method: http.MethodGet, wantCode: http.StatusOK},
+		{name: "current", method: http.MethodGet, ifNoneMatch: `"abc"`, wantCode: http.StatusNotModified},
+		{name: "weak", method: http.MethodGet, ifNoneMatch: `W/"abc"`, wantCode: http.StatusNotModified},
+		{name: "wildcard", method: http.MethodGet, ifNoneMatch: `*`, wantCode: http.StatusNotModified},
+		{name: "head", method: http.MethodHead, ifNoneMatch: `"abc"`, wantCode: http.StatusNotModified},
+		{name: "stale", method: http.MethodGet, ifNoneMatch: `"old"`, wantCode: http.StatusOK},
+		{name: "not a read", method: http.MethodPut, ifNoneMatch: `"abc"`, wantCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/", nil)
+			if tt.ifNoneMatch != "" {
+				r.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			w := httptest.NewRecorder()
+
+			respondWithTaggedJSON(w, r, http.StatusOK, etag, map[string]string{"a": "b"})
+
+			if w.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantCode)
+			}
+			if got := w.Header().Get("ETag"); got != etag {
+				t.Errorf("ETag = %q, want %q", got, etag)
+			}
+			wantBody := `{"a":"b"}`
+			if tt.wantCode == http.StatusNotModified {
+				wantBody = ""
+			}
+			if got := w.Body.String(); got != wantBody {
+				t.Errorf("body = %q, want %q", got, wantBody)
+			}
+		})
+	}
+}
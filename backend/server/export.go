@@ -0,0 +1,229 @@
+package server
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/dukunuu/hackathon_backend/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// AccountExportProfileDTO is profile.json of an account data export.
+type AccountExportProfileDTO struct {
+	User       UserResponseDTO    `json:"user"`
+	Privacy    PrivacySettingsDTO `json:"privacy"`
+	ExportedAt time.Time          `json:"exported_at"`
+}
+
+// AccountExportShiftDTO is an entry of shifts.json of an account data export.
+type AccountExportShiftDTO struct {
+	ShiftSignupDTO
+	PostID   uuid.UUID `json:"post_id"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+}
+
+// accountExportFile is a stored object copied into the export archive.
+type accountExportFile struct {
+	objectName  string
+	archivePath string
+}
+
+// handleExportAccountData streams a ZIP of everything stored about the current user.
+// @Summary Export account data
+// @Description Downloads a ZIP archive with the current user's profile (profile.json), posts (posts.json),
+// @Description volunteer history (volunteering.json), shift sign-ups with check-ins (shifts.json), skills, availability
+// @Description and location (volunteer_profile.json) and the uploaded profile and post images (files/).
+// @Description Requires a logged-in session; API keys cannot export account data.
+// @Tags Users
+// @Produce application/zip
+// @Success 200 {file} file "ZIP archive"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Request made with an API key"
+// @Failure 404 {object} ErrorResponse "User not found"
+// @Failure 500 {object} ErrorResponse "Failed to export account data"
+// @Security BearerAuth
+// @Router /users/me/export [get]
+func (s *Server) handleExportAccountData(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromContext(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	user, err := s.db.GetUserByID(r.Context(), userID)
+	if err != nil {
+		slog.Error("Failed to fetch user for export", "error", err, "userID", userID)
+		respondWithError(w, http.StatusNotFound, "User not found")
+		return
+	}
+
+	// Everything except the image bytes is gathered up front so failures can
+	// still be reported as JSON errors before the archive starts streaming.
+	settings, err := s.db.ListUserPrivacySettings(r.Context(), []pgtype.UUID{userID})
+	if err != nil {
+		slog.Error("Failed to get privacy settings for export", "error", err, "userID", userID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to export account data")
+		return
+	}
+	privacy := PrivacySettingsDTO{PhoneVisibility: defaultPhoneVisibility, EmailVisibility: defaultEmailVisibility}
+	if len(settings) > 0 {
+		privacy = PrivacySettingsDTO{PhoneVisibility: settings[0].PhoneVisibility, EmailVisibility: settings[0].EmailVisibility}
+	}
+
+	posts, err := s.db.GetUserPosts(r.Context(), userID)
+	if err != nil {
+		slog.Error("Failed to get posts for export", "error", err, "userID", userID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to export account data")
+		return
+	}
+	images, err := s.db.ListUserPostImages(r.Context(), userID)
+	if err != nil {
+		slog.Error("Failed to get post images for export", "error", err, "userID", userID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to export account data")
+		return
+	}
+	volunteering, err := s.db.ListUserVolunteering(r.Context(), userID)
+	if err != nil {
+		slog.Error("Failed to get volunteer history for export", "error", err, "userID", userID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to export account data")
+		return
+	}
+	shiftSignups, err := s.db.ListUserShiftSignups(r.Context(), userID)
+	if err != nil {
+		slog.Error("Failed to get shift sign-ups for export", "error", err, "userID", userID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to export account data")
+		return
+	}
+	volunteerProfile, err := s.volunteerProfile(r.Context(), userID)
+	if err != nil {
+		slog.Error("Failed to get volunteer profile for export", "error", err, "userID", userID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to export account data")
+		return
+	}
+
+	var files []accountExportFile
+	addFile := func(objectURL, dir string) {
+		if objectName, ok := s.filestore.ObjectNameFromURL(objectURL); ok {
+			files = append(files, accountExportFile{objectName: objectName, archivePath: dir + "/" + path.Base(objectName)})
+		}
+	}
+	if user.ProfileUrl.Valid {
+		addFile(user.ProfileUrl.String, "files/profile")
+	}
+
+	postDTOs := make([]PostResponseDTO, len(posts))
+	postIndex := make(map[uuid.UUID]int, len(posts))
+	for i, p := range posts {
+		postDTOs[i] = toPostResponseDTO(p)
+		postIndex[postDTOs[i].ID] = i
+		if p.PreviewUrl.Valid {
+			addFile(p.PreviewUrl.String, fmt.Sprintf("files/posts/%s", postDTOs[i].ID))
+		}
+	}
+	for _, img := range images {
+		postID := uuid.UUID(img.PostID.Bytes)
+		if i, ok := postIndex[postID]; ok {
+			postDTOs[i].Images = append(postDTOs[i].Images, img.ImageUrl)
+		}
+		addFile(img.ImageUrl, fmt.Sprintf("files/posts/%s", postID))
+	}
+
+	volunteerDTOs := make([]PostVolunteerDTO, len(volunteering))
+	for i, v := range volunteering {
+		volunteerDTOs[i] = toPostVolunteerDTO(v)
+	}
+
+	shiftDTOs := make([]AccountExportShiftDTO, len(shiftSignups))
+	for i, su := range shiftSignups {
+		shiftDTOs[i] = AccountExportShiftDTO{
+			ShiftSignupDTO: toShiftSignupDTO(db.ShiftSignup{
+				ID:           su.ID,
+				ShiftID:      su.ShiftID,
+				VolunteerID:  su.VolunteerID,
+				CheckedInAt:  su.CheckedInAt,
+				CheckInLat:   su.CheckInLat,
+				CheckInLng:   su.CheckInLng,
+				CheckedOutAt: su.CheckedOutAt,
+				CheckOutLat:  su.CheckOutLat,
+				CheckOutLng:  su.CheckOutLng,
+				CreatedAt:    su.CreatedAt,
+			}, userID.Bytes),
+			PostID:   su.PostID.Bytes,
+			StartsAt: su.StartsAt.Time,
+			EndsAt:   su.EndsAt.Time,
+		}
+	}
+
+	exportedAt := time.Now().UTC()
+	filename := fmt.Sprintf("account-export-%s.zip", exportedAt.Format("20060102"))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+
+	archive := zip.NewWriter(w)
+	defer func() {
+		if err := archive.Close(); err != nil {
+			slog.Error("Failed to finish account export", "error", err, "userID", userID)
+		}
+	}()
+
+	documents := []struct {
+		name string
+		data any
+	}{
+		{"profile.json", AccountExportProfileDTO{User: ToUserResponseDTO(user), Privacy: privacy, ExportedAt: exportedAt}},
+		{"posts.json", postDTOs},
+		{"volunteering.json", volunteerDTOs},
+		{"shifts.json", shiftDTOs},
+		{"volunteer_profile.json", volunteerProfile},
+	}
+	for _, doc := range documents {
+		if err := writeExportJSON(archive, doc.name, doc.data); err != nil {
+			slog.Error("Failed to write account export", "error", err, "userID", userID, "file", doc.name)
+			return
+		}
+	}
+
+	for _, f := range files {
+		if err := s.copyExportFile(r.Context(), archive, f); err != nil {
+			// The response is already streaming, so a missing object is
+			// skipped rather than failing the whole export.
+			slog.Error("Failed to add file to account export", "error", err, "userID", userID, "object", f.objectName)
+		}
+	}
+}
+
+func writeExportJSON(archive *zip.Writer, name string, data any) error {
+	out, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(data)
+}
+
+func (s *Server) copyExportFile(ctx context.Context, archive *zip.Writer, f accountExportFile) error {
+	obj, err := s.filestore.GetObject(ctx, f.objectName)
+	if err != nil {
+		return err
+	}
+	defer obj.Close()
+
+	out, err := archive.Create(f.archivePath)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, obj)
+	return err
+}
@@ -0,0 +1,376 @@
+package server
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dukunuu/hackathon_backend/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	defaultNearbyRadius = 1000.0   // metres
+	maxNearbyRadius     = 50_000.0 // metres
+
+	// Below this zoom level in-bounds queries return clusters, not posts.
+	clusterMaxZoom = 14
+	maxZoom        = 22
+	// Posts closer than roughly this many pixels at the requested zoom share
+	// a cluster, assuming 256px web map tiles.
+	clusterCellPixels = 60
+	// maxClusterCells bounds how many cluster cells a clustered bbox may
+	// span: a 4K screen at its zoom level is about 64 by 36 cells.
+	maxClusterCells = 4096
+)
+
+// GeoPostDTO is a post placed on the map.
+// swagger:model GeoPostDTO
+type GeoPostDTO struct {
+	ID          uuid.UUID `json:"id" format:"uuid"`
+	Title       string    `json:"title"`
+	Status      string    `json:"status"`
+	Priority    string    `json:"priority"`
+	PostType    string    `json:"post_type"`
+	CategoryID  uuid.UUID `json:"category_id,omitempty" format:"uuid"`
+	PreviewURL  string    `json:"preview_url,omitempty"`
+	LocationLat float64   `json:"location_lat" example:"47.9187"`
+	LocationLng float64   `json:"location_lng" example:"106.9170"`
+	AddressText string    `json:"address_text,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	DistanceM   float64   `json:"distance_m" example:"350.5"` // From the search point
+}
+
+// PostClusterDTO is a group of nearby posts shown as one marker.
+// swagger:model PostClusterDTO
+type PostClusterDTO struct {
+	LocationLat float64 `json:"location_lat" example:"47.9187"` // Centre of the posts in the cluster
+	LocationLng float64 `json:"location_lng" example:"106.9170"`
+	Count       int64   `json:"count" example:"12"`
+	// Bounds of the posts in the cluster as min_lng,min_lat,max_lng,max_lat, for zooming in
+	BBox   [4]float64 `json:"bbox"`
+	PostID *uuid.UUID `json:"post_id,omitempty" format:"uuid"` // Set when the cluster holds a single post
+}
+
+// PostsInBoundsResponseDTO holds either posts or, when Clustered is set,
+// clusters of posts.
+// swagger:model PostsInBoundsResponseDTO
+type PostsInBoundsResponseDTO struct {
+	Clustered bool             `json:"clustered"`
+	Posts     []GeoPostDTO     `json:"posts,omitempty"`
+	Clusters  []PostClusterDTO `json:"clusters,omitempty"`
+}
+
+func toGeoPostDTO(row db.ListPostsNearbyRow) GeoPostDTO {
+	return GeoPostDTO{
+		ID:          row.ID.Bytes,
+		Title:       row.Title,
+		Status:      string(row.Status),
+		Priority:    string(row.Priority),
+		PostType:    string(row.PostType),
+		CategoryID:  row.CategoryID.Bytes,
+		PreviewURL:  row.PreviewUrl.String,
+		LocationLat: row.LocationLat.Float64,
+		LocationLng: row.LocationLng.Float64,
+		AddressText: row.AddressText.String,
+		CreatedAt:   row.CreatedAt.Time,
+		DistanceM:   row.DistanceM,
+	}
+}
+
+func validateCoordinates(lat, lng float64) error {
+	if math.IsNaN(lat) || math.IsInf(lat, 0) || lat < -90 || lat > 90 {
+		return fmt.Errorf("latitude must be between -90 and 90")
+	}
+	if math.IsNaN(lng) || math.IsInf(lng, 0) || lng < -180 || lng > 180 {
+		return fmt.Errorf("longitude must be between -180 and 180")
+	}
+	return nil
+}
+
+// postLocation validates the coordinates of a post. Posts without a location
+// send neither (or 0,0), which is stored as NULL.
+func postLocation(lat, lng float64) (pgtype.Float8, pgtype.Float8, error) {
+	if lat == 0 && lng == 0 {
+		return pgtype.Float8{}, pgtype.Float8{}, nil
+	}
+	if err := validateCoordinates(lat, lng); err != nil {
+		return pgtype.Float8{}, pgtype.Float8{}, err
+	}
+	return pgtype.Float8{Float64: lat, Valid: true}, pgtype.Float8{Float64: lng, Valid: true}, nil
+}
+
+// parseFloatParam reads a query parameter as a float, falling back to def
+// when it is absent.
+func parseFloatParam(r *http.Request, name string, def float64) (float64, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return def, nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("%s must be a number", name)
+	}
+	return f, nil
+}
+
+// parseResultLimit reads the 'limit' query parameter of unpaginated results.
+func parseResultLimit(r *http.Request) (int32, error) {
+	v := r.URL.Query().Get("limit")
+	if v == "" {
+		return defaultPageSize, nil
+	}
+	limit, err := strconv.Atoi(v)
+	if err != nil || limit < 1 || limit > maxPageSize {
+		return 0, fmt.Errorf("limit must be between 1 and %d", maxPageSize)
+	}
+	return int32(limit), nil
+}
+
+// parseBBox reads a 'min_lng,min_lat,max_lng,max_lat' bounding box.
+func parseBBox(v string) ([4]float64, error) {
+	var bbox [4]float64
+	parts := strings.Split(v, ",")
+	if len(parts) != 4 {
+		return bbox, fmt.Errorf("bbox must be min_lng,min_lat,max_lng,max_lat")
+	}
+	for i, p := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return bbox, fmt.Errorf("bbox must be min_lng,min_lat,max_lng,max_lat")
+		}
+		bbox[i] = f
+	}
+	if err := validateCoordinates(bbox[1], bbox[0]); err != nil {
+		return bbox, fmt.Errorf("bbox: %w", err)
+	}
+	if err := validateCoordinates(bbox[3], bbox[2]); err != nil {
+		return bbox, fmt.Errorf("bbox: %w", err)
+	}
+	if bbox[0] > bbox[2] || bbox[1] > bbox[3] {
+		return bbox, fmt.Errorf("bbox minimums must not exceed its maximums")
+	}
+	return bbox, nil
+}
+
+// clusterCellSize is the grid cell size, in degrees, that clusters posts
+// at the given zoom level.
+func clusterCellSize(zoom int) float64 {
+	return 360 / (256 * math.Exp2(float64(zoom))) * clusterCellPixels
+}
+
+// clusterCellCount is the number of grid cells bbox spans at the given zoom.
+func clusterCellCount(bbox [4]float64, zoom int) float64 {
+	cell := clusterCellSize(zoom)
+	return math.Ceil((bbox[2]-bbox[0])/cell) * math.Ceil((bbox[3]-bbox[1])/cell)
+}
+
+// handleListPostsNearby lists posts around a point.
+// @Summary List nearby posts
+// @Description Retrieves posts within 'radius' metres of a point, nearest first. Posts without a location are never included.
+// @Tags Posts
+// @Produce json
+// @Param lat query number true "Latitude of the point" minimum(-90) maximum(90)
+// @Param lng query number true "Longitude of the point" minimum(-180) maximum(180)
+// @Param radius query number false "Search radius in metres, up to 50000 (default 1000)"
+// @Param status query string false "Only posts with this status"
+// @Param priority query string false "Only posts with this priority"
+// @Param post_type query string false "Only posts of this type"
+// @Param category_id query string false "Only posts in this category" format(uuid)
+// @Param limit query int false "Maximum number of posts, 1-100 (default 20)"
+// @Success 200 {array} GeoPostDTO "Posts ordered by distance"
+// @Failure 400 {object} ErrorResponse "Invalid point, radius or filter"
+// @Failure 500 {object} ErrorResponse "Failed to retrieve posts"
+// @Router /posts/nearby [get]
+func (s *Server) handleListPostsNearby(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("lat") == "" || r.URL.Query().Get("lng") == "" {
+		respondWithError(w, http.StatusBadRequest, "lat and lng are required")
+		return
+	}
+	lat, err := parseFloatParam(r, "lat", 0)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	lng, err := parseFloatParam(r, "lng", 0)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := validateCoordinates(lat, lng); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	radius, err := parseFloatParam(r, "radius", defaultNearbyRadius)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if radius <= 0 || radius > maxNearbyRadius {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("radius must be between 0 and %.0f metres", maxNearbyRadius))
+		return
+	}
+	limit, err := parseResultLimit(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	filters, err := parsePostFilters(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rows, err := s.db.ListPostsNearby(r.Context(), db.ListPostsNearbyParams{
+		Lat:         lat,
+		Lng:         lng,
+		RadiusM:     radius,
+		Status:      filters.Status,
+		Priority:    filters.Priority,
+		PostType:    filters.PostType,
+		CategoryID:  filters.CategoryID,
+		ResultLimit: limit,
+	})
+	if err != nil {
+		slog.Error("Failed to list nearby posts", "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve posts")
+		return
+	}
+
+	dtos := make([]GeoPostDTO, len(rows))
+	for i, row := range rows {
+		dtos[i] = toGeoPostDTO(row)
+	}
+	respondWithJSON(w, http.StatusOK, dtos)
+}
+
+// handleListPostsInBounds lists or clusters the posts inside a map viewport.
+// @Summary List posts in a bounding box
+// @Description Retrieves posts inside 'bbox', nearest to 'lat'/'lng' (default: the centre of the box) first.
+// @Description When 'zoom' is below 14, nearby posts are grouped into clusters instead and 'clustered' is true.
+// @Description A clustered box may span at most 4096 cluster cells of 60 pixels at its zoom level, and at most that
+// @Description many clusters are returned; larger boxes get a 400, so clients should send the zoom of the map they show.
+// @Tags Posts
+// @Produce json
+// @Param bbox query string true "Bounding box as min_lng,min_lat,max_lng,max_lat" example(106.8,47.85,107.0,47.95)
+// @Param zoom query int false "Map zoom level, 0-22; below 14 the response is clustered"
+// @Param lat query number false "Latitude to sort by distance from"
+// @Param lng query number false "Longitude to sort by distance from"
+// @Param status query string false "Only posts with this status"
+// @Param priority query string false "Only posts with this priority"
+// @Param post_type query string false "Only posts of this type"
+// @Param category_id query string false "Only posts in this category" format(uuid)
+// @Param limit query int false "Maximum number of posts when not clustered, 1-100 (default 20)"
+// @Success 200 {object} PostsInBoundsResponseDTO "Posts or clusters"
+// @Failure 400 {object} ErrorResponse "Invalid bounding box, zoom or filter, or a box too large for its zoom level"
+// @Failure 500 {object} ErrorResponse "Failed to retrieve posts"
+// @Router /posts/in-bounds [get]
+func (s *Server) handleListPostsInBounds(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	bbox, err := parseBBox(q.Get("bbox"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	zoom := maxZoom
+	if v := q.Get("zoom"); v != "" {
+		zoom, err = strconv.Atoi(v)
+		if err != nil || zoom < 0 || zoom > maxZoom {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("zoom must be between 0 and %d", maxZoom))
+			return
+		}
+	}
+	filters, err := parsePostFilters(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if zoom < clusterMaxZoom {
+		if clusterCellCount(bbox, zoom) > maxClusterCells {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("bbox is too large for zoom %d; send a smaller box or a lower zoom", zoom))
+			return
+		}
+		rows, err := s.db.ClusterPostsInBounds(r.Context(), db.ClusterPostsInBoundsParams{
+			MinLng:      bbox[0],
+			MinLat:      bbox[1],
+			MaxLng:      bbox[2],
+			MaxLat:      bbox[3],
+			Status:      filters.Status,
+			Priority:    filters.Priority,
+			PostType:    filters.PostType,
+			CategoryID:  filters.CategoryID,
+			CellSize:    clusterCellSize(zoom),
+			MaxClusters: maxClusterCells,
+		})
+		if err != nil {
+			slog.Error("Failed to cluster posts", "error", err)
+			respondWithError(w, http.StatusInternalServerError, "Failed to retrieve posts")
+			return
+		}
+		clusters := make([]PostClusterDTO, len(rows))
+		for i, row := range rows {
+			clusters[i] = PostClusterDTO{
+				LocationLat: row.Lat,
+				LocationLng: row.Lng,
+				Count:       row.PostCount,
+				BBox:        [4]float64{row.MinLng, row.MinLat, row.MaxLng, row.MaxLat},
+			}
+			if row.PostCount == 1 {
+				postID := uuid.UUID(row.PostID.Bytes)
+				clusters[i].PostID = &postID
+			}
+		}
+		respondWithJSON(w, http.StatusOK, PostsInBoundsResponseDTO{Clustered: true, Clusters: clusters})
+		return
+	}
+
+	lat, err := parseFloatParam(r, "lat", (bbox[1]+bbox[3])/2)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	lng, err := parseFloatParam(r, "lng", (bbox[0]+bbox[2])/2)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := validateCoordinates(lat, lng); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	limit, err := parseResultLimit(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rows, err := s.db.ListPostsInBounds(r.Context(), db.ListPostsInBoundsParams{
+		Lat:         lat,
+		Lng:         lng,
+		MinLng:      bbox[0],
+		MinLat:      bbox[1],
+		MaxLng:      bbox[2],
+		MaxLat:      bbox[3],
+		Status:      filters.Status,
+		Priority:    filters.Priority,
+		PostType:    filters.PostType,
+		CategoryID:  filters.CategoryID,
+		ResultLimit: limit,
+	})
+	if err != nil {
+		slog.Error("Failed to list posts in bounds", "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve posts")
+		return
+	}
+	posts := make([]GeoPostDTO, len(rows))
+	for i, row := range rows {
+		posts[i] = toGeoPostDTO(db.ListPostsNearbyRow(row))
+	}
+	respondWithJSON(w, http.StatusOK, PostsInBoundsResponseDTO{Posts: posts})
+}
@@ -0,0 +1,231 @@
+package server
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dukunuu/hackathon_backend/db"
+	"github.com/dukunuu/hackathon_backend/mvt"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	maxGeoJSONFeatures = 10_000
+	maxTileFeatures    = 5_000
+
+	postsTileLayer = "posts"
+)
+
+// GeoJSONFeatureCollection is a GeoJSON (RFC 7946) FeatureCollection of posts.
+// swagger:model GeoJSONFeatureCollection
+type GeoJSONFeatureCollection struct {
+	Type     string           `json:"type" example:"FeatureCollection"`
+	Features []GeoJSONFeature `json:"features"`
+}
+
+// GeoJSONFeature is a post as a GeoJSON point feature.
+// swagger:model GeoJSONFeature
+type GeoJSONFeature struct {
+	Type       string         `json:"type" example:"Feature"`
+	ID         string         `json:"id" format:"uuid"`
+	Geometry   GeoJSONPoint   `json:"geometry"`
+	Properties map[string]any `json:"properties"`
+}
+
+// GeoJSONPoint is a GeoJSON Point geometry; coordinates are [longitude, latitude].
+// swagger:model GeoJSONPoint
+type GeoJSONPoint struct {
+	Type        string     `json:"type" example:"Point"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+// postFeatureProperties are the attributes exported with each located post.
+func postFeatureProperties(row db.ListPostFeaturesRow) map[string]any {
+	props := map[string]any{
+		"id":         uuid.UUID(row.ID.Bytes).String(),
+		"title":      row.Title,
+		"status":     string(row.Status),
+		"priority":   string(row.Priority),
+		"post_type":  string(row.PostType),
+		"created_at": row.CreatedAt.Time.UTC().Format(time.RFC3339),
+		"updated_at": row.UpdatedAt.Time.UTC().Format(time.RFC3339),
+	}
+	if row.CategoryID.Valid {
+		props["category_id"] = uuid.UUID(row.CategoryID.Bytes).String()
+	}
+	if row.CategoryName.Valid {
+		props["category"] = row.CategoryName.String
+	}
+	if row.AddressText.Valid {
+		props["address_text"] = row.AddressText.String
+	}
+	return props
+}
+
+// parsePostFeatureFilters reads the post listing filters for map exports.
+func parsePostFeatureFilters(r *http.Request) (db.ListPostFeaturesParams, error) {
+	var params db.ListPostFeaturesParams
+	filters, err := parsePostFilters(r)
+	if err != nil {
+		return params, err
+	}
+	params.Status = filters.Status
+	params.Priority = filters.Priority
+	params.PostType = filters.PostType
+	params.CategoryID = filters.CategoryID
+	if params.AuthorID, err = parseUUIDParam(r, "author"); err != nil {
+		return params, err
+	}
+	if params.CreatedAfter, err = parseTimeParam(r, "created_after"); err != nil {
+		return params, err
+	}
+	if params.CreatedBefore, err = parseTimeParam(r, "created_before"); err != nil {
+		return params, err
+	}
+	return params, nil
+}
+
+func setFeatureBounds(params *db.ListPostFeaturesParams, minLng, minLat, maxLng, maxLat float64) {
+	params.MinLng = pgtype.Float8{Float64: minLng, Valid: true}
+	params.MinLat = pgtype.Float8{Float64: minLat, Valid: true}
+	params.MaxLng = pgtype.Float8{Float64: maxLng, Valid: true}
+	params.MaxLat = pgtype.Float8{Float64: maxLat, Valid: true}
+}
+
+// handlePostsGeoJSON exports located posts as a GeoJSON FeatureCollection.
+// @Summary Export posts as GeoJSON
+// @Description Returns the posts that have a location as a GeoJSON FeatureCollection of points, newest first, for GIS tools.
+// @Description Takes the same filters as GET /posts plus an optional 'bbox'. At most 10000 features are returned.
+// @Tags Posts
+// @Produce application/geo+json
+// @Param bbox query string false "Bounding box as min_lng,min_lat,max_lng,max_lat"
+// @Param status query string false "Only posts with this status"
+// @Param priority query string false "Only posts with this priority"
+// @Param post_type query string false "Only posts of this type"
+// @Param category_id query string false "Only posts in this category" format(uuid)
+// @Param author query string false "Only posts by this user" format(uuid)
+// @Param created_after query string false "Only posts created at or after this time (RFC 3339 or YYYY-MM-DD)"
+// @Param created_before query string false "Only posts created before this time (RFC 3339 or YYYY-MM-DD)"
+// @Success 200 {object} GeoJSONFeatureCollection "GeoJSON FeatureCollection"
+// @Failure 400 {object} ErrorResponse "Invalid bounding box or filter"
+// @Failure 500 {object} ErrorResponse "Failed to export posts"
+// @Router /posts.geojson [get]
+func (s *Server) handlePostsGeoJSON(w http.ResponseWriter, r *http.Request) {
+	params, err := parsePostFeatureFilters(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if v := r.URL.Query().Get("bbox"); v != "" {
+		bbox, err := parseBBox(v)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		setFeatureBounds(&params, bbox[0], bbox[1], bbox[2], bbox[3])
+	}
+	params.MaxFeatures = maxGeoJSONFeatures
+
+	rows, err := s.db.ListPostFeatures(r.Context(), params)
+	if err != nil {
+		slog.Error("Failed to list posts for GeoJSON export", "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to export posts")
+		return
+	}
+
+	fc := GeoJSONFeatureCollection{Type: "FeatureCollection", Features: make([]GeoJSONFeature, len(rows))}
+	for i, row := range rows {
+		fc.Features[i] = GeoJSONFeature{
+			Type:       "Feature",
+			ID:         uuid.UUID(row.ID.Bytes).String(),
+			Geometry:   GeoJSONPoint{Type: "Point", Coordinates: [2]float64{row.LocationLng.Float64, row.LocationLat.Float64}},
+			Properties: postFeatureProperties(row),
+		}
+	}
+	body, err := json.Marshal(fc)
+	if err != nil {
+		slog.Error("Failed to encode GeoJSON export", "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to export posts")
+		return
+	}
+	w.Header().Set("Content-Type", "application/geo+json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// handlePostTile renders located posts as a Mapbox Vector Tile.
+// @Summary Posts vector tile
+// @Description Returns a Mapbox Vector Tile (XYZ scheme) with a 'posts' point layer. Features carry id, title, status,
+// @Description priority, post_type, category_id, category, address_text, created_at and updated_at properties.
+// @Description Takes the same filters as GET /posts. At most 5000 features are included per tile.
+// @Tags Posts
+// @Produce application/vnd.mapbox-vector-tile
+// @Param z path int true "Zoom level, 0-22"
+// @Param x path int true "Tile column"
+// @Param y path int true "Tile row"
+// @Param status query string false "Only posts with this status"
+// @Param priority query string false "Only posts with this priority"
+// @Param post_type query string false "Only posts of this type"
+// @Param category_id query string false "Only posts in this category" format(uuid)
+// @Param author query string false "Only posts by this user" format(uuid)
+// @Param created_after query string false "Only posts created at or after this time (RFC 3339 or YYYY-MM-DD)"
+// @Param created_before query string false "Only posts created before this time (RFC 3339 or YYYY-MM-DD)"
+// @Success 200 {file} file "Vector tile"
+// @Failure 400 {object} ErrorResponse "Invalid tile coordinates or filter"
+// @Failure 500 {object} ErrorResponse "Failed to render tile"
+// @Router /tiles/{z}/{x}/{y}.mvt [get]
+func (s *Server) handlePostTile(w http.ResponseWriter, r *http.Request) {
+	z, errZ := strconv.ParseUint(chi.URLParam(r, "z"), 10, 32)
+	x, errX := strconv.ParseUint(chi.URLParam(r, "x"), 10, 32)
+	y, errY := strconv.ParseUint(chi.URLParam(r, "y"), 10, 32)
+	if errZ != nil || errX != nil || errY != nil {
+		respondWithError(w, http.StatusBadRequest, "Tile coordinates must be non-negative integers")
+		return
+	}
+	tile := mvt.Tile{Z: uint32(z), X: uint32(x), Y: uint32(y)}
+	if err := tile.Validate(); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid tile: "+err.Error())
+		return
+	}
+
+	params, err := parsePostFeatureFilters(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	minLng, minLat, maxLng, maxLat := tile.Bounds()
+	setFeatureBounds(&params, minLng, minLat, maxLng, maxLat)
+	params.MaxFeatures = maxTileFeatures
+
+	rows, err := s.db.ListPostFeatures(r.Context(), params)
+	if err != nil {
+		slog.Error("Failed to list posts for vector tile", "error", err, "z", z, "x", x, "y", y)
+		respondWithError(w, http.StatusInternalServerError, "Failed to render tile")
+		return
+	}
+
+	layer := mvt.Layer{Name: postsTileLayer, Features: make([]mvt.Feature, len(rows))}
+	for i, row := range rows {
+		layer.Features[i] = mvt.Feature{
+			Lng:        row.LocationLng.Float64,
+			Lat:        row.LocationLat.Float64,
+			Properties: postFeatureProperties(row),
+		}
+	}
+	body, err := mvt.Encode(tile, layer)
+	if err != nil {
+		slog.Error("Failed to encode vector tile", "error", err, "z", z, "x", x, "y", y)
+		respondWithError(w, http.StatusInternalServerError, "Failed to render tile")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/vnd.mapbox-vector-tile")
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
@@ -6,14 +6,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"log/slog"
 	"net/http"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/dukunuu/hackathon_backend/db" // ADJUST THIS IMPORT PATH
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
@@ -28,14 +29,144 @@ const (
 )
 
 
-// handleGetUserPosts retrieves all posts for a specific user.
+func isValidPostStatus(v db.PostStatus) bool {
+	switch v {
+	case db.PostStatusValue0, db.PostStatusValue1, db.PostStatusValue2, db.PostStatusValue3, db.PostStatusValue4:
+		return true
+	}
+	return false
+}
+
+func isValidPostPriority(v db.PostPriority) bool {
+	switch v {
+	case db.PostPriorityValue0, db.PostPriorityValue1, db.PostPriorityValue2:
+		return true
+	}
+	return false
+}
+
+func isValidPostType(v db.PostType) bool {
+	switch v {
+	case db.PostTypeValue0, db.PostTypeValue1:
+		return true
+	}
+	return false
+}
+
+// postFilters are the enum and category filters shared by post listings,
+// search and map queries.
+type postFilters struct {
+	Status     db.NullPostStatus
+	Priority   db.NullPostPriority
+	PostType   db.NullPostType
+	CategoryID pgtype.UUID
+}
+
+// parsePostFilters reads the 'status', 'priority', 'post_type' and
+// 'category_id' query parameters. The error message is meant for a 400
+// response.
+func parsePostFilters(r *http.Request) (postFilters, error) {
+	var f postFilters
+	q := r.URL.Query()
+
+	if v := q.Get("status"); v != "" {
+		if !isValidPostStatus(db.PostStatus(v)) {
+			return f, fmt.Errorf("Invalid status '%s'", v)
+		}
+		f.Status = db.NullPostStatus{PostStatus: db.PostStatus(v), Valid: true}
+	}
+	if v := q.Get("priority"); v != "" {
+		if !isValidPostPriority(db.PostPriority(v)) {
+			return f, fmt.Errorf("Invalid priority '%s'", v)
+		}
+		f.Priority = db.NullPostPriority{PostPriority: db.PostPriority(v), Valid: true}
+	}
+	if v := q.Get("post_type"); v != "" {
+		if !isValidPostType(db.PostType(v)) {
+			return f, fmt.Errorf("Invalid post_type '%s'", v)
+		}
+		f.PostType = db.NullPostType{PostType: db.PostType(v), Valid: true}
+	}
+	var err error
+	f.CategoryID, err = parseUUIDParam(r, "category_id")
+	return f, err
+}
+
+// parseListPostsParams reads the post listing filters and paging parameters.
+// The error message is meant for a 400 response.
+func parseListPostsParams(r *http.Request) (db.ListPostsParams, pageRequest, error) {
+	var params db.ListPostsParams
+	page, err := parsePageRequest(r)
+	if err != nil {
+		return params, page, err
+	}
+	filters, err := parsePostFilters(r)
+	if err != nil {
+		return params, page, err
+	}
+	params.Status = filters.Status
+	params.Priority = filters.Priority
+	params.PostType = filters.PostType
+	params.CategoryID = filters.CategoryID
+	if params.AuthorID, err = parseUUIDParam(r, "author"); err != nil {
+		return params, page, err
+	}
+	if params.CreatedAfter, err = parseTimeParam(r, "created_after"); err != nil {
+		return params, page, err
+	}
+	if params.CreatedBefore, err = parseTimeParam(r, "created_before"); err != nil {
+		return params, page, err
+	}
+
+	params.SortAsc = page.SortAsc
+	params.CursorCreatedAt = page.cursorCreatedAt()
+	params.CursorID = page.cursorID()
+	params.PageSize = page.fetchSize()
+	return params, page, nil
+}
+
+// respondWithPostPage runs a post listing query and writes one page of it.
+func (s *Server) respondWithPostPage(w http.ResponseWriter, r *http.Request, params db.ListPostsParams, page pageRequest) {
+	rows, err := s.db.ListPosts(r.Context(), params)
+	if err != nil {
+		slog.Error("Failed to list posts", "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve posts")
+		return
+	}
+	rows, nextCursor := trimPage(page, rows, func(row db.ListPostsRow) (pgtype.Timestamptz, pgtype.UUID) {
+		return row.CreatedAt, row.ID
+	})
+
+	responseDTOs := make([]PostResponseDTO, 0, len(rows))
+	for _, row := range rows {
+		dto, err := toPostResponseDTOFromListPostsRow(row)
+		if err != nil {
+			slog.Error("Failed to process post row for listing", "postID", row.ID.Bytes, "error", err)
+			continue
+		}
+		responseDTOs = append(responseDTOs, dto)
+	}
+	respondWithJSON(w, http.StatusOK, PageDTO[PostResponseDTO]{Items: responseDTOs, NextCursor: nextCursor})
+}
+
+// handleGetUserPosts retrieves the posts of a specific user.
 // @Summary Get posts by user ID
-// @Description Retrieves all posts created by a specific user.
+// @Description Retrieves the posts created by a specific user, newest first, one page at a time.
+// @Description Accepts the same filters and paging parameters as GET /posts, except 'author'.
 // @Tags Posts
 // @Produce json
 // @Param userId path string true "User ID" format(uuid)
-// @Success 200 {array} PostResponseDTO "Successfully retrieved user's posts"
-// @Failure 400 {object} ErrorResponse "Invalid user ID format"
+// @Param status query string false "Only posts with this status"
+// @Param priority query string false "Only posts with this priority"
+// @Param post_type query string false "Only posts of this type"
+// @Param category_id query string false "Only posts in this category" format(uuid)
+// @Param created_after query string false "Only posts created at or after this time (RFC 3339 or YYYY-MM-DD)"
+// @Param created_before query string false "Only posts created before this time (RFC 3339 or YYYY-MM-DD)"
+// @Param sort query string false "created_at (oldest first) or -created_at (newest first, default)" Enums(created_at, -created_at)
+// @Param limit query int false "Page size, 1-100 (default 20)"
+// @Param cursor query string false "next_cursor from the previous page"
+// @Success 200 {object} PageDTO[PostResponseDTO] "One page of the user's posts"
+// @Failure 400 {object} ErrorResponse "Invalid user ID, filter or cursor"
 // @Failure 401 {object} ErrorResponse "Unauthorized"
 // @Failure 500 {object} ErrorResponse "Failed to retrieve user posts"
 // @Security BearerAuth
@@ -52,46 +183,43 @@ func (s *Server) handleGetUserPosts(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	posts, err := s.db.GetUserPosts(r.Context(), toPgtypeUUID(userID))
+	params, page, err := parseListPostsParams(r)
 	if err != nil {
-		slog.Error("Failed to get user posts", "error", err, "userID", userID)
-		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve user posts")
+		respondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	params.AuthorID = toPgtypeUUID(userID)
 
-	var responseDTOs []PostResponseDTO
-	for _, p := range posts {
-		responseDTOs = append(responseDTOs, toPostResponseDTO(p))
-	}
-	respondWithJSON(w, http.StatusOK, responseDTOs)
+	s.respondWithPostPage(w, r, params, page)
 }
 
-// handleListPosts retrieves all posts with their images and volunteers.
-// @Summary List all posts
-// @Description Retrieves a list of all posts, including associated images and volunteers.
+// handleListPosts retrieves posts with their images.
+// @Summary List posts
+// @Description Retrieves posts, including associated images and volunteer counts, one page at a time.
+// @Description Pages are ordered by creation time; follow 'next_cursor' until it is omitted to read them all.
 // @Tags Posts
 // @Produce json
-// @Success 200 {array} PostResponseDTO "Successfully retrieved list of posts"
+// @Param status query string false "Only posts with this status"
+// @Param priority query string false "Only posts with this priority"
+// @Param post_type query string false "Only posts of this type"
+// @Param category_id query string false "Only posts in this category" format(uuid)
+// @Param author query string false "Only posts by this user" format(uuid)
+// @Param created_after query string false "Only posts created at or after this time (RFC 3339 or YYYY-MM-DD)"
+// @Param created_before query string false "Only posts created before this time (RFC 3339 or YYYY-MM-DD)"
+// @Param sort query string false "created_at (oldest first) or -created_at (newest first, default)" Enums(created_at, -created_at)
+// @Param limit query int false "Page size, 1-100 (default 20)"
+// @Param cursor query string false "next_cursor from the previous page"
+// @Success 200 {object} PageDTO[PostResponseDTO] "One page of posts"
+// @Failure 400 {object} ErrorResponse "Invalid filter or cursor"
 // @Failure 500 {object} ErrorResponse "Failed to retrieve posts"
 // @Router /posts [get]
 func (s *Server) handleListPosts(w http.ResponseWriter, r *http.Request) {
-	listPostsRows, err := s.db.ListPosts(r.Context())
+	params, page, err := parseListPostsParams(r)
 	if err != nil {
-		slog.Error("Failed to list posts", "error", err)
-		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve posts")
+		respondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-
-	var responseDTOs []PostResponseDTO
-	for _, row := range listPostsRows {
-		dto, err := toPostResponseDTOFromListPostsRow(row)
-		if err != nil {
-			slog.Error("Failed to process post row for listing", "postID", row.ID.Bytes, "error", err)
-			continue
-		}
-		responseDTOs = append(responseDTOs, dto)
-	}
-	respondWithJSON(w, http.StatusOK, responseDTOs)
+	s.respondWithPostPage(w, r, params, page)
 }
 
 // handleCreatePost creates a new post, optionally with images and AI categorization.
@@ -152,6 +280,15 @@ func (s *Server) handleCreatePost(w http.ResponseWriter, r *http.Request) {
 		)
 		return
 	}
+	if req.MaxVolunteers < 0 {
+		respondWithError(w, http.StatusBadRequest, "max_volunteers must be a non-negative number")
+		return
+	}
+	locationLat, locationLng, err := postLocation(req.LocationLat, req.LocationLng)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid location in 'postData': "+err.Error())
+		return
+	}
 
 	// --- AI Categorization ---
 	var chosenCategoryID pgtype.UUID // Assuming CategoryID in DB is UUID and nullable
@@ -271,7 +408,6 @@ func (s *Server) handleCreatePost(w http.ResponseWriter, r *http.Request) {
 	}
 	// --- End AI Categorization ---
 
-	uploadedImageURLs := []string{}
 	formFiles := r.MultipartForm.File["postImages"]
 
 	if len(formFiles) > maxPostImages {
@@ -283,122 +419,28 @@ func (s *Server) handleCreatePost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	for _, handler := range formFiles {
-		file, err := handler.Open()
-		if err != nil {
-			slog.Error(
-				"Failed to open an uploaded post image file",
-				"filename",
-				handler.Filename,
-				"error",
-				err,
-			)
-			respondWithError(
-				w,
-				http.StatusInternalServerError,
-				"Error processing uploaded file: "+handler.Filename,
-			)
-			return
-		}
-
-		if handler.Size > maxPostImageFileSize {
-			file.Close()
-			respondWithError(
-				w,
-				http.StatusBadRequest,
-				fmt.Sprintf(
-					"Post image '%s' too large. Maximum size is %dMB.",
-					handler.Filename,
-					maxPostImageFileSize/(1024*1024),
-				),
-			)
-			return
-		}
-
-		buffer := make([]byte, 512)
-		_, readErr := file.Read(buffer)
-		if readErr != nil && readErr != io.EOF {
-			file.Close()
-			slog.Error(
-				"Failed to read post image for MIME type detection",
-				"filename",
-				handler.Filename,
-				"error",
-				readErr,
-			)
-			respondWithError(
-				w,
-				http.StatusInternalServerError,
-				"Could not read image for type detection: "+handler.Filename,
-			)
-			return
-		}
-		_, seekErr := file.Seek(0, io.SeekStart)
-		if seekErr != nil {
-			file.Close()
-			slog.Error(
-				"Failed to seek post image after MIME type detection",
-				"filename",
-				handler.Filename,
-				"error",
-				seekErr,
-			)
-			respondWithError(
-				w,
-				http.StatusInternalServerError,
-				"Could not process image: "+handler.Filename,
-			)
-			return
-		}
-
-		contentType := http.DetectContentType(buffer)
-		if !allowedMimeTypes[contentType] {
-			file.Close()
-			slog.Warn(
-				"Invalid post image type uploaded",
-				"contentType",
-				contentType,
-				"filename",
-				handler.Filename,
-			)
-			respondWithError(
-				w,
-				http.StatusBadRequest,
-				fmt.Sprintf(
-					"Invalid image type for '%s': %s. Allowed types: jpeg, png, gif, webp.",
-					handler.Filename,
-					contentType,
-				),
-			)
-			return
-		}
-
-		fileBytes, readAllErr := io.ReadAll(file)
-		file.Close()
-		if readAllErr != nil {
-			slog.Error(
-				"Failed to read post image into buffer",
-				"filename",
-				handler.Filename,
-				"error",
-				readAllErr,
-			)
-			respondWithError(
-				w,
-				http.StatusInternalServerError,
-				"Could not read image content: "+handler.Filename,
-			)
+	// Every image is checked before any is uploaded.
+	imageContents := make([][]byte, len(formFiles))
+	imageTypes := make([]string, len(formFiles))
+	for i, handler := range formFiles {
+		var ok bool
+		if imageContents[i], imageTypes[i], ok = readImageUpload(w, handler, maxPostImageFileSize); !ok {
 			return
 		}
+	}
 
-		tempEntityIDForPath := uuid.New()
-		uploadedURL, _, uploadErr := s.filestore.UploadProfileImage(
+	// The ID is chosen up front so the images are stored under the post.
+	postID := uuid.New()
+	uploadedImageURLs := []string{}
+	uploadedObjects := []string{}
+	for i, handler := range formFiles {
+		uploadedURL, objectName, uploadErr := s.filestore.UploadPostImage(
 			r.Context(),
-			fileBytes,
+			imageContents[i],
 			handler.Filename,
-			contentType,
-			tempEntityIDForPath,
-		) // Assuming UploadProfileImage can be used or adapt to UploadPostImage
+			imageTypes[i],
+			postID,
+		)
 		if uploadErr != nil {
 			slog.Error(
 				"Failed to upload post image via filestore",
@@ -407,6 +449,7 @@ func (s *Server) handleCreatePost(w http.ResponseWriter, r *http.Request) {
 				"error",
 				uploadErr,
 			)
+			s.removeUploadedObjects(r.Context(), uploadedObjects)
 			respondWithError(
 				w,
 				http.StatusInternalServerError,
@@ -415,6 +458,7 @@ func (s *Server) handleCreatePost(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		uploadedImageURLs = append(uploadedImageURLs, uploadedURL)
+		uploadedObjects = append(uploadedObjects, objectName)
 		slog.Info(
 			"Successfully uploaded post image",
 			"filename",
@@ -425,23 +469,21 @@ func (s *Server) handleCreatePost(w http.ResponseWriter, r *http.Request) {
 	}
 
 	params := db.CreatePostParams{
+		ID:                toPgtypeUUID(postID),
 		Title:             req.Title,
 		Description:       req.Description,
 		PostType:          db.PostType(req.PostType), // Ensure db.PostType is the correct type
 		UserID:            authUserID,
 		MaxVolunteers:     req.MaxVolunteers,
 		CurrentVolunteers: 0,
-		LocationLat:       toPgtypeFloat8(req.LocationLat),
-		LocationLng:       toPgtypeFloat8(req.LocationLng),
+		LocationLat:       locationLat,
+		LocationLng:       locationLng,
 		AddressText:       toPgtypeText(req.AddressText),
 		CategoryID:        chosenCategoryID, // Set the AI-determined category ID
 	}
 
-	if req.Status == "" {
-		params.Status = db.PostStatus("Хүлээгдэж байгаа") // Default status
-	} else {
-		params.Status = db.PostStatus(req.Status) // Ensure db.PostStatus is correct type
-	}
+	// New posts always start the status workflow as pending.
+	params.Status = postStatusPending
 
 	if req.Priority == "" {
 		params.Priority = db.PostPriority("бага") // Default priority
@@ -455,7 +497,30 @@ func (s *Server) handleCreatePost(w http.ResponseWriter, r *http.Request) {
 		params.PreviewUrl = toPgtypeText(uploadedImageURLs[0])
 	}
 
-	createdPost, err := s.db.CreatePost(r.Context(), params)
+	// The post, its first status and its images are stored together or not at all.
+	var createdPost db.Post
+	err = s.db.ExecTx(r.Context(), func(q *db.Queries) error {
+		var err error
+		if createdPost, err = q.CreatePost(r.Context(), params); err != nil {
+			return fmt.Errorf("create post: %w", err)
+		}
+		if _, err := q.CreatePostStatusHistory(r.Context(), db.CreatePostStatusHistoryParams{
+			PostID:    createdPost.ID,
+			ToStatus:  createdPost.Status,
+			ChangedBy: authUserID,
+		}); err != nil {
+			return fmt.Errorf("record initial status: %w", err)
+		}
+		for _, imgURL := range uploadedImageURLs {
+			if _, err := q.CreatePostImage(r.Context(), db.CreatePostImageParams{
+				PostID:   createdPost.ID,
+				ImageUrl: imgURL,
+			}); err != nil {
+				return fmt.Errorf("link image %s: %w", imgURL, err)
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		slog.Error(
 			"Failed to create post in DB",
@@ -464,53 +529,27 @@ func (s *Server) handleCreatePost(w http.ResponseWriter, r *http.Request) {
 			"userID",
 			authUserID.String(),
 		)
+		s.removeUploadedObjects(r.Context(), uploadedObjects)
 		respondWithError(w, http.StatusInternalServerError, "Failed to create post: "+err.Error())
 		return
 	}
 
-	finalLinkedImageURLs := []string{} // This will be part of the response DTO
-	for _, imgURL := range uploadedImageURLs {
-		_, dbErr := s.db.CreatePostImage(r.Context(), db.CreatePostImageParams{
-			PostID:   createdPost.ID,
-			ImageUrl: imgURL,
-		})
-		if dbErr != nil {
-			slog.Error(
-				"Failed to create post_image record in DB",
-				"postID",
-				createdPost.ID,
-				"imageURL",
-				imgURL,
-				"error",
-				dbErr,
-			)
-			// For simplicity, we log and continue; the image won't be linked.
-			// For stricter handling, you might want to roll back or delete the post.
-			// Or return an error for the whole request.
-			respondWithError(
-				w,
-				http.StatusInternalServerError,
-				"Failed to link image '"+imgURL+"' to post: "+dbErr.Error(),
-			)
-			return
-		}
-		finalLinkedImageURLs = append(finalLinkedImageURLs, imgURL)
-	}
-
 	responseDTO := toPostResponseDTO(createdPost) // Ensure this DTO can include image URLs
-	// You might want to add finalLinkedImageURLs to responseDTO if it's designed for it
-	// e.g., responseDTO.ImageURLs = finalLinkedImageURLs
 
 	respondWithJSON(w, http.StatusCreated, responseDTO)
 }
 
 // handleGetPost retrieves a single post by its ID.
 // @Summary Get post by ID
-// @Description Retrieves details for a specific post, including images and volunteers.
+// @Description Retrieves details for a specific post, including images and volunteer counts. The author of the post
+// @Description lists its volunteers with GET /posts/{postId}/volunteers.
 // @Tags Posts
 // @Produce json
 // @Param postId path string true "Post ID" format(uuid)
+// @Param If-None-Match header string false "ETag from an earlier response; a 304 is returned while it is current"
 // @Success 200 {object} PostResponseDTO "Successfully retrieved post"
+// @Header 200 {string} ETag "Version of the post, for If-Match and If-None-Match"
+// @Success 304 "Post has not changed"
 // @Failure 400 {object} ErrorResponse "Invalid post ID format"
 // @Failure 404 {object} ErrorResponse "Post not found"
 // @Failure 500 {object} ErrorResponse "Failed to retrieve post"
@@ -522,7 +561,12 @@ func (s *Server) handleGetPost(w http.ResponseWriter, r *http.Request) {
 		respondWithError(w, http.StatusBadRequest, "Invalid post ID format")
 		return
 	}
+	s.respondWithPost(w, r, postID)
+}
 
+// respondWithPost answers with the current representation of a post and its
+// ETag.
+func (s *Server) respondWithPost(w http.ResponseWriter, r *http.Request, postID uuid.UUID) {
 	postRow, err := s.db.GetPost(r.Context(), toPgtypeUUID(postID))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
@@ -540,12 +584,12 @@ func (s *Server) handleGetPost(w http.ResponseWriter, r *http.Request) {
 		respondWithError(w, http.StatusInternalServerError, "Failed to process post data")
 		return
 	}
-	respondWithJSON(w, http.StatusOK, dto)
+	respondWithTaggedJSON(w, r, http.StatusOK, versionETag(postRow.UpdatedAt), dto)
 }
 
 // handleDeletePost deletes a post by its ID.
 // @Summary Delete post by ID
-// @Description Deletes a specific post. Only the owner of the post can delete it.
+// @Description Deletes a specific post. Only the owner of the post or a moderator (posts:moderate) can delete it.
 // @Tags Posts
 // @Produce json
 // @Param postId path string true "Post ID" format(uuid)
@@ -582,7 +626,7 @@ func (s *Server) handleDeletePost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if post.UserID.Bytes != authUserID.Bytes {
+	if post.UserID.Bytes != authUserID.Bytes && !hasPermission(r.Context(), PermPostsModerate) {
 		respondWithError(w, http.StatusForbidden, "You are not authorized to delete this post")
 		return
 	}
@@ -599,16 +643,26 @@ func (s *Server) handleDeletePost(w http.ResponseWriter, r *http.Request) {
 // handleUpdatePost updates an existing post.
 // @Summary Update post by ID
 // @Description Updates details of a specific post. Only the owner of the post can update it.
+// @Description A changed status must be an allowed transition, see POST /posts/{postId}/status; it is recorded in the post's history.
+// @Description A complaint cannot be changed to another post_type.
+// @Description Omitted or empty fields keep their current value; title and description cannot be set empty.
+// @Description Use PATCH /posts/{postId} to clear optional fields.
+// @Description The If-Match header must carry the post's current ETag so concurrent edits are not overwritten.
 // @Tags Posts
 // @Accept json
 // @Produce json
 // @Param postId path string true "Post ID" format(uuid)
+// @Param If-Match header string true "ETag of the post from GET /posts/{postId}"
 // @Param postUpdateData body UpdatePostRequest true "Post update details"
 // @Success 200 {object} PostResponseDTO "Successfully updated post"
+// @Header 200 {string} ETag "New version of the post"
 // @Failure 400 {object} ErrorResponse "Invalid request payload or post ID format"
 // @Failure 401 {object} ErrorResponse "Authentication required"
 // @Failure 403 {object} ErrorResponse "Forbidden - not authorized to update this post"
 // @Failure 404 {object} ErrorResponse "Post not found to update"
+// @Failure 409 {object} ErrorResponse "Status transition or post_type change not allowed"
+// @Failure 412 {object} ErrorResponse "Post was modified since the ETag was issued"
+// @Failure 428 {object} ErrorResponse "If-Match header missing"
 // @Failure 500 {object} ErrorResponse "Failed to update post"
 // @Security BearerAuth
 // @Router /posts/{postId} [put]
@@ -641,6 +695,9 @@ func (s *Server) handleUpdatePost(w http.ResponseWriter, r *http.Request) {
 		respondWithError(w, http.StatusForbidden, "You are not authorized to update this post")
 		return
 	}
+	if !checkIfMatch(w, r, versionETag(existingPost.UpdatedAt)) {
+		return
+	}
 
 	var req UpdatePostRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -649,63 +706,128 @@ func (s *Server) handleUpdatePost(w http.ResponseWriter, r *http.Request) {
 	}
 	defer r.Body.Close()
 
-	params := db.UpdatePostParams{
-		ID:                toPgtypeUUID(postID),
-		Title:             req.Title,
-		Description:       req.Description,
-		Status:            db.PostStatus(req.Status),
-		Priority:          db.PostPriority(req.Priority),
-		PreviewUrl:        toPgtypeText(req.PreviewURL),
-		PostType:          db.PostType(req.PostType),
-		UserID:            authUserID,
-		MaxVolunteers:     req.MaxVolunteers,
-		CurrentVolunteers: req.CurrentVolunteers,
-		CategoryID:        toPgtypeUUID(req.CategoryID),
-		LocationLat:       toPgtypeFloat8(req.LocationLat),
-		LocationLng:       toPgtypeFloat8(req.LocationLng),
-		AddressText:       toPgtypeText(req.AddressText),
-	}
-
-	updatedPost, err := s.db.UpdatePost(r.Context(), params)
+	// Omitted coordinates keep the current location.
+	locationLat, locationLng, err := postLocation(req.LocationLat, req.LocationLng)
 	if err != nil {
-		slog.Error("Failed to update post", "error", err, "postID", postID)
-		respondWithError(w, http.StatusInternalServerError, "Failed to update post: "+err.Error())
+		respondWithError(w, http.StatusBadRequest, "Invalid location: "+err.Error())
 		return
 	}
-	respondWithJSON(w, http.StatusOK, toPostResponseDTO(updatedPost))
-}
 
-// handleListPostVolunteers lists all volunteer applications.
-// @Summary List all post volunteers
-// @Description Retrieves a list of all volunteer applications across all posts. (Consider admin-only access and pagination).
-// @Tags Posts, Volunteers
-// @Produce json
-// @Success 200 {array} PostVolunteerDTO "Successfully retrieved list of post volunteers"
-// @Failure 401 {object} ErrorResponse "Authentication required"
-// @Failure 500 {object} ErrorResponse "Failed to retrieve post volunteers"
-// @Security BearerAuth
-// @Router /posts/volunteers [get]
-func (s *Server) handleListPostVolunteers(w http.ResponseWriter, r *http.Request) {
-	volunteers, err := s.db.ListPostVolunteers(r.Context())
-	if err != nil {
-		slog.Error("Failed to list post volunteers", "error", err)
-		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve post volunteers")
+	var title, description pgtype.Text
+	if req.Title != nil {
+		n := utf8.RuneCountInString(*req.Title)
+		if n == 0 {
+			respondWithError(w, http.StatusBadRequest, "title cannot be empty")
+			return
+		}
+		if n > maxPostTitleLength {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("title must be at most %d characters", maxPostTitleLength))
+			return
+		}
+		title = pgtype.Text{String: *req.Title, Valid: true}
+	}
+	if req.Description != nil {
+		if *req.Description == "" {
+			respondWithError(w, http.StatusBadRequest, "description cannot be empty")
+			return
+		}
+		description = pgtype.Text{String: *req.Description, Valid: true}
+	}
+
+	// Capacity is only checked when the request changes it.
+	var maxVolunteers pgtype.Int4
+	if req.MaxVolunteers != nil {
+		if *req.MaxVolunteers < 0 {
+			respondWithError(w, http.StatusBadRequest, "max_volunteers must be a non-negative number")
+			return
+		}
+		if *req.MaxVolunteers < existingPost.CurrentVolunteers {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("max_volunteers cannot be lower than the %d volunteers already on the post", existingPost.CurrentVolunteers))
+			return
+		}
+		maxVolunteers = pgtype.Int4{Int32: *req.MaxVolunteers, Valid: true}
+	}
+
+	// Empty enums keep their current value rather than reaching the database.
+	priority := existingPost.Priority
+	if req.Priority != "" {
+		if !isValidPostPriority(db.PostPriority(req.Priority)) {
+			respondWithError(w, http.StatusBadRequest, "Invalid priority")
+			return
+		}
+		priority = db.PostPriority(req.Priority)
+	}
+	postType := existingPost.PostType
+	if req.PostType != "" {
+		if !isValidPostType(db.PostType(req.PostType)) {
+			respondWithError(w, http.StatusBadRequest, "Invalid post_type")
+			return
+		}
+		postType = db.PostType(req.PostType)
+	}
+	if err := checkPostTypeChange(existingPost, postType); err != nil {
+		respondWithError(w, http.StatusConflict, err.Error())
 		return
 	}
 
-	var dtos []PostVolunteerDTO
-	for _, v := range volunteers {
-		dtos = append(dtos, PostVolunteerDTO{
-			ID:        v.ID.Bytes,
-			UserID:    v.UserID.Bytes,
-			PostID:    v.PostID.Bytes,
-			Status:    v.Status.String,
-			Notes:     v.Notes.String,
-			CreatedAt: v.CreatedAt.Time,
-			UpdatedAt: v.UpdatedAt.Time,
-		})
+	// A new status goes through the same workflow as POST /posts/{postId}/status,
+	// once the other fields are saved.
+	newStatus := db.PostStatus(req.Status)
+	changeStatus := req.Status != "" && newStatus != existingPost.Status
+	if changeStatus {
+		if !isValidPostStatus(newStatus) {
+			respondWithError(w, http.StatusBadRequest, "Invalid status")
+			return
+		}
+		if err := checkPostTransition(r.Context(), existingPost, authUserID, newStatus); err != nil {
+			respondWithStatusChangeError(w, err, postID)
+			return
+		}
+	}
+
+	params := db.UpdatePostParams{
+		Title:         title,
+		Description:   description,
+		Status:        existingPost.Status,
+		Priority:      priority,
+		PreviewUrl:    toPgtypeText(req.PreviewURL),
+		PostType:      postType,
+		UserID:        authUserID,
+		MaxVolunteers: maxVolunteers,
+		CategoryID:    toPgtypeUUID(req.CategoryID),
+		LocationLat:   locationLat,
+		LocationLng:   locationLng,
+		AddressText:   toPgtypeText(req.AddressText),
+		ID:            toPgtypeUUID(postID),
+		UpdatedAt:     existingPost.UpdatedAt,
+	}
+
+	// The fields and the status change are saved together or not at all.
+	err = s.db.ExecTx(r.Context(), func(q *db.Queries) error {
+		if _, err := q.UpdatePost(r.Context(), params); err != nil {
+			return err
+		}
+		if changeStatus {
+			_, err := changePostStatus(r.Context(), q, existingPost, authUserID, newStatus, "")
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows):
+			respondWithError(w, http.StatusPreconditionFailed, errResourceModified)
+		case isVolunteerCapacityViolation(err):
+			respondWithError(w, http.StatusConflict, errVolunteerCapacityBelowApproved)
+		case isStatusChangeRefusal(err):
+			respondWithStatusChangeError(w, err, postID)
+		default:
+			slog.Error("Failed to update post", "error", err, "postID", postID)
+			respondWithError(w, http.StatusInternalServerError, "Failed to update post: "+err.Error())
+		}
+		return
 	}
-	respondWithJSON(w, http.StatusOK, dtos)
+	s.respondWithPost(w, r, postID)
 }
 
 // handleDeletePostVolunteer removes a volunteer from a post.
@@ -793,6 +915,7 @@ func (s *Server) handleDeletePostVolunteer(w http.ResponseWriter, r *http.Reques
 // @Failure 401 {object} ErrorResponse "Authentication required"
 // @Failure 403 {object} ErrorResponse "Forbidden - not authorized to approve volunteers for this post"
 // @Failure 404 {object} ErrorResponse "Post or volunteer application not found"
+// @Failure 409 {object} ErrorResponse "The application is not pending, or the post has no free volunteer places"
 // @Failure 500 {object} ErrorResponse "Failed to approve volunteer"
 // @Security BearerAuth
 // @Router /approve_volunteer [post]
@@ -838,20 +961,20 @@ func (s *Server) handleApproveVolunteer(w http.ResponseWriter, r *http.Request)
 
 	approvedVolunteer, err := s.db.ApproveVolunteer(r.Context(), params)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+			s.respondVolunteerTransitionRefused(w, r, params.PostID, params.UserID, "approve")
+			return
+		}
+		if isVolunteerCapacityViolation(err) {
+			respondWithError(w, http.StatusConflict, errNoVolunteerPlaces)
+			return
+		}
 		slog.Error("Failed to approve volunteer", "error", err, "postID", req.PostID, "volunteerID", req.VolunteerUserID)
 		respondWithError(w, http.StatusInternalServerError, "Failed to approve volunteer: "+err.Error())
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, PostVolunteerDTO{
-		ID:        approvedVolunteer.ID.Bytes,
-		UserID:    approvedVolunteer.UserID.Bytes,
-		PostID:    approvedVolunteer.PostID.Bytes,
-		Status:    approvedVolunteer.Status.String,
-		Notes:     approvedVolunteer.Notes.String,
-		CreatedAt: approvedVolunteer.CreatedAt.Time,
-		UpdatedAt: approvedVolunteer.UpdatedAt.Time,
-	})
+	respondWithJSON(w, http.StatusOK, toPostVolunteerDTO(approvedVolunteer))
 }
 
 // handleRejectVolunteer rejects a volunteer for a post.
@@ -866,6 +989,7 @@ func (s *Server) handleApproveVolunteer(w http.ResponseWriter, r *http.Request)
 // @Failure 401 {object} ErrorResponse "Authentication required"
 // @Failure 403 {object} ErrorResponse "Forbidden - not authorized to reject volunteers for this post"
 // @Failure 404 {object} ErrorResponse "Post or volunteer application not found"
+// @Failure 409 {object} ErrorResponse "The application is not pending"
 // @Failure 500 {object} ErrorResponse "Failed to reject volunteer"
 // @Security BearerAuth
 // @Router /reject_volunteer [post]
@@ -911,25 +1035,21 @@ func (s *Server) handleRejectVolunteer(w http.ResponseWriter, r *http.Request) {
 
 	rejectedVolunteer, err := s.db.RejectVolunteer(r.Context(), params)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+			s.respondVolunteerTransitionRefused(w, r, params.PostID, params.UserID, "reject")
+			return
+		}
 		slog.Error("Failed to reject volunteer", "error", err, "postID", req.PostID, "volunteerID", req.VolunteerUserID)
 		respondWithError(w, http.StatusInternalServerError, "Failed to reject volunteer: "+err.Error())
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, PostVolunteerDTO{
-		ID:        rejectedVolunteer.ID.Bytes,
-		UserID:    rejectedVolunteer.UserID.Bytes,
-		PostID:    rejectedVolunteer.PostID.Bytes,
-		Status:    rejectedVolunteer.Status.String,
-		Notes:     rejectedVolunteer.Notes.String,
-		CreatedAt: rejectedVolunteer.CreatedAt.Time,
-		UpdatedAt: rejectedVolunteer.UpdatedAt.Time,
-	})
+	respondWithJSON(w, http.StatusOK, toPostVolunteerDTO(rejectedVolunteer))
 }
 
 // handleGetUserStats retrieves statistics for a user.
 // @Summary Get user statistics
-// @Description Retrieves statistics for a specific user, including post count, volunteer count, and approved post count.
+// @Description Retrieves statistics for a specific user, including post count, volunteer count, approved post count and hours volunteered on shifts.
 // @Tags Users, Stats
 // @Produce json
 // @Param userId path string true "User ID" format(uuid)
@@ -956,10 +1076,6 @@ func (s *Server) handleGetUserStats(w http.ResponseWriter, r *http.Request) {
 	respondWithJSON(w, http.StatusOK, stats)
 }
 
-func toPgtypeFloat8(val float64) pgtype.Float8 {
-	return pgtype.Float8{Float64: val, Valid: true}
-}
-
 // handleGetCategoryName retrieves name for a category.
 // @Summary Get category name
 // @Description Retrieves category name.
@@ -1011,3 +1127,56 @@ func (s *Server) handleGetCategories(w http.ResponseWriter, r *http.Request) {
 
 	respondWithJSON(w, http.StatusOK, res)
 }
+
+// handleCreateCategory creates a new post category.
+// @Summary Create a category
+// @Description Creates a new post category. Requires the categories:write permission.
+// @Tags Categories
+// @Accept json
+// @Produce json
+// @Param category body CreateCategoryRequest true "Category details"
+// @Success 201 {object} CategoryDTO "Successfully created category"
+// @Failure 400 {object} ErrorResponse "Invalid request payload or missing fields"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Missing permission"
+// @Failure 409 {object} ErrorResponse "Category with this name or endpoint already exists"
+// @Failure 500 {object} ErrorResponse "Failed to create category"
+// @Security BearerAuth
+// @Router /categories [post]
+func (s *Server) handleCreateCategory(w http.ResponseWriter, r *http.Request) {
+	var req CreateCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload: "+err.Error())
+		return
+	}
+	defer r.Body.Close()
+
+	if req.Name == "" || req.Endpoint == "" {
+		respondWithError(w, http.StatusBadRequest, "Missing required fields: name, endpoint")
+		return
+	}
+
+	category, err := s.db.CreateCategory(r.Context(), db.CreateCategoryParams{
+		Name:         req.Name,
+		Description:  toPgtypeText(req.Description),
+		Endpoint:     req.Endpoint,
+		CanVolunteer: pgtype.Bool{Bool: req.CanVolunteer, Valid: true},
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			respondWithError(w, http.StatusConflict, "Category with this name or endpoint already exists")
+			return
+		}
+		slog.Error("Failed to create category", "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to create category")
+		return
+	}
+
+	dto, err := toCategoryDTO(category)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to process category data")
+		return
+	}
+	respondWithJSON(w, http.StatusCreated, dto)
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
@@ -8,12 +9,16 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/dukunuu/hackathon_backend/db" // ADJUST THIS IMPORT PATH
+	"github.com/dukunuu/hackathon_backend/password"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const maxUploadSize = 5 * 1024 * 1024 // 5 MB
@@ -32,7 +37,6 @@ type CreateUserRequest struct {
 	Phone          string `json:"phone,omitempty" example:"123-456-7890"`
 	IsVolunteering bool   `json:"is_volunteering" example:"false"`
 	Email          string `json:"email" example:"john.doe@example.com"`
-	Role           string `json:"role" example:"user"`
 	ProfileUrl     string `json:"profile_url,omitempty" example:"http://example.com/profile.jpg"`
 	Password       string `json:"password" example:"strongpassword123"`
 }
@@ -42,6 +46,8 @@ type CreateUserRequest struct {
 type LoginRequestPayload struct {
 	Email    string `json:"email" example:"john.doe@example.com"`
 	Password string `json:"password" example:"strongpassword123"`
+	// Restores an account scheduled for deletion instead of rejecting the login
+	RestoreAccount bool `json:"restore_account,omitempty" example:"false"`
 }
 
 // UpdateUserDetailsRequest defines the expected JSON body for updating user details.
@@ -57,26 +63,38 @@ type UpdateUserDetailsRequest struct {
 // UpdateUserEmailRequest defines the expected JSON body for updating user email.
 // swagger:model UpdateUserEmailRequest
 type UpdateUserEmailRequest struct {
-	Email string `json:"email" example:"new.email@example.com"`
+	Email           string `json:"email" example:"new.email@example.com"`
+	CurrentPassword string `json:"current_password" example:"strongpassword123"`
 }
 
 // UpdateUserPasswordRequest defines the expected JSON body for updating user password.
 // swagger:model UpdateUserPasswordRequest
 type UpdateUserPasswordRequest struct {
-	NewPassword string `json:"new_password" example:"newStrongPassword456"`
+	CurrentPassword string `json:"current_password" example:"strongpassword123"`
+	NewPassword     string `json:"new_password" example:"newStrongPassword456"`
+}
+
+// UpdateUserRoleRequest defines the expected JSON body for promoting or demoting a user.
+// swagger:model UpdateUserRoleRequest
+type UpdateUserRoleRequest struct {
+	Role string `json:"role" example:"MODERATOR"`
 }
 
 // handleCreateUser creates a new user, optionally with a profile picture.
 // @Summary Create a new user (with optional profile image)
 // @Description Registers a new user. User details are sent as a JSON string in the 'userData' form field.
+// @Description Self-registered accounts always get the USER role; any 'role' in userData is ignored.
+// @Description A verification link is emailed to the address. Creating posts and volunteering require a verified email,
+// @Description so 'is_volunteering' is ignored at registration.
 // @Description Optionally, a profile image can be uploaded via the 'profileImage' form field.
 // @Tags Users
 // @Accept multipart/form-data
 // @Produce json
-// @Param userData formData string true "User registration details as a JSON string. Example: '{\"first_name\":\"John\", \"last_name\":\"Doe\", \"email\":\"john.doe@example.com\", \"password\":\"secure123\", \"role\":\"user\"}'"
+// @Param userData formData string true "User registration details as a JSON string. Example: '{\"first_name\":\"John\", \"last_name\":\"Doe\", \"email\":\"john.doe@example.com\", \"password\":\"correct-horse-battery\"}'"
 // @Param profileImage formData file false "Optional profile image file (max 5MB, types: jpeg, png, gif, webp)"
 // @Success 201 {object} UserResponseDTO "Successfully created user"
 // @Failure 400 {object} ErrorResponse "Invalid request (e.g., missing 'userData', invalid JSON, invalid image, missing required fields in userData)"
+// @Failure 422 {object} PasswordPolicyErrorResponse "Password does not meet the password policy"
 // @Failure 409 {object} ErrorResponse "User with this email already exists"
 // @Failure 500 {object} ErrorResponse "Internal server error (e.g., failed to hash password, upload image, or create user)"
 // @Router /users/register [post]
@@ -101,8 +119,12 @@ func (s *Server) handleCreateUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Validate required fields from the parsed JSON
-	if req.Email == "" || req.Password == "" || req.FirstName == "" || req.LastName == "" || req.Role == "" {
-		respondWithError(w, http.StatusBadRequest, "Missing required fields in 'userData': email, password, first_name, last_name, role")
+	if req.Email == "" || req.Password == "" || req.FirstName == "" || req.LastName == "" {
+		respondWithError(w, http.StatusBadRequest, "Missing required fields in 'userData': email, password, first_name, last_name")
+		return
+	}
+
+	if !checkPasswordPolicy(w, req.Password, password.UserInfo{Email: req.Email, FirstName: req.FirstName, LastName: req.LastName}) {
 		return
 	}
 
@@ -114,7 +136,7 @@ func (s *Server) handleCreateUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// 3. Handle optional profile image upload
-	var uploadedProfileURL string
+	var uploadedProfileURL, uploadedProfileObject string
 	file, handler, err := r.FormFile("profileImage")
 	if err != nil && !errors.Is(err, http.ErrMissingFile) {
 		// An error other than "missing file" occurred
@@ -160,13 +182,14 @@ func (s *Server) handleCreateUser(w http.ResponseWriter, r *http.Request) {
 		}
 
 		tempUserIDForPath := uuid.New() // Generate a UUID to use in the path for now
-		uploadedURL, _, uploadErr := s.filestore.UploadProfileImage(r.Context(), fileBytes, handler.Filename, contentType, tempUserIDForPath)
+		uploadedURL, objectName, uploadErr := s.filestore.UploadProfileImage(r.Context(), fileBytes, handler.Filename, contentType, tempUserIDForPath)
 		if uploadErr != nil {
 			slog.Error("Failed to upload profile image via filestore during user creation", "error", uploadErr)
 			respondWithError(w, http.StatusInternalServerError, "Could not upload profile image: "+uploadErr.Error())
 			return
 		}
 		uploadedProfileURL = uploadedURL
+		uploadedProfileObject = objectName
 		slog.Info("Successfully uploaded profile image during user creation", "tempUserIDForPath", tempUserIDForPath, "url", uploadedProfileURL)
 	}
 
@@ -175,15 +198,13 @@ func (s *Server) handleCreateUser(w http.ResponseWriter, r *http.Request) {
 		finalProfileURL = req.ProfileUrl
 	}
 
-	var roleValueForDB = req.Role
-
 	params := db.CreateUserParams{
 		FirstName:      req.FirstName,
 		LastName:       req.LastName,
 		Phone:          toPgtypeText(req.Phone),
-		IsVolunteering: req.IsVolunteering,
+		IsVolunteering: false, // Volunteering can only be turned on once the email is verified
 		Email:          req.Email,
-		Role:           db.UserRole(roleValueForDB),
+		Role:           db.UserRoleUSER, // Elevated roles are only granted by an administrator
 		ProfileUrl:     toPgtypeText(finalProfileURL),
 		PasswordHash:   hashedPassword,
 	}
@@ -191,6 +212,10 @@ func (s *Server) handleCreateUser(w http.ResponseWriter, r *http.Request) {
 	// 5. Create user in database
 	user, err := s.db.CreateUser(r.Context(), params)
 	if err != nil {
+		// The uploaded image belongs to no one now.
+		if uploadedProfileObject != "" {
+			s.removeUploadedObjects(r.Context(), []string{uploadedProfileObject})
+		}
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // Unique violation
 			respondWithError(w, http.StatusConflict, "User with this email already exists")
@@ -201,19 +226,34 @@ func (s *Server) handleCreateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The account is usable without a verified address, so a mail failure
+	// must not fail registration; the user can request a new link.
+	if err := s.sendVerificationEmail(r.Context(), user); err != nil {
+		slog.Error("Failed to send verification email", "error", err, "userID", user.ID)
+	}
+
 	respondWithJSON(w, http.StatusCreated, ToUserResponseDTO(user))
 }
 
 // handleLogin authenticates a user and returns a JWT.
 // @Summary Login a user
-// @Description Authenticates a user with email and password, returns a JWT token and user details.
+// @Description Authenticates a user with email and password, returns a short-lived JWT access token, a refresh token and user details.
+// @Description Repeated failures for an email or client address are answered with exponential back-off, and enough
+// @Description consecutive failures lock the account temporarily. Both are reported as 429 with a Retry-After header.
+// @Description Accounts with two-factor authentication get a 202 with a short-lived 'mfa_token' instead of a session.
+// @Description An account scheduled for deletion is only restored once the second factor has been checked too.
 // @Tags Authentication
 // @Accept json
 // @Produce json
 // @Param credentials body LoginRequestPayload true "User login credentials"
 // @Success 200 {object} LoginResponsePayloadDTO "Successfully logged in"
+// @Success 202 {object} MFAChallengeResponseDTO "Password accepted; complete the login at POST /auth/mfa/verify"
 // @Failure 400 {object} ErrorResponse "Invalid request payload or missing fields"
 // @Failure 401 {object} ErrorResponse "Invalid email or password"
+// @Failure 409 {object} ErrorResponse "Account is scheduled for deletion and restore_account was not set"
+// @Failure 410 {object} ErrorResponse "Account has been deleted"
+// @Failure 429 {object} ErrorResponse "Too many failed login attempts"
+// @Header 429 {integer} Retry-After "Seconds until the next attempt will be evaluated"
 // @Failure 500 {object} ErrorResponse "Login failed or failed to generate token"
 // @Router /users/login [post]
 func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
@@ -229,9 +269,25 @@ func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	wait, err := s.loginBlockedFor(r.Context(), emailThrottleKey(req.Email), ipThrottleKey(clientIP(r)))
+	if err != nil {
+		slog.Error("Failed to check login throttles", "error", err, "email", req.Email)
+		respondWithError(w, http.StatusInternalServerError, "Login failed")
+		return
+	}
+	if wait > 0 {
+		respondTooManyAttempts(w, wait)
+		return
+	}
+
 	loginRow, err := s.db.LoginRequest(r.Context(), req.Email)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			// Unknown emails are throttled too, so they look like wrong passwords.
+			if wait := s.recordLoginFailure(r.Context(), r, req.Email, pgtype.UUID{}); wait > 0 {
+				respondTooManyAttempts(w, wait)
+				return
+			}
 			respondWithError(w, http.StatusUnauthorized, "Invalid email or password")
 			return
 		}
@@ -240,11 +296,63 @@ func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	wait, err = s.accountLockedFor(r.Context(), loginRow.ID)
+	if err != nil {
+		slog.Error("Failed to check account lockout", "error", err, "userID", loginRow.ID)
+		respondWithError(w, http.StatusInternalServerError, "Login failed")
+		return
+	}
+	if wait > 0 {
+		respondTooManyAttempts(w, wait)
+		return
+	}
+
 	if err := verifyPassword(loginRow.PasswordHash, req.Password); err != nil {
+		if wait := s.recordLoginFailure(r.Context(), r, req.Email, loginRow.ID); wait > 0 {
+			respondTooManyAttempts(w, wait)
+			return
+		}
 		respondWithError(w, http.StatusUnauthorized, "Invalid email or password")
 		return
 	}
 
+	// Only the account's counter is cleared; the address keeps its history
+	// so one valid account cannot be used to reset back-off for others.
+	if err := s.db.ResetLoginThrottle(r.Context(), emailThrottleKey(req.Email)); err != nil {
+		slog.Error("Failed to reset login throttle", "error", err, "userID", loginRow.ID)
+	}
+
+	// The account is restored once every factor has been checked: here for
+	// password-only accounts, in handleVerifyMFA for the others.
+	if loginRow.DeletedAt.Valid && !req.RestoreAccount {
+		respondWithError(w, http.StatusConflict, "Account is scheduled for deletion. Log in with restore_account set to restore it.")
+		return
+	}
+
+	mfaEnabled, err := s.mfaEnabled(r.Context(), loginRow.ID)
+	if err != nil {
+		slog.Error("Failed to check MFA enrollment", "error", err, "userID", loginRow.ID)
+		respondWithError(w, http.StatusInternalServerError, "Login failed")
+		return
+	}
+	if mfaEnabled {
+		expiresAt := time.Now().Add(mfaPendingTokenTTL)
+		mfaToken, err := generateMFAPendingJWT(loginRow.ID, loginRow.Email, s.signingKeys, expiresAt)
+		if err != nil {
+			slog.Error("Failed to generate MFA token", "error", err, "userID", loginRow.ID)
+			respondWithError(w, http.StatusInternalServerError, "Failed to generate token")
+			return
+		}
+		respondWithJSON(w, http.StatusAccepted, MFAChallengeResponseDTO{MFARequired: true, MFAToken: mfaToken, ExpiresAt: expiresAt})
+		return
+	}
+
+	if loginRow.DeletedAt.Valid {
+		if _, ok := s.restoreAccountOnLogin(w, r, loginRow.ID); !ok {
+			return
+		}
+	}
+
 	fullUser, err := s.db.GetUserByID(r.Context(), loginRow.ID)
 	if err != nil {
 		slog.Error("Failed to fetch full user details post-login", "error", err, "userID", loginRow.ID)
@@ -252,23 +360,23 @@ func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	expirationTime := time.Now().Add(24 * time.Hour)
-	tokenString, err := generateJWT(loginRow.ID, loginRow.Email, loginRow.Role, s.jwtSecret, expirationTime)
+	tokens, err := s.issueSession(r.Context(), r, loginRow.ID, loginRow.Email, loginRow.Role, false)
 	if err != nil {
-		slog.Error("Failed to generate token", "error", err)
+		slog.Error("Failed to start session", "error", err, "userID", loginRow.ID)
 		respondWithError(w, http.StatusInternalServerError, "Failed to generate token")
 		return
 	}
-	respondWithJSON(w, http.StatusOK, ToLoginResponsePayloadDTO(tokenString, fullUser))
+	respondWithJSON(w, http.StatusOK, ToLoginResponsePayloadDTO(tokens, fullUser))
 }
 
 // handleGetUserByID fetches a user by their ID.
 // @Summary Get user by ID
-// @Description Retrieves user details for a given user ID.
+// @Description Retrieves the public profile of a user. Contact details are included as the user's privacy settings allow.
+// @Description The user themselves and callers with the 'users:manage' permission receive the full UserResponse instead.
 // @Tags Users
 // @Produce json
 // @Param userID path string true "User ID" format(uuid)
-// @Success 200 {object} UserResponseDTO "Successfully retrieved user"
+// @Success 200 {object} PublicUserProfileDTO "Successfully retrieved user"
 // @Failure 400 {object} ErrorResponse "Invalid user ID format"
 // @Failure 401 {object} ErrorResponse "Unauthorized"
 // @Failure 404 {object} ErrorResponse "User not found"
@@ -292,7 +400,22 @@ func (s *Server) handleGetUserByID(w http.ResponseWriter, r *http.Request) {
 		respondWithError(w, http.StatusInternalServerError, "Failed to get user: "+err.Error())
 		return
 	}
-	respondWithJSON(w, http.StatusOK, ToUserResponseDTO(user))
+	if user.DeletedAt.Valid && !hasPermission(r.Context(), PermUsersManage) {
+		respondWithError(w, http.StatusNotFound, "User not found")
+		return
+	}
+	if canViewFullUser(r.Context(), user.ID) {
+		respondWithJSON(w, http.StatusOK, ToUserResponseDTO(user))
+		return
+	}
+
+	profiles, err := s.publicProfiles(r.Context(), []db.User{user})
+	if err != nil {
+		slog.Error("Failed to build public profile", "error", err, "userID", userID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to get user")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, profiles[0])
 }
 
 // handleGetCurrentUser fetches the currently authenticated user's details.
@@ -300,7 +423,10 @@ func (s *Server) handleGetUserByID(w http.ResponseWriter, r *http.Request) {
 // @Description Retrieves details for the currently authenticated user.
 // @Tags Users
 // @Produce json
+// @Param If-None-Match header string false "ETag from an earlier response; a 304 is returned while it is current"
 // @Success 200 {object} UserResponseDTO "Successfully retrieved user"
+// @Header 200 {string} ETag "Version of the user, for If-Match and If-None-Match"
+// @Success 304 "User has not changed"
 // @Failure 401 {object} ErrorResponse "Authentication required or user not found"
 // @Failure 500 {object} ErrorResponse "Failed to get user"
 // @Security BearerAuth
@@ -323,36 +449,86 @@ func (s *Server) handleGetCurrentUser(w http.ResponseWriter, r *http.Request) {
 		respondWithError(w, http.StatusInternalServerError, "Failed to get user: "+err.Error())
 		return
 	}
-	respondWithJSON(w, http.StatusOK, ToUserResponseDTO(user))
+	respondWithTaggedJSON(w, r, http.StatusOK, versionETag(user.UpdatedAt), ToUserResponseDTO(user))
 }
 
-// handleListUsers lists all users.
-// @Summary List all users
-// @Description Retrieves a list of all users. (Note: Consider pagination for large datasets and admin-only access)
+// handleListUsers lists users one page at a time.
+// @Summary List users
+// @Description Retrieves the public profiles of users, newest first. Callers with the 'users:manage' permission receive full UserResponse records instead.
+// @Description Follow 'next_cursor' until it is omitted to read every page.
 // @Tags Users
 // @Produce json
-// @Success 200 {array} UserResponseDTO "Successfully retrieved list of users"
+// @Param role query string false "Only users with this role" Enums(ADMIN, MODERATOR, USER)
+// @Param is_volunteering query bool false "Only users who are (or are not) volunteering"
+// @Param sort query string false "created_at (oldest first) or -created_at (newest first, default)" Enums(created_at, -created_at)
+// @Param limit query int false "Page size, 1-100 (default 20)"
+// @Param cursor query string false "next_cursor from the previous page"
+// @Success 200 {object} PageDTO[PublicUserProfileDTO] "One page of users"
+// @Failure 400 {object} ErrorResponse "Invalid filter or cursor"
 // @Failure 401 {object} ErrorResponse "Unauthorized"
 // @Failure 500 {object} ErrorResponse "Failed to list users"
 // @Security BearerAuth
 // @Router /users [get]
 func (s *Server) handleListUsers(w http.ResponseWriter, r *http.Request) {
-	users, err := s.db.ListUsers(r.Context())
+	page, err := parsePageRequest(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	params := db.ListUsersParams{
+		SortAsc:         page.SortAsc,
+		CursorCreatedAt: page.cursorCreatedAt(),
+		CursorID:        page.cursorID(),
+		PageSize:        page.fetchSize(),
+	}
+	if v := r.URL.Query().Get("role"); v != "" {
+		if !isValidRole(db.UserRole(v)) {
+			respondWithError(w, http.StatusBadRequest, "Invalid role '"+v+"'")
+			return
+		}
+		params.Role = db.NullUserRole{UserRole: db.UserRole(v), Valid: true}
+	}
+	if v := r.URL.Query().Get("is_volunteering"); v != "" {
+		volunteering, err := strconv.ParseBool(v)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "is_volunteering must be true or false")
+			return
+		}
+		params.IsVolunteering = pgtype.Bool{Bool: volunteering, Valid: true}
+	}
+
+	users, err := s.db.ListUsers(r.Context(), params)
 	if err != nil {
 		slog.Error("Failed to list users", "error", err)
 		respondWithError(w, http.StatusInternalServerError, "Failed to list users: "+err.Error())
 		return
 	}
-	respondWithJSON(w, http.StatusOK, ToUserResponseDTOs(users))
+	users, nextCursor := trimPage(page, users, func(u db.User) (pgtype.Timestamptz, pgtype.UUID) {
+		return u.CreatedAt, u.ID
+	})
+	if hasPermission(r.Context(), PermUsersManage) {
+		respondWithJSON(w, http.StatusOK, PageDTO[UserResponseDTO]{Items: ToUserResponseDTOs(users), NextCursor: nextCursor})
+		return
+	}
+
+	profiles, err := s.publicProfiles(r.Context(), users)
+	if err != nil {
+		slog.Error("Failed to build public profiles", "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to list users")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, PageDTO[PublicUserProfileDTO]{Items: profiles, NextCursor: nextCursor})
 }
 
-// handleDeleteUser deletes a user by their ID.
+// handleDeleteUser schedules a user account for deletion.
 // @Summary Delete user by ID
-// @Description Deletes a user account. Authenticated user can delete their own account. (Admin functionality can be added).
+// @Description Schedules a user account for deletion. Authenticated users can delete their own account; administrators (users:manage) can delete any account.
+// @Description The account is signed out everywhere and can be restored until purge_after, after which its profile, images and volunteer records are removed for good.
+// @Description Posts authored by the account are kept without an author.
 // @Tags Users
 // @Produce json
 // @Param userID path string true "User ID of the account to delete" format(uuid)
-// @Success 200 {object} map[string]string "message: User deleted successfully"
+// @Success 200 {object} AccountDeletionResponseDTO "Account scheduled for deletion"
 // @Failure 400 {object} ErrorResponse "Invalid user ID format"
 // @Failure 401 {object} ErrorResponse "Authentication required"
 // @Failure 403 {object} ErrorResponse "Forbidden - cannot delete another user's account"
@@ -373,45 +549,58 @@ func (s *Server) handleDeleteUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var authUUIDBytes = authUserID.Bytes
-	var targetUUIDBytes = userIDToDelete.Bytes
-
-	if authUUIDBytes != targetUUIDBytes {
+	if authUserID.Bytes != userIDToDelete.Bytes && !hasPermission(r.Context(), PermUsersManage) {
 		respondWithError(w, http.StatusForbidden, "You can only delete your own account or you lack admin privileges.")
 		return
 	}
+	if _, viaAPIKey := getAPIKeyScopesFromContext(r.Context()); viaAPIKey && authUserID.Bytes == userIDToDelete.Bytes {
+		respondWithError(w, http.StatusForbidden, "Deleting your own account requires a logged-in session and cannot be done with an API key")
+		return
+	}
 
-	_, err = s.db.GetUserByID(r.Context(), userIDToDelete)
+	user, err := s.db.SoftDeleteUser(r.Context(), userIDToDelete)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
 			respondWithError(w, http.StatusNotFound, "User not found to delete")
 			return
 		}
-		slog.Error("Error checking user before delete", "error", err, "userIDToDelete", userIDToDelete)
-		respondWithError(w, http.StatusInternalServerError, "Error checking user before deletion: "+err.Error())
-		return
-	}
-
-	err = s.db.DeleteUser(r.Context(), userIDToDelete)
-	if err != nil {
 		slog.Error("Failed to delete user", "error", err, "userIDToDelete", userIDToDelete)
 		respondWithError(w, http.StatusInternalServerError, "Failed to delete user: "+err.Error())
 		return
 	}
-	respondWithJSON(w, http.StatusOK, map[string]string{"message": "User deleted successfully"})
+
+	if err := s.db.RevokeUserSessions(r.Context(), user.ID); err != nil {
+		slog.Error("Failed to revoke sessions of deleted user", "error", err, "userID", user.ID)
+	}
+	if err := s.db.RevokeUserAPIKeys(r.Context(), user.ID); err != nil {
+		slog.Error("Failed to revoke API keys of deleted user", "error", err, "userID", user.ID)
+	}
+
+	slog.Info("Account scheduled for deletion", "userID", user.ID, "deletedBy", authUserID)
+	respondWithJSON(w, http.StatusOK, AccountDeletionResponseDTO{
+		Message:    "Account scheduled for deletion",
+		PurgeAfter: s.purgeAfter(user),
+	})
 }
 
 // handleUpdateUserDetails updates details for the authenticated user.
 // @Summary Update current user's details
 // @Description Updates the first name, last name, phone, volunteering status, and profile URL for the authenticated user.
+// @Description Turning volunteering on requires a verified email address.
+// @Description The If-Match header must carry the user's current ETag from GET /users/me.
 // @Tags Users
 // @Accept json
 // @Produce json
+// @Param If-Match header string true "ETag of the user from GET /users/me"
 // @Param userDetails body UpdateUserDetailsRequest true "User details to update"
 // @Success 200 {object} UserResponseDTO "Successfully updated user details"
+// @Header 200 {string} ETag "New version of the user"
 // @Failure 400 {object} ErrorResponse "Invalid request payload"
 // @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Email address not verified"
 // @Failure 404 {object} ErrorResponse "User not found to update"
+// @Failure 412 {object} ErrorResponse "User was modified since the ETag was issued"
+// @Failure 428 {object} ErrorResponse "If-Match header missing"
 // @Failure 500 {object} ErrorResponse "Failed to update user details"
 // @Security BearerAuth
 // @Router /users/me/details [put]
@@ -429,6 +618,24 @@ func (s *Server) handleUpdateUserDetails(w http.ResponseWriter, r *http.Request)
 	}
 	defer r.Body.Close()
 
+	currentUser, err := s.db.GetUserByID(r.Context(), targetUserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "User not found to update")
+			return
+		}
+		slog.Error("Failed to fetch user for details update", "error", err, "userID", targetUserID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to update user details")
+		return
+	}
+	if !checkIfMatch(w, r, versionETag(currentUser.UpdatedAt)) {
+		return
+	}
+	if req.IsVolunteering && !currentUser.EmailVerifiedAt.Valid {
+		respondWithError(w, http.StatusForbidden, "Please verify your email address before volunteering")
+		return
+	}
+
 	params := db.UpdateUserDetailsParams{
 		ID:             targetUserID,
 		FirstName:      req.FirstName,
@@ -436,31 +643,34 @@ func (s *Server) handleUpdateUserDetails(w http.ResponseWriter, r *http.Request)
 		Phone:          toPgtypeText(req.Phone),
 		IsVolunteering: req.IsVolunteering,
 		ProfileUrl:     toPgtypeText(req.ProfileUrl),
+		UpdatedAt:      currentUser.UpdatedAt,
 	}
 
 	updatedUser, err := s.db.UpdateUserDetails(r.Context(), params)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
-			respondWithError(w, http.StatusNotFound, "User not found to update")
+			respondWithError(w, http.StatusPreconditionFailed, errResourceModified)
 			return
 		}
 		slog.Error("Failed to update user details", "error", err, "userID", targetUserID)
 		respondWithError(w, http.StatusInternalServerError, "Failed to update user details: "+err.Error())
 		return
 	}
-	respondWithJSON(w, http.StatusOK, ToUserResponseDTO(updatedUser))
+	respondWithTaggedJSON(w, r, http.StatusOK, versionETag(updatedUser.UpdatedAt), ToUserResponseDTO(updatedUser))
 }
 
-// handleUpdateUserEmail updates the email for the authenticated user.
+// handleUpdateUserEmail starts an email change for the authenticated user.
 // @Summary Update current user's email
-// @Description Updates the email address for the authenticated user. May require re-verification in a real application.
+// @Description Emails a confirmation link to the new address. The email is only changed once the link is redeemed
+// @Description through POST /auth/confirm-email-change, which also revokes all sessions of the user.
 // @Tags Users
 // @Accept json
 // @Produce json
-// @Param emailUpdate body UpdateUserEmailRequest true "New email address"
-// @Success 200 {object} UserResponseDTO "Successfully updated user email"
-// @Failure 400 {object} ErrorResponse "Invalid request payload or email empty"
+// @Param emailUpdate body UpdateUserEmailRequest true "New email address and current password"
+// @Success 202 {object} map[string]string "message: Confirmation email sent to the new address"
+// @Failure 400 {object} ErrorResponse "Invalid request payload, email or current password empty, or email unchanged"
 // @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Current password is incorrect"
 // @Failure 404 {object} ErrorResponse "User not found to update email"
 // @Failure 409 {object} ErrorResponse "This email is already in use"
 // @Failure 500 {object} ErrorResponse "Failed to update user email"
@@ -484,41 +694,61 @@ func (s *Server) handleUpdateUserEmail(w http.ResponseWriter, r *http.Request) {
 		respondWithError(w, http.StatusBadRequest, "Email cannot be empty")
 		return
 	}
-
-	params := db.UpdateUserEmailParams{
-		ID:    targetUserID,
-		Email: req.Email,
+	if req.CurrentPassword == "" {
+		respondWithError(w, http.StatusBadRequest, "Current password is required")
+		return
 	}
 
-	updatedUser, err := s.db.UpdateUserEmail(r.Context(), params)
+	user, err := s.db.GetUserByID(r.Context(), targetUserID)
 	if err != nil {
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
-			respondWithError(w, http.StatusConflict, "This email is already in use.")
-			return
-		}
 		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
 			respondWithError(w, http.StatusNotFound, "User not found to update email")
 			return
 		}
-		slog.Error("Failed to update user email", "error", err, "userID", targetUserID)
-		respondWithError(w, http.StatusInternalServerError, "Failed to update user email: "+err.Error())
+		slog.Error("Failed to fetch user for email update", "error", err, "userID", targetUserID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to update user email")
 		return
 	}
-	respondWithJSON(w, http.StatusOK, ToUserResponseDTO(updatedUser))
+	if err := verifyPassword(user.PasswordHash, req.CurrentPassword); err != nil {
+		respondWithError(w, http.StatusForbidden, "Current password is incorrect")
+		return
+	}
+	if strings.EqualFold(user.Email, req.Email) {
+		respondWithError(w, http.StatusBadRequest, "New email is the same as the current one")
+		return
+	}
+
+	if _, err := s.db.GetUserByEmail(r.Context(), req.Email); err == nil {
+		respondWithError(w, http.StatusConflict, "This email is already in use.")
+		return
+	} else if !errors.Is(err, sql.ErrNoRows) && !errors.Is(err, pgx.ErrNoRows) {
+		slog.Error("Failed to check email availability", "error", err, "userID", targetUserID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to update user email")
+		return
+	}
+
+	if err := s.sendEmailChangeConfirmation(r.Context(), user, req.Email); err != nil {
+		slog.Error("Failed to send email change confirmation", "error", err, "userID", targetUserID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to send confirmation email")
+		return
+	}
+	respondWithJSON(w, http.StatusAccepted, map[string]string{"message": "Confirmation email sent to the new address"})
 }
 
 // handleUpdateUserPassword updates the password for the authenticated user.
 // @Summary Update current user's password
-// @Description Updates the password for the authenticated user.
+// @Description Updates the password for the authenticated user after checking the current one.
+// @Description The new password must satisfy the password policy. All other sessions of the user are revoked.
 // @Tags Users
 // @Accept json
 // @Produce json
-// @Param passwordUpdate body UpdateUserPasswordRequest true "New password"
+// @Param passwordUpdate body UpdateUserPasswordRequest true "Current and new password"
 // @Success 200 {object} map[string]string "message: Password updated successfully"
-// @Failure 400 {object} ErrorResponse "Invalid request payload or new password empty"
+// @Failure 400 {object} ErrorResponse "Invalid request payload or current/new password empty"
 // @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Current password is incorrect"
 // @Failure 404 {object} ErrorResponse "User not found to update password"
+// @Failure 422 {object} PasswordPolicyErrorResponse "New password does not meet the password policy"
 // @Failure 500 {object} ErrorResponse "Failed to hash new password or update user password"
 // @Security BearerAuth
 // @Router /users/me/password [put]
@@ -536,11 +766,33 @@ func (s *Server) handleUpdateUserPassword(w http.ResponseWriter, r *http.Request
 	}
 	defer r.Body.Close()
 
+	if req.CurrentPassword == "" {
+		respondWithError(w, http.StatusBadRequest, "Current password is required")
+		return
+	}
 	if req.NewPassword == "" {
 		respondWithError(w, http.StatusBadRequest, "New password cannot be empty")
 		return
 	}
 
+	user, err := s.db.GetUserByID(r.Context(), targetUserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "User not found to update password")
+			return
+		}
+		slog.Error("Failed to fetch user for password update", "error", err, "userID", targetUserID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to update user password")
+		return
+	}
+	if err := verifyPassword(user.PasswordHash, req.CurrentPassword); err != nil {
+		respondWithError(w, http.StatusForbidden, "Current password is incorrect")
+		return
+	}
+	if !checkPasswordPolicy(w, req.NewPassword, password.UserInfo{Email: user.Email, FirstName: user.FirstName, LastName: user.LastName}) {
+		return
+	}
+
 	newHashedPassword, err := hashPassword(req.NewPassword)
 	if err != nil {
 		slog.Error("Failed to hash new password", "error", err)
@@ -563,18 +815,20 @@ func (s *Server) handleUpdateUserPassword(w http.ResponseWriter, r *http.Request
 		respondWithError(w, http.StatusInternalServerError, "Failed to update user password: "+err.Error())
 		return
 	}
+	s.revokeOtherSessions(r.Context(), targetUserID)
 	respondWithJSON(w, http.StatusOK, map[string]string{"message": "Password updated successfully"})
 }
 
 // handleGetUserByEmail fetches a user by their email address.
 // @Summary Get user by email
-// @Description Retrieves user details for a given email address. (Consider admin-only access).
+// @Description Retrieves user details for a given email address. Requires the 'users:manage' permission so addresses cannot be enumerated.
 // @Tags Users
 // @Produce json
 // @Param email query string true "Email address of the user" example("jane.doe@example.com")
 // @Success 200 {object} UserResponseDTO "Successfully retrieved user"
 // @Failure 400 {object} ErrorResponse "Email query parameter is required"
 // @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Missing permission"
 // @Failure 404 {object} ErrorResponse "User with this email not found"
 // @Failure 500 {object} ErrorResponse "Failed to get user by email"
 // @Security BearerAuth
@@ -599,3 +853,86 @@ func (s *Server) handleGetUserByEmail(w http.ResponseWriter, r *http.Request) {
 	respondWithJSON(w, http.StatusOK, ToUserResponseDTO(user))
 }
 
+// handleUpdateUserRole promotes or demotes a user.
+// @Summary Change a user's role
+// @Description Promotes or demotes a user to ADMIN, MODERATOR or USER. Requires the users:manage permission.
+// @Description Administrators cannot change their own role, so the last administrator cannot lock everyone out.
+// @Tags Users, Admin
+// @Accept json
+// @Produce json
+// @Param userID path string true "User ID" format(uuid)
+// @Param roleUpdate body UpdateUserRoleRequest true "New role"
+// @Success 200 {object} UserResponseDTO "Successfully updated user role"
+// @Failure 400 {object} ErrorResponse "Invalid user ID, payload or role"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Missing permission or attempt to change own role"
+// @Failure 404 {object} ErrorResponse "User not found"
+// @Failure 500 {object} ErrorResponse "Failed to update user role"
+// @Security BearerAuth
+// @Router /users/{userID}/role [put]
+func (s *Server) handleUpdateUserRole(w http.ResponseWriter, r *http.Request) {
+	targetUserID, err := parseUUIDFromParam(r, "userID")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	authUserID, err := getUserIDFromContext(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	if authUserID.Bytes == targetUserID.Bytes {
+		respondWithError(w, http.StatusForbidden, "You cannot change your own role")
+		return
+	}
+
+	var req UpdateUserRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	newRole := db.UserRole(strings.ToUpper(req.Role))
+	if !isValidRole(newRole) {
+		respondWithError(w, http.StatusBadRequest, "Invalid role. Allowed roles: ADMIN, MODERATOR, USER")
+		return
+	}
+
+	updatedUser, err := s.db.UpdateUserRole(r.Context(), db.UpdateUserRoleParams{
+		ID:   targetUserID,
+		Role: newRole,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "User not found")
+			return
+		}
+		slog.Error("Failed to update user role", "error", err, "userID", targetUserID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to update user role: "+err.Error())
+		return
+	}
+
+	slog.Info("User role changed", "userID", targetUserID, "role", newRole, "changedBy", authUserID)
+	respondWithJSON(w, http.StatusOK, ToUserResponseDTO(updatedUser))
+}
+
+// BootstrapAdmin promotes the user registered under email to ADMIN. It is
+// meant to be called once at startup so a fresh deployment has an
+// administrator who can promote everyone else through the API.
+func (s *Server) BootstrapAdmin(ctx context.Context, email string) error {
+	user, err := s.db.GetUserByEmail(ctx, email)
+	if err != nil {
+		return fmt.Errorf("failed to find bootstrap admin '%s': %w", email, err)
+	}
+	if user.Role == db.UserRoleADMIN {
+		return nil
+	}
+	if _, err := s.db.UpdateUserRole(ctx, db.UpdateUserRoleParams{ID: user.ID, Role: db.UserRoleADMIN}); err != nil {
+		return fmt.Errorf("failed to promote bootstrap admin '%s': %w", email, err)
+	}
+	slog.Info("Promoted bootstrap admin", "email", email)
+	return nil
+}
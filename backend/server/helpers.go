@@ -8,6 +8,8 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/dukunuu/hackathon_backend/jwtkeys"
+	"github.com/dukunuu/hackathon_backend/password"
 	"github.com/go-chi/chi/v5"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
@@ -18,7 +20,11 @@ import (
 type contextKey string
 
 const UserIDKey contextKey = "userID"
-const UserRoleKey contextKey = "userRole" // If you store role in JWT
+const UserRoleKey contextKey = "userRole" // Current db.UserRole, resolved by AuthMiddleware
+const SessionIDKey contextKey = "sessionID"
+const MFAVerifiedKey contextKey = "mfaVerified"   // Whether the session passed a second factor
+const MFARequiredKey contextKey = "mfaRequired"   // Set when policy demands 2FA the session lacks
+const APIKeyScopesKey contextKey = "apiKeyScopes" // Set when the request authenticated with an API key
 
 type ErrorResponse struct {
 	Error string `json:"error"`
@@ -28,6 +34,27 @@ func respondWithError(w http.ResponseWriter, code int, message string) {
 	respondWithJSON(w, code, ErrorResponse{Error: message})
 }
 
+// PasswordPolicyErrorResponse lists every password rule a request failed.
+// swagger:model PasswordPolicyErrorResponse
+type PasswordPolicyErrorResponse struct {
+	Error      string               `json:"error" example:"Password does not meet the password policy"`
+	Violations []password.Violation `json:"violations"`
+}
+
+// checkPasswordPolicy validates pw and, when it fails, writes a 422 response
+// listing the violations. It reports whether the password was accepted.
+func checkPasswordPolicy(w http.ResponseWriter, pw string, user password.UserInfo) bool {
+	violations := password.Validate(pw, user)
+	if len(violations) == 0 {
+		return true
+	}
+	respondWithJSON(w, http.StatusUnprocessableEntity, PasswordPolicyErrorResponse{
+		Error:      "Password does not meet the password policy",
+		Violations: violations,
+	})
+	return false
+}
+
 func respondWithJSON(w http.ResponseWriter, code int, payload any) {
 	response, err := json.Marshal(payload)
 	if err != nil {
@@ -49,33 +76,32 @@ func verifyPassword(hashedPassword, password string) error {
 }
 
 type Claims struct {
-	UserID pgtype.UUID `json:"user_id"`
-	Email  string      `json:"email"`
-	Role   any				 `json:"role,omitempty"` // Store role if needed
+	UserID    pgtype.UUID `json:"user_id"`
+	SessionID pgtype.UUID `json:"sid"` // Server-side session the token was issued for
+	Email     string      `json:"email"`
+	Role      any         `json:"role,omitempty"`    // Store role if needed
+	AMR       []string    `json:"amr,omitempty"`     // Authentication methods used, RFC 8176
+	Purpose   string      `json:"purpose,omitempty"` // Set on restricted tokens that are not access tokens
 	jwt.RegisteredClaims
 }
 
-func generateJWT(userID pgtype.UUID, email string, role any, secret string, expiresAt time.Time) (string, error) {
+func generateJWT(userID, sessionID pgtype.UUID, email string, role any, amr []string, keys *jwtkeys.KeySet, expiresAt time.Time) (string, error) {
 	claims := &Claims{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
+		UserID:    userID,
+		SessionID: sessionID,
+		Email:     email,
+		Role:      role,
+		AMR:       amr,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(secret))
+	return keys.Sign(claims)
 }
 
-func parseJWT(tokenString string, secret string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (any, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(secret), nil
-	})
+func parseJWT(tokenString string, keys *jwtkeys.KeySet) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, keys.Keyfunc, jwt.WithValidMethods(keys.Methods()))
 
 	if err != nil {
 		return nil, err
@@ -121,3 +147,14 @@ func getUserIDFromContext(ctx context.Context) (pgtype.UUID, error) {
 	return userID, nil
 }
 
+func getSessionIDFromContext(ctx context.Context) (pgtype.UUID, error) {
+	sessionIDVal := ctx.Value(SessionIDKey)
+	if sessionIDVal == nil {
+		return pgtype.UUID{}, errors.New("session ID not found in context")
+	}
+	sessionID, ok := sessionIDVal.(pgtype.UUID)
+	if !ok || !sessionID.Valid {
+		return pgtype.UUID{}, errors.New("session ID in context is invalid")
+	}
+	return sessionID, nil
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHashPassword(t *testing.T) {
+	tests := []struct {
+		name string
+		pw   string
+	}{
+		{name: "ascii", pw: "xk7#pq2m"},
+		{name: "multi-byte", pw: "хөх7#тэн"},
+		{name: "maximum length", pw: strings.Repeat("k", 72)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := hashPassword(tt.pw)
+			if err != nil {
+				t.Fatalf("hashPassword: %v", err)
+			}
+			if hash == tt.pw {
+				t.Fatal("hashPassword returned the password itself")
+			}
+			if err := verifyPassword(hash, tt.pw); err != nil {
+				t.Errorf("verifyPassword(correct) = %v, want nil", err)
+			}
+			if err := verifyPassword(hash, "X"+tt.pw[1:]); err == nil {
+				t.Error("verifyPassword(wrong) = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestHashPasswordIsSalted(t *testing.T) {
+	first, err := hashPassword("xk7#pq2m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := hashPassword("xk7#pq2m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == second {
+		t.Error("two hashes of the same password are equal")
+	}
+}
@@ -0,0 +1,13 @@
+package server
+
+import (
+	"net/http"
+)
+
+// handleJWKS publishes the public keys access tokens are signed with so
+// other services can verify them. Retired keys stay listed until they are
+// removed from JWT_KEYS_DIR, so caches may keep the document for a while.
+func (s *Server) handleJWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	respondWithJSON(w, http.StatusOK, s.signingKeys.JWKS())
+}
@@ -0,0 +1,269 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dukunuu/hackathon_backend/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	// Failures allowed per key before back-off kicks in. Many users can sit
+	// behind one address, so addresses get more slack than accounts.
+	emailThrottleFreeAttempts = 3
+	ipThrottleFreeAttempts    = 10
+
+	throttleBaseDelay = 1 * time.Second
+	throttleMaxDelay  = 15 * time.Minute
+
+	// Every accountLockoutThreshold consecutive failures lock the account.
+	accountLockoutThreshold = 10
+	accountLockoutDuration  = 30 * time.Minute
+)
+
+// AccountLockoutDTO is an entry of the account lockout audit log.
+// swagger:model AccountLockoutDTO
+type AccountLockoutDTO struct {
+	ID             uuid.UUID  `json:"id" format:"uuid"`
+	UserID         uuid.UUID  `json:"user_id" format:"uuid"`
+	IPAddress      string     `json:"ip_address,omitempty" example:"203.0.113.7"`
+	FailedAttempts int32      `json:"failed_attempts" example:"10"`
+	LockedAt       time.Time  `json:"locked_at"`
+	LockedUntil    time.Time  `json:"locked_until"`
+	UnlockedAt     *time.Time `json:"unlocked_at,omitempty"`
+	UnlockedBy     *uuid.UUID `json:"unlocked_by,omitempty" format:"uuid"`
+}
+
+func toAccountLockoutDTO(l db.AccountLockout) AccountLockoutDTO {
+	dto := AccountLockoutDTO{
+		ID:             l.ID.Bytes,
+		UserID:         l.UserID.Bytes,
+		IPAddress:      l.IpAddress.String,
+		FailedAttempts: l.FailedAttempts,
+		LockedAt:       l.LockedAt.Time,
+		LockedUntil:    l.LockedUntil.Time,
+	}
+	if l.UnlockedAt.Valid {
+		dto.UnlockedAt = &l.UnlockedAt.Time
+	}
+	if l.UnlockedBy.Valid {
+		unlockedBy := uuid.UUID(l.UnlockedBy.Bytes)
+		dto.UnlockedBy = &unlockedBy
+	}
+	return dto
+}
+
+func emailThrottleKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipThrottleKey(ip string) string {
+	return "ip:" + ip
+}
+
+// throttleDelay is the exponential back-off after the given number of
+// consecutive failures, or zero while still within the free attempts.
+func throttleDelay(failedAttempts int32, freeAttempts int32) time.Duration {
+	if failedAttempts < freeAttempts {
+		return 0
+	}
+	exp := float64(failedAttempts - freeAttempts)
+	delay := time.Duration(float64(throttleBaseDelay) * math.Pow(2, exp))
+	if delay <= 0 || delay > throttleMaxDelay {
+		return throttleMaxDelay
+	}
+	return delay
+}
+
+// respondTooManyAttempts writes a 429 telling the client when to try again.
+func respondTooManyAttempts(w http.ResponseWriter, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	respondWithError(w, http.StatusTooManyRequests, "Too many failed login attempts. Try again later.")
+}
+
+// loginBlockedFor returns how long logins for these keys are still blocked.
+func (s *Server) loginBlockedFor(ctx context.Context, keys ...string) (time.Duration, error) {
+	throttles, err := s.db.GetLoginThrottles(ctx, keys)
+	if err != nil {
+		return 0, err
+	}
+	var wait time.Duration
+	for _, t := range throttles {
+		if t.BlockedUntil.Valid {
+			if remaining := time.Until(t.BlockedUntil.Time); remaining > wait {
+				wait = remaining
+			}
+		}
+	}
+	return wait, nil
+}
+
+// accountLockedFor returns how long the user's account is still locked.
+func (s *Server) accountLockedFor(ctx context.Context, userID pgtype.UUID) (time.Duration, error) {
+	lockout, err := s.db.GetActiveAccountLockout(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return time.Until(lockout.LockedUntil.Time), nil
+}
+
+// recordLoginFailure counts a failed attempt against the email and client
+// address, blocks them for the back-off period and locks the account when
+// it crosses the lockout threshold. userID is invalid when no account
+// exists for the email. It returns how long the caller must now wait.
+func (s *Server) recordLoginFailure(ctx context.Context, r *http.Request, email string, userID pgtype.UUID) time.Duration {
+	ip := clientIP(r)
+	var wait time.Duration
+
+	block := func(key string, freeAttempts int32) int32 {
+		throttle, err := s.db.RecordLoginFailure(ctx, key)
+		if err != nil {
+			slog.Error("Failed to record login failure", "error", err, "key", key)
+			return 0
+		}
+		delay := throttleDelay(throttle.FailedAttempts, freeAttempts)
+		if delay == 0 {
+			return throttle.FailedAttempts
+		}
+		err = s.db.BlockLoginThrottle(ctx, db.BlockLoginThrottleParams{
+			ThrottleKey:  key,
+			BlockedUntil: pgtype.Timestamptz{Time: time.Now().Add(delay), Valid: true},
+		})
+		if err != nil {
+			slog.Error("Failed to apply login back-off", "error", err, "key", key)
+			return throttle.FailedAttempts
+		}
+		if delay > wait {
+			wait = delay
+		}
+		return throttle.FailedAttempts
+	}
+
+	emailFailures := block(emailThrottleKey(email), emailThrottleFreeAttempts)
+	block(ipThrottleKey(ip), ipThrottleFreeAttempts)
+
+	if userID.Valid && emailFailures > 0 && emailFailures%accountLockoutThreshold == 0 {
+		_, err := s.db.CreateAccountLockout(ctx, db.CreateAccountLockoutParams{
+			UserID:         userID,
+			IpAddress:      toPgtypeText(ip),
+			FailedAttempts: emailFailures,
+			LockedUntil:    pgtype.Timestamptz{Time: time.Now().Add(accountLockoutDuration), Valid: true},
+		})
+		if err != nil {
+			slog.Error("Failed to lock account", "error", err, "userID", userID)
+		} else {
+			slog.Warn("Account locked after repeated failed logins", "userID", userID, "failedAttempts", emailFailures, "ip", ip)
+			if accountLockoutDuration > wait {
+				wait = accountLockoutDuration
+			}
+		}
+	}
+	return wait
+}
+
+// handleUnlockUser lifts an account lockout before it expires.
+// @Summary Unlock a user account
+// @Description Lifts any active lockout of the account and clears its failed login counter. Requires the 'users:manage' permission.
+// @Tags Users
+// @Produce json
+// @Param userID path string true "User ID" format(uuid)
+// @Success 200 {object} map[string]string "message: Account unlocked"
+// @Failure 400 {object} ErrorResponse "Invalid user ID format"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Missing permission"
+// @Failure 404 {object} ErrorResponse "User not found"
+// @Failure 500 {object} ErrorResponse "Failed to unlock account"
+// @Security BearerAuth
+// @Router /users/{userID}/unlock [post]
+func (s *Server) handleUnlockUser(w http.ResponseWriter, r *http.Request) {
+	targetUserID, err := parseUUIDFromParam(r, "userID")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user ID format")
+		return
+	}
+	adminID, err := getUserIDFromContext(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	user, err := s.db.GetUserByID(r.Context(), targetUserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "User not found")
+			return
+		}
+		slog.Error("Failed to fetch user for unlock", "error", err, "userID", targetUserID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to unlock account")
+		return
+	}
+
+	unlocked, err := s.db.UnlockAccount(r.Context(), db.UnlockAccountParams{
+		UnlockedBy: adminID,
+		UserID:     user.ID,
+	})
+	if err != nil {
+		slog.Error("Failed to unlock account", "error", err, "userID", user.ID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to unlock account")
+		return
+	}
+	if err := s.db.ResetLoginThrottle(r.Context(), emailThrottleKey(user.Email)); err != nil {
+		slog.Error("Failed to reset login throttle", "error", err, "userID", user.ID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to unlock account")
+		return
+	}
+
+	slog.Info("Account unlocked by administrator", "userID", user.ID, "adminID", adminID, "lockoutsLifted", unlocked)
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "Account unlocked"})
+}
+
+// handleListUserLockouts returns the lockout audit log of an account.
+// @Summary List account lockouts
+// @Description Returns every lockout recorded for the account, newest first. Requires the 'users:manage' permission.
+// @Tags Users
+// @Produce json
+// @Param userID path string true "User ID" format(uuid)
+// @Success 200 {array} AccountLockoutDTO "Lockout history"
+// @Failure 400 {object} ErrorResponse "Invalid user ID format"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Missing permission"
+// @Failure 500 {object} ErrorResponse "Failed to retrieve lockouts"
+// @Security BearerAuth
+// @Router /users/{userID}/lockouts [get]
+func (s *Server) handleListUserLockouts(w http.ResponseWriter, r *http.Request) {
+	targetUserID, err := parseUUIDFromParam(r, "userID")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user ID format")
+		return
+	}
+
+	lockouts, err := s.db.ListAccountLockouts(r.Context(), targetUserID)
+	if err != nil {
+		slog.Error("Failed to list account lockouts", "error", err, "userID", targetUserID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve lockouts")
+		return
+	}
+
+	dtos := make([]AccountLockoutDTO, len(lockouts))
+	for i, l := range lockouts {
+		dtos[i] = toAccountLockoutDTO(l)
+	}
+	respondWithJSON(w, http.StatusOK, dtos)
+}
@@ -0,0 +1,518 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dukunuu/hackathon_backend/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	maxSkills            = 20
+	maxSkillLength       = 50
+	maxAvailabilitySlots = 28
+
+	// suggestionMaxDistanceM is where the distance part of a suggestion's
+	// score reaches zero.
+	suggestionMaxDistanceM = 50_000
+	// maxAIRankedSuggestions caps how many suggestions are sent to the model
+	// for re-ranking; the rest keep their score order after them.
+	maxAIRankedSuggestions = 20
+
+	errNotSkillsOrganizer = "Only the author of the post can change its needed skills"
+
+	suggestionSystemPrompt = "You match volunteers to community requests. You are given a request and numbered candidates. " +
+		"Order the candidates from best to worst match and explain each match in one short sentence. " +
+		`Respond with ONLY a JSON array like [{"candidate": 2, "reason": "..."}], with every candidate exactly once.`
+)
+
+// AvailabilitySlotDTO is a weekly time slot in which a user can volunteer.
+// Times are HH:MM in the server's time zone.
+// swagger:model AvailabilitySlotDTO
+type AvailabilitySlotDTO struct {
+	Weekday   int16  `json:"weekday" minimum:"0" maximum:"6" example:"6"`
+	StartTime string `json:"start_time" example:"09:00"`
+	EndTime   string `json:"end_time" example:"13:00"`
+}
+
+// VolunteerProfileDTO is what a user offers as a volunteer: skills, weekly
+// availability and where they are based.
+// swagger:model VolunteerProfileDTO
+type VolunteerProfileDTO struct {
+	Skills       []string              `json:"skills" example:"first aid,driving"`
+	Availability []AvailabilitySlotDTO `json:"availability"`
+	LocationLat  float64               `json:"location_lat,omitempty" example:"47.9187"`
+	LocationLng  float64               `json:"location_lng,omitempty" example:"106.9170"`
+}
+
+// PostSkillsDTO lists the skills a post needs from its volunteers.
+// swagger:model PostSkillsDTO
+type PostSkillsDTO struct {
+	Skills []string `json:"skills" example:"first aid,driving"`
+}
+
+// SuggestedVolunteerDTO is a volunteer suggested for a post and why.
+// swagger:model SuggestedVolunteerDTO
+type SuggestedVolunteerDTO struct {
+	UserID          uuid.UUID `json:"user_id" format:"uuid"`
+	FirstName       string    `json:"first_name" example:"John"`
+	LastName        string    `json:"last_name" example:"Doe"`
+	ProfileUrl      *string   `json:"profile_url,omitempty"`
+	MatchedSkills   []string  `json:"matched_skills" example:"first aid"`
+	AvailableShifts int64     `json:"available_shifts" example:"2"`
+	DistanceM       *float64  `json:"distance_m,omitempty" example:"1250.5"`
+	Score           float64   `json:"score" example:"0.72"`
+	Explanation     string    `json:"explanation,omitempty" example:"Has first aid training and lives nearby."`
+}
+
+// SuggestedVolunteersDTO is the ranked list of volunteers suggested for a post.
+// swagger:model SuggestedVolunteersDTO
+type SuggestedVolunteersDTO struct {
+	Items    []SuggestedVolunteerDTO `json:"items"`
+	AIRanked bool                    `json:"ai_ranked"`
+}
+
+// normalizeSkills trims and lower-cases skills and drops duplicates. The
+// error message is meant for a 400 response.
+func normalizeSkills(skills []string) ([]string, error) {
+	seen := make(map[string]bool, len(skills))
+	normalized := make([]string, 0, len(skills))
+	for _, skill := range skills {
+		skill = strings.ToLower(strings.TrimSpace(skill))
+		if skill == "" {
+			return nil, fmt.Errorf("skills cannot be empty")
+		}
+		if utf8.RuneCountInString(skill) > maxSkillLength {
+			return nil, fmt.Errorf("skills must be at most %d characters", maxSkillLength)
+		}
+		if !seen[skill] {
+			seen[skill] = true
+			normalized = append(normalized, skill)
+		}
+	}
+	if len(normalized) > maxSkills {
+		return nil, fmt.Errorf("at most %d skills are allowed", maxSkills)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// parseClockTime reads an HH:MM time of day.
+func parseClockTime(v string) (pgtype.Time, error) {
+	t, err := time.Parse("15:04", v)
+	if err != nil {
+		return pgtype.Time{}, err
+	}
+	minutes := int64(t.Hour()*60 + t.Minute())
+	return pgtype.Time{Microseconds: minutes * int64(time.Minute/time.Microsecond), Valid: true}, nil
+}
+
+func formatClockTime(t pgtype.Time) string {
+	minutes := t.Microseconds / int64(time.Minute/time.Microsecond)
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// parseAvailability validates availability slots for the user. The error
+// message is meant for a 400 response.
+func parseAvailability(userID pgtype.UUID, slots []AvailabilitySlotDTO) ([]db.CreateUserAvailabilityParams, error) {
+	if len(slots) > maxAvailabilitySlots {
+		return nil, fmt.Errorf("at most %d availability slots are allowed", maxAvailabilitySlots)
+	}
+	params := make([]db.CreateUserAvailabilityParams, len(slots))
+	for i, slot := range slots {
+		if slot.Weekday < 0 || slot.Weekday > 6 {
+			return nil, fmt.Errorf("weekday must be between 0 (Sunday) and 6 (Saturday)")
+		}
+		start, err := parseClockTime(slot.StartTime)
+		if err != nil {
+			return nil, fmt.Errorf("start_time must be HH:MM")
+		}
+		end, err := parseClockTime(slot.EndTime)
+		if err != nil {
+			return nil, fmt.Errorf("end_time must be HH:MM")
+		}
+		if end.Microseconds <= start.Microseconds {
+			return nil, fmt.Errorf("end_time must be after start_time")
+		}
+		params[i] = db.CreateUserAvailabilityParams{UserID: userID, Weekday: slot.Weekday, StartTime: start, EndTime: end}
+	}
+	return params, nil
+}
+
+// volunteerProfile loads the skills, availability and location of a user.
+func (s *Server) volunteerProfile(ctx context.Context, userID pgtype.UUID) (VolunteerProfileDTO, error) {
+	dto := VolunteerProfileDTO{Skills: []string{}, Availability: []AvailabilitySlotDTO{}}
+
+	skills, err := s.db.ListUserSkills(ctx, userID)
+	if err != nil {
+		return dto, err
+	}
+	dto.Skills = append(dto.Skills, skills...)
+
+	slots, err := s.db.ListUserAvailability(ctx, userID)
+	if err != nil {
+		return dto, err
+	}
+	for _, slot := range slots {
+		dto.Availability = append(dto.Availability, AvailabilitySlotDTO{
+			Weekday:   slot.Weekday,
+			StartTime: formatClockTime(slot.StartTime),
+			EndTime:   formatClockTime(slot.EndTime),
+		})
+	}
+
+	profile, err := s.db.GetVolunteerProfile(ctx, userID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) && !errors.Is(err, sql.ErrNoRows) {
+		return dto, err
+	}
+	dto.LocationLat = profile.LocationLat.Float64
+	dto.LocationLng = profile.LocationLng.Float64
+	return dto, nil
+}
+
+// handleGetVolunteerProfile returns the current user's volunteer profile.
+// @Summary Get volunteer profile
+// @Description Returns the current user's skills, weekly availability and location used to suggest them to organizers.
+// @Tags Users, Volunteers
+// @Produce json
+// @Success 200 {object} VolunteerProfileDTO "Volunteer profile"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 500 {object} ErrorResponse "Failed to retrieve volunteer profile"
+// @Security BearerAuth
+// @Router /users/me/volunteer-profile [get]
+func (s *Server) handleGetVolunteerProfile(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromContext(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	profile, err := s.volunteerProfile(r.Context(), userID)
+	if err != nil {
+		slog.Error("Failed to get volunteer profile", "error", err, "userID", userID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve volunteer profile")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, profile)
+}
+
+// handleUpdateVolunteerProfile replaces the current user's volunteer profile.
+// @Summary Update volunteer profile
+// @Description Replaces the current user's skills, weekly availability and location. Skills are stored trimmed and
+// @Description lower-cased. Availability weekdays run from 0 (Sunday) to 6 (Saturday) and times are HH:MM in the
+// @Description server's time zone. Omit the location, or send 0,0, to clear it.
+// @Tags Users, Volunteers
+// @Accept json
+// @Produce json
+// @Param profile body VolunteerProfileDTO true "New volunteer profile"
+// @Success 200 {object} VolunteerProfileDTO "Updated volunteer profile"
+// @Failure 400 {object} ErrorResponse "Invalid skills, availability or location"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 500 {object} ErrorResponse "Failed to update volunteer profile"
+// @Security BearerAuth
+// @Router /users/me/volunteer-profile [put]
+func (s *Server) handleUpdateVolunteerProfile(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromContext(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req VolunteerProfileDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload: "+err.Error())
+		return
+	}
+	defer r.Body.Close()
+
+	skills, err := normalizeSkills(req.Skills)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	slots, err := parseAvailability(userID, req.Availability)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	lat, lng, err := postLocation(req.LocationLat, req.LocationLng)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid location: "+err.Error())
+		return
+	}
+
+	err = s.db.ExecTx(r.Context(), func(q *db.Queries) error {
+		if err := q.DeleteUserSkills(r.Context(), userID); err != nil {
+			return err
+		}
+		if len(skills) > 0 {
+			if err := q.AddUserSkills(r.Context(), db.AddUserSkillsParams{UserID: userID, Skills: skills}); err != nil {
+				return err
+			}
+		}
+		if err := q.DeleteUserAvailability(r.Context(), userID); err != nil {
+			return err
+		}
+		for _, slot := range slots {
+			if _, err := q.CreateUserAvailability(r.Context(), slot); err != nil {
+				return err
+			}
+		}
+		_, err := q.UpsertVolunteerProfile(r.Context(), db.UpsertVolunteerProfileParams{UserID: userID, LocationLat: lat, LocationLng: lng})
+		return err
+	})
+	if err != nil {
+		slog.Error("Failed to update volunteer profile", "error", err, "userID", userID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to update volunteer profile")
+		return
+	}
+
+	profile, err := s.volunteerProfile(r.Context(), userID)
+	if err != nil {
+		slog.Error("Failed to get volunteer profile", "error", err, "userID", userID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve volunteer profile")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, profile)
+}
+
+// handleGetPostSkills lists the skills a post needs.
+// @Summary Get needed skills of a post
+// @Description Returns the skills the post needs from its volunteers.
+// @Tags Posts, Volunteers
+// @Produce json
+// @Param postId path string true "Post ID" format(uuid)
+// @Success 200 {object} PostSkillsDTO "Needed skills"
+// @Failure 400 {object} ErrorResponse "Invalid post ID format"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 500 {object} ErrorResponse "Failed to retrieve skills"
+// @Security BearerAuth
+// @Router /posts/{postId}/skills [get]
+func (s *Server) handleGetPostSkills(w http.ResponseWriter, r *http.Request) {
+	postID, err := uuid.Parse(r.PathValue("postId"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid post ID format")
+		return
+	}
+
+	skills, err := s.db.ListPostSkills(r.Context(), toPgtypeUUID(postID))
+	if err != nil {
+		slog.Error("Failed to list post skills", "error", err, "postID", postID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve skills")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, PostSkillsDTO{Skills: append([]string{}, skills...)})
+}
+
+// handleUpdatePostSkills replaces the skills a post needs.
+// @Summary Update needed skills of a post
+// @Description Replaces the skills the post needs from its volunteers. Skills are stored trimmed and lower-cased.
+// @Description Only the author of the post can change them.
+// @Tags Posts, Volunteers
+// @Accept json
+// @Produce json
+// @Param postId path string true "Post ID" format(uuid)
+// @Param skills body PostSkillsDTO true "Needed skills"
+// @Success 200 {object} PostSkillsDTO "Updated needed skills"
+// @Failure 400 {object} ErrorResponse "Invalid post ID format or skills"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Not the author of the post"
+// @Failure 404 {object} ErrorResponse "Post not found"
+// @Failure 500 {object} ErrorResponse "Failed to update skills"
+// @Security BearerAuth
+// @Router /posts/{postId}/skills [put]
+func (s *Server) handleUpdatePostSkills(w http.ResponseWriter, r *http.Request) {
+	post, ok := s.organizerPostFromPath(w, r, errNotSkillsOrganizer)
+	if !ok {
+		return
+	}
+
+	var req PostSkillsDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload: "+err.Error())
+		return
+	}
+	defer r.Body.Close()
+
+	skills, err := normalizeSkills(req.Skills)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = s.db.ExecTx(r.Context(), func(q *db.Queries) error {
+		if err := q.DeletePostSkills(r.Context(), post.ID); err != nil {
+			return err
+		}
+		if len(skills) == 0 {
+			return nil
+		}
+		return q.AddPostSkills(r.Context(), db.AddPostSkillsParams{PostID: post.ID, Skills: skills})
+	})
+	if err != nil {
+		slog.Error("Failed to update post skills", "error", err, "postID", post.ID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to update skills")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, PostSkillsDTO{Skills: skills})
+}
+
+// handleSuggestVolunteers ranks volunteers who could help with a post.
+// @Summary Suggest volunteers for a post
+// @Description Ranks users who are volunteering and have not applied yet. The score (0-1) weighs the share of the
+// @Description post's needed skills they have (0.5), the share of its upcoming shifts that fit their weekly availability
+// @Description (0.3) and how close they are based to the post, fading out at 50 km (0.2). With explain=true and an AI
+// @Description model configured, the top suggestions are re-ranked by the model with a short explanation each; no
+// @Description names or contact details are sent to it. ai_ranked reports whether that happened.
+// @Description Only the author of the post can see suggestions.
+// @Tags Posts, Volunteers
+// @Produce json
+// @Param postId path string true "Post ID" format(uuid)
+// @Param limit query int false "Maximum number of suggestions, 1-100 (default 20)"
+// @Param explain query bool false "Re-rank and explain the suggestions with the AI model"
+// @Success 200 {object} SuggestedVolunteersDTO "Suggestions, best first"
+// @Failure 400 {object} ErrorResponse "Invalid post ID format or query parameter"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Not the author of the post"
+// @Failure 404 {object} ErrorResponse "Post not found"
+// @Failure 500 {object} ErrorResponse "Failed to suggest volunteers"
+// @Security BearerAuth
+// @Router /posts/{postId}/suggested-volunteers [get]
+func (s *Server) handleSuggestVolunteers(w http.ResponseWriter, r *http.Request) {
+	post, ok := s.organizerPostFromPath(w, r, "Only the author of the post can see suggested volunteers")
+	if !ok {
+		return
+	}
+	limit, err := parseResultLimit(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	explain := false
+	if v := r.URL.Query().Get("explain"); v != "" {
+		if explain, err = strconv.ParseBool(v); err != nil {
+			respondWithError(w, http.StatusBadRequest, "explain must be true or false")
+			return
+		}
+	}
+
+	rows, err := s.db.ListSuggestedVolunteers(r.Context(), db.ListSuggestedVolunteersParams{
+		PostID:       post.ID,
+		MaxDistanceM: suggestionMaxDistanceM,
+		ResultLimit:  limit,
+	})
+	if err != nil {
+		slog.Error("Failed to list suggested volunteers", "error", err, "postID", post.ID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to suggest volunteers")
+		return
+	}
+
+	resp := SuggestedVolunteersDTO{Items: make([]SuggestedVolunteerDTO, len(rows))}
+	for i, row := range rows {
+		item := SuggestedVolunteerDTO{
+			UserID:          row.UserID.Bytes,
+			FirstName:       row.FirstName,
+			LastName:        row.LastName,
+			MatchedSkills:   append([]string{}, row.MatchedSkills...),
+			AvailableShifts: row.AvailableShifts,
+			Score:           row.Score,
+		}
+		if row.ProfileUrl.Valid {
+			item.ProfileUrl = &row.ProfileUrl.String
+		}
+		if row.DistanceM.Valid {
+			item.DistanceM = &row.DistanceM.Float64
+		}
+		resp.Items[i] = item
+	}
+
+	if explain && s.aiModel != nil && len(resp.Items) > 0 {
+		ranked, err := s.aiRankSuggestions(r.Context(), post, resp.Items)
+		if err != nil {
+			slog.Warn("AI re-ranking of suggested volunteers failed; keeping score order", "error", err, "postID", post.ID)
+		} else {
+			resp.Items, resp.AIRanked = ranked, true
+		}
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// aiRankSuggestions asks the AI model to re-order the top suggestions and
+// explain them. Candidates are only described by what matched, so no
+// personal data leaves the server.
+func (s *Server) aiRankSuggestions(ctx context.Context, post db.GetPostRow, items []SuggestedVolunteerDTO) ([]SuggestedVolunteerDTO, error) {
+	top := items
+	if len(top) > maxAIRankedSuggestions {
+		top = items[:maxAIRankedSuggestions]
+	}
+
+	neededSkills, err := s.db.ListPostSkills(ctx, post.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var prompt strings.Builder
+	fmt.Fprintf(&prompt, "Request: %s\n%s\n", post.Title, post.Description)
+	if len(neededSkills) > 0 {
+		fmt.Fprintf(&prompt, "Needed skills: %s\n", strings.Join(neededSkills, ", "))
+	}
+	prompt.WriteString("\nCandidates:\n")
+	for i, item := range top {
+		fmt.Fprintf(&prompt, "%d. matched skills: %s; upcoming shifts they are available for: %d", i+1, strings.Join(item.MatchedSkills, ", "), item.AvailableShifts)
+		if item.DistanceM != nil {
+			fmt.Fprintf(&prompt, "; distance: %.1f km", *item.DistanceM/1000)
+		}
+		fmt.Fprintf(&prompt, "; score: %.2f\n", item.Score)
+	}
+
+	answer, err := s.aiModel.GenerateResponseWithSystem(ctx, suggestionSystemPrompt, prompt.String())
+	if err != nil {
+		return nil, err
+	}
+	start, end := strings.Index(answer, "["), strings.LastIndex(answer, "]")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("no JSON array in AI answer")
+	}
+	var ranking []struct {
+		Candidate int    `json:"candidate"`
+		Reason    string `json:"reason"`
+	}
+	if err := json.Unmarshal([]byte(answer[start:end+1]), &ranking); err != nil {
+		return nil, fmt.Errorf("parse AI ranking: %w", err)
+	}
+
+	ranked := make([]SuggestedVolunteerDTO, 0, len(items))
+	used := make([]bool, len(top))
+	for _, entry := range ranking {
+		i := entry.Candidate - 1
+		if i < 0 || i >= len(top) || used[i] {
+			continue
+		}
+		used[i] = true
+		item := top[i]
+		item.Explanation = strings.TrimSpace(entry.Reason)
+		ranked = append(ranked, item)
+	}
+	if len(ranked) == 0 {
+		return nil, fmt.Errorf("AI ranking named no known candidate")
+	}
+	for i, item := range top {
+		if !used[i] {
+			ranked = append(ranked, item)
+		}
+	}
+	return append(ranked, items[len(top):]...), nil
+}
@@ -0,0 +1,714 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base32"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dukunuu/hackathon_backend/db"
+	"github.com/dukunuu/hackathon_backend/jwtkeys"
+	"github.com/dukunuu/hackathon_backend/totp"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	mfaPendingPurpose  = "mfa_pending"
+	mfaPendingTokenTTL = 5 * time.Minute
+
+	recoveryCodeCount = 10
+	recoveryCodeBytes = 6
+
+	settingRequireAdminMFA = "require_admin_mfa"
+
+	errAdminMFARequired = "Two-factor authentication is required for administrators. Log in again with two-factor authentication."
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// MFAChallengeResponseDTO is returned by login when a second factor is still required.
+// swagger:model MFAChallengeResponse
+type MFAChallengeResponseDTO struct {
+	MFARequired bool      `json:"mfa_required" example:"true"`
+	MFAToken    string    `json:"mfa_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// VerifyMFARequest defines the expected JSON body for completing a two-step login.
+// swagger:model VerifyMFARequest
+type VerifyMFARequest struct {
+	MFAToken string `json:"mfa_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	Code     string `json:"code" example:"123456"`
+}
+
+// MFACodeRequest defines the expected JSON body carrying a TOTP or recovery code.
+// swagger:model MFACodeRequest
+type MFACodeRequest struct {
+	Code string `json:"code" example:"123456"`
+}
+
+// DisableMFARequest defines the expected JSON body for turning two-factor authentication off.
+// swagger:model DisableMFARequest
+type DisableMFARequest struct {
+	CurrentPassword string `json:"current_password" example:"strongpassword123"`
+	Code            string `json:"code" example:"123456"`
+}
+
+// MFASetupResponseDTO carries the secret to add to an authenticator app.
+// swagger:model MFASetupResponse
+type MFASetupResponseDTO struct {
+	Secret     string `json:"secret" example:"JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+	OTPAuthURL string `json:"otpauth_url" example:"otpauth://totp/Hackathon:john.doe@example.com?secret=JBSWY3DPEHPK3PXP&issuer=Hackathon"`
+}
+
+// MFARecoveryCodesResponseDTO carries freshly generated recovery codes. They are only shown once.
+// swagger:model MFARecoveryCodesResponse
+type MFARecoveryCodesResponseDTO struct {
+	RecoveryCodes []string `json:"recovery_codes" example:"k3j9a-x7q2m"`
+}
+
+// MFAStatusDTO describes the two-factor state of the current user.
+// swagger:model MFAStatus
+type MFAStatusDTO struct {
+	Enabled                bool       `json:"enabled" example:"true"`
+	EnabledAt              *time.Time `json:"enabled_at,omitempty"`
+	RecoveryCodesRemaining int64      `json:"recovery_codes_remaining" example:"8"`
+	SessionVerified        bool       `json:"session_verified" example:"true"`
+}
+
+// SecuritySettingsDTO holds the security settings administrators can change at runtime.
+// swagger:model SecuritySettings
+type SecuritySettingsDTO struct {
+	RequireAdminMFA bool `json:"require_admin_mfa" example:"true"`
+}
+
+func generateMFAPendingJWT(userID pgtype.UUID, email string, keys *jwtkeys.KeySet, expiresAt time.Time) (string, error) {
+	claims := &Claims{
+		UserID:  userID,
+		Email:   email,
+		AMR:     []string{"pwd"},
+		Purpose: mfaPendingPurpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+	return keys.Sign(claims)
+}
+
+func getMFAVerifiedFromContext(ctx context.Context) bool {
+	verified, _ := ctx.Value(MFAVerifiedKey).(bool)
+	return verified
+}
+
+// adminMFARequired reports whether administrators must use two-factor authentication.
+func (s *Server) adminMFARequired(ctx context.Context) (bool, error) {
+	value, err := s.db.GetSystemSetting(ctx, settingRequireAdminMFA)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	required, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid value %q for setting %s: %w", value, settingRequireAdminMFA, err)
+	}
+	return required, nil
+}
+
+// mfaEnabled reports whether the user has completed two-factor enrollment.
+func (s *Server) mfaEnabled(ctx context.Context, userID pgtype.UUID) (bool, error) {
+	mfa, err := s.db.GetUserMFA(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return mfa.EnabledAt.Valid, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// verifySecondFactor accepts either a current TOTP code or an unused
+// recovery code. Accepted codes are burned so they cannot be replayed.
+func (s *Server) verifySecondFactor(ctx context.Context, userID pgtype.UUID, code string) (bool, error) {
+	mfa, err := s.db.GetUserMFA(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	if !mfa.EnabledAt.Valid {
+		return false, nil
+	}
+
+	if step, ok := totp.Validate(mfa.Secret, code, time.Now()); ok {
+		used, err := s.db.UseTOTPStep(ctx, db.UseTOTPStepParams{
+			Step:   pgtype.Int8{Int64: step, Valid: true},
+			UserID: userID,
+		})
+		if err != nil {
+			return false, err
+		}
+		return used == 1, nil
+	}
+
+	used, err := s.db.UseMFARecoveryCode(ctx, db.UseMFARecoveryCodeParams{
+		UserID:   userID,
+		CodeHash: hashToken(normalizeRecoveryCode(code)),
+	})
+	if err != nil {
+		return false, err
+	}
+	return used > 0, nil
+}
+
+// replaceRecoveryCodes discards the user's recovery codes and returns a
+// fresh set. Only hashes are stored. Callers run it in a transaction so a
+// failure keeps the old codes.
+func replaceRecoveryCodes(ctx context.Context, q *db.Queries, userID pgtype.UUID) ([]string, error) {
+	if err := q.DeleteMFARecoveryCodes(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+	codes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		buf := make([]byte, recoveryCodeBytes)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		raw := strings.ToLower(recoveryCodeEncoding.EncodeToString(buf))
+		codes[i] = raw[:5] + "-" + raw[5:]
+		err := q.CreateMFARecoveryCode(ctx, db.CreateMFARecoveryCodeParams{
+			UserID:   userID,
+			CodeHash: hashToken(raw),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to store recovery code: %w", err)
+		}
+	}
+	return codes, nil
+}
+
+// handleVerifyMFA completes a two-step login.
+// @Summary Complete login with a second factor
+// @Description Exchanges the 'mfa_token' returned by login plus a TOTP code or an unused recovery code for a session.
+// @Description Failed codes count towards the same login throttling as wrong passwords.
+// @Description An account scheduled for deletion that logged in with restore_account set is restored here.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param verification body VerifyMFARequest true "MFA token and code"
+// @Success 200 {object} LoginResponsePayloadDTO "Successfully logged in"
+// @Failure 400 {object} ErrorResponse "Invalid request payload or missing fields"
+// @Failure 401 {object} ErrorResponse "MFA token invalid or expired, or code invalid"
+// @Failure 410 {object} ErrorResponse "Account has been deleted"
+// @Failure 429 {object} ErrorResponse "Too many failed login attempts"
+// @Header 429 {integer} Retry-After "Seconds until the next attempt will be evaluated"
+// @Failure 500 {object} ErrorResponse "Failed to verify code"
+// @Router /auth/mfa/verify [post]
+func (s *Server) handleVerifyMFA(w http.ResponseWriter, r *http.Request) {
+	var req VerifyMFARequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	if req.MFAToken == "" || req.Code == "" {
+		respondWithError(w, http.StatusBadRequest, "mfa_token and code are required")
+		return
+	}
+
+	claims, err := parseJWT(req.MFAToken, s.signingKeys)
+	if err != nil || claims.Purpose != mfaPendingPurpose {
+		respondWithError(w, http.StatusUnauthorized, "MFA token is invalid or has expired")
+		return
+	}
+
+	user, err := s.db.GetUserByID(r.Context(), claims.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			respondWithError(w, http.StatusUnauthorized, "MFA token is invalid or has expired")
+			return
+		}
+		slog.Error("Failed to fetch user for MFA verification", "error", err, "userID", claims.UserID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to verify code")
+		return
+	}
+
+	wait, err := s.loginBlockedFor(r.Context(), emailThrottleKey(user.Email), ipThrottleKey(clientIP(r)))
+	if err == nil && wait == 0 {
+		wait, err = s.accountLockedFor(r.Context(), user.ID)
+	}
+	if err != nil {
+		slog.Error("Failed to check login throttles", "error", err, "userID", user.ID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to verify code")
+		return
+	}
+	if wait > 0 {
+		respondTooManyAttempts(w, wait)
+		return
+	}
+
+	ok, err := s.verifySecondFactor(r.Context(), user.ID, req.Code)
+	if err != nil {
+		slog.Error("Failed to verify second factor", "error", err, "userID", user.ID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to verify code")
+		return
+	}
+	if !ok {
+		if wait := s.recordLoginFailure(r.Context(), r, user.Email, user.ID); wait > 0 {
+			respondTooManyAttempts(w, wait)
+			return
+		}
+		respondWithError(w, http.StatusUnauthorized, "Invalid verification code")
+		return
+	}
+
+	if err := s.db.ResetLoginThrottle(r.Context(), emailThrottleKey(user.Email)); err != nil {
+		slog.Error("Failed to reset login throttle", "error", err, "userID", user.ID)
+	}
+
+	// Login only hands out an mfa_token for an account scheduled for deletion
+	// when restore_account was set.
+	if user.DeletedAt.Valid {
+		if user, ok = s.restoreAccountOnLogin(w, r, user.ID); !ok {
+			return
+		}
+	}
+
+	tokens, err := s.issueSession(r.Context(), r, user.ID, user.Email, user.Role, true)
+	if err != nil {
+		slog.Error("Failed to start session", "error", err, "userID", user.ID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to generate token")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, ToLoginResponsePayloadDTO(tokens, user))
+}
+
+// handleGetMFAStatus reports the two-factor state of the current user.
+// @Summary Get two-factor status
+// @Description Returns whether two-factor authentication is enabled, how many recovery codes are left and whether the current session passed 2FA.
+// @Tags Users
+// @Produce json
+// @Success 200 {object} MFAStatusDTO "Two-factor status"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 500 {object} ErrorResponse "Failed to retrieve two-factor status"
+// @Security BearerAuth
+// @Router /users/me/mfa [get]
+func (s *Server) handleGetMFAStatus(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromContext(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	status := MFAStatusDTO{SessionVerified: getMFAVerifiedFromContext(r.Context())}
+	mfa, err := s.db.GetUserMFA(r.Context(), userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) && !errors.Is(err, pgx.ErrNoRows) {
+		slog.Error("Failed to fetch MFA state", "error", err, "userID", userID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve two-factor status")
+		return
+	}
+	if err == nil && mfa.EnabledAt.Valid {
+		status.Enabled = true
+		status.EnabledAt = &mfa.EnabledAt.Time
+		status.RecoveryCodesRemaining, err = s.db.CountUnusedMFARecoveryCodes(r.Context(), userID)
+		if err != nil {
+			slog.Error("Failed to count recovery codes", "error", err, "userID", userID)
+			respondWithError(w, http.StatusInternalServerError, "Failed to retrieve two-factor status")
+			return
+		}
+	}
+	respondWithJSON(w, http.StatusOK, status)
+}
+
+// handleSetupMFA starts two-factor enrollment.
+// @Summary Start two-factor enrollment
+// @Description Generates a new TOTP secret for the current user. Two-factor authentication is only turned on once
+// @Description a code from the authenticator app is confirmed through POST /users/me/mfa/enable.
+// @Tags Users
+// @Produce json
+// @Success 200 {object} MFASetupResponseDTO "Secret and otpauth URL to add to an authenticator app"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 409 {object} ErrorResponse "Two-factor authentication is already enabled"
+// @Failure 500 {object} ErrorResponse "Failed to start two-factor enrollment"
+// @Security BearerAuth
+// @Router /users/me/mfa/setup [post]
+func (s *Server) handleSetupMFA(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromContext(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	user, err := s.db.GetUserByID(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			respondWithError(w, http.StatusUnauthorized, "User not found")
+			return
+		}
+		slog.Error("Failed to fetch user for MFA setup", "error", err, "userID", userID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to start two-factor enrollment")
+		return
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		slog.Error("Failed to generate TOTP secret", "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to start two-factor enrollment")
+		return
+	}
+
+	_, err = s.db.UpsertPendingUserMFA(r.Context(), db.UpsertPendingUserMFAParams{UserID: userID, Secret: secret})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			respondWithError(w, http.StatusConflict, "Two-factor authentication is already enabled")
+			return
+		}
+		slog.Error("Failed to store pending TOTP secret", "error", err, "userID", userID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to start two-factor enrollment")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, MFASetupResponseDTO{
+		Secret:     secret,
+		OTPAuthURL: totp.ProvisioningURI(s.mfaIssuer, user.Email, secret),
+	})
+}
+
+// handleEnableMFA confirms enrollment with a first code.
+// @Summary Enable two-factor authentication
+// @Description Confirms the secret from POST /users/me/mfa/setup with a current code and turns two-factor authentication on.
+// @Description Returns single-use recovery codes, which are only shown once. Other sessions of the user are revoked.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param code body MFACodeRequest true "Code from the authenticator app"
+// @Success 200 {object} MFARecoveryCodesResponseDTO "Two-factor authentication enabled"
+// @Failure 400 {object} ErrorResponse "Invalid request payload, no enrollment in progress or invalid code"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 409 {object} ErrorResponse "Two-factor authentication is already enabled"
+// @Failure 500 {object} ErrorResponse "Failed to enable two-factor authentication"
+// @Security BearerAuth
+// @Router /users/me/mfa/enable [post]
+func (s *Server) handleEnableMFA(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromContext(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req MFACodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	mfa, err := s.db.GetUserMFA(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			respondWithError(w, http.StatusBadRequest, "No two-factor enrollment in progress")
+			return
+		}
+		slog.Error("Failed to fetch pending MFA", "error", err, "userID", userID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to enable two-factor authentication")
+		return
+	}
+	if mfa.EnabledAt.Valid {
+		respondWithError(w, http.StatusConflict, "Two-factor authentication is already enabled")
+		return
+	}
+
+	step, ok := totp.Validate(mfa.Secret, req.Code, time.Now())
+	if !ok {
+		respondWithError(w, http.StatusBadRequest, "Invalid verification code")
+		return
+	}
+
+	// The old recovery codes are only replaced if MFA is enabled as well.
+	var codes []string
+	err = s.db.ExecTx(r.Context(), func(q *db.Queries) error {
+		var err error
+		if codes, err = replaceRecoveryCodes(r.Context(), q, userID); err != nil {
+			return err
+		}
+		_, err = q.EnableUserMFA(r.Context(), db.EnableUserMFAParams{
+			UserID:       userID,
+			LastUsedStep: pgtype.Int8{Int64: step, Valid: true},
+		})
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			respondWithError(w, http.StatusConflict, "Two-factor authentication is already enabled")
+			return
+		}
+		slog.Error("Failed to enable MFA", "error", err, "userID", userID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to enable two-factor authentication")
+		return
+	}
+
+	// The caller just proved possession of the second factor.
+	if sessionID, err := getSessionIDFromContext(r.Context()); err == nil {
+		if err := s.db.MarkSessionMFAVerified(r.Context(), sessionID); err != nil {
+			slog.Error("Failed to mark session MFA verified", "error", err, "sessionID", sessionID)
+		}
+	}
+	s.revokeOtherSessions(r.Context(), userID)
+
+	respondWithJSON(w, http.StatusOK, MFARecoveryCodesResponseDTO{RecoveryCodes: codes})
+}
+
+// handleDisableMFA turns two-factor authentication off.
+// @Summary Disable two-factor authentication
+// @Description Turns two-factor authentication off after checking the current password and a TOTP or recovery code.
+// @Description Administrators cannot disable it while two-factor authentication is required for administrators.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param disable body DisableMFARequest true "Current password and code"
+// @Success 200 {object} map[string]string "message: Two-factor authentication disabled"
+// @Failure 400 {object} ErrorResponse "Invalid request payload, missing fields or invalid code"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Current password is incorrect or 2FA is required for administrators"
+// @Failure 409 {object} ErrorResponse "Two-factor authentication is not enabled"
+// @Failure 500 {object} ErrorResponse "Failed to disable two-factor authentication"
+// @Security BearerAuth
+// @Router /users/me/mfa/disable [post]
+func (s *Server) handleDisableMFA(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromContext(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req DisableMFARequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	if req.CurrentPassword == "" || req.Code == "" {
+		respondWithError(w, http.StatusBadRequest, "current_password and code are required")
+		return
+	}
+
+	user, err := s.db.GetUserByID(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			respondWithError(w, http.StatusUnauthorized, "User not found")
+			return
+		}
+		slog.Error("Failed to fetch user for disabling MFA", "error", err, "userID", userID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to disable two-factor authentication")
+		return
+	}
+	if err := verifyPassword(user.PasswordHash, req.CurrentPassword); err != nil {
+		respondWithError(w, http.StatusForbidden, "Current password is incorrect")
+		return
+	}
+
+	enabled, err := s.mfaEnabled(r.Context(), userID)
+	if err != nil {
+		slog.Error("Failed to fetch MFA state", "error", err, "userID", userID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to disable two-factor authentication")
+		return
+	}
+	if !enabled {
+		respondWithError(w, http.StatusConflict, "Two-factor authentication is not enabled")
+		return
+	}
+
+	if user.Role == db.UserRoleADMIN {
+		required, err := s.adminMFARequired(r.Context())
+		if err != nil {
+			slog.Error("Failed to read admin MFA setting", "error", err)
+			respondWithError(w, http.StatusInternalServerError, "Failed to disable two-factor authentication")
+			return
+		}
+		if required {
+			respondWithError(w, http.StatusForbidden, "Two-factor authentication is required for administrators")
+			return
+		}
+	}
+
+	ok, err := s.verifySecondFactor(r.Context(), userID, req.Code)
+	if err != nil {
+		slog.Error("Failed to verify second factor", "error", err, "userID", userID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to disable two-factor authentication")
+		return
+	}
+	if !ok {
+		respondWithError(w, http.StatusBadRequest, "Invalid verification code")
+		return
+	}
+
+	// The recovery codes and the secret go together or not at all.
+	err = s.db.ExecTx(r.Context(), func(q *db.Queries) error {
+		if err := q.DeleteMFARecoveryCodes(r.Context(), userID); err != nil {
+			return fmt.Errorf("failed to delete recovery codes: %w", err)
+		}
+		return q.DeleteUserMFA(r.Context(), userID)
+	})
+	if err != nil {
+		slog.Error("Failed to disable MFA", "error", err, "userID", userID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to disable two-factor authentication")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "Two-factor authentication disabled"})
+}
+
+// handleRegenerateRecoveryCodes replaces the user's recovery codes.
+// @Summary Regenerate recovery codes
+// @Description Invalidates all existing recovery codes and returns a new set after checking a TOTP or recovery code.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param code body MFACodeRequest true "Current TOTP or recovery code"
+// @Success 200 {object} MFARecoveryCodesResponseDTO "New recovery codes"
+// @Failure 400 {object} ErrorResponse "Invalid request payload or invalid code"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 409 {object} ErrorResponse "Two-factor authentication is not enabled"
+// @Failure 500 {object} ErrorResponse "Failed to regenerate recovery codes"
+// @Security BearerAuth
+// @Router /users/me/mfa/recovery-codes [post]
+func (s *Server) handleRegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromContext(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req MFACodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	enabled, err := s.mfaEnabled(r.Context(), userID)
+	if err != nil {
+		slog.Error("Failed to fetch MFA state", "error", err, "userID", userID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to regenerate recovery codes")
+		return
+	}
+	if !enabled {
+		respondWithError(w, http.StatusConflict, "Two-factor authentication is not enabled")
+		return
+	}
+
+	ok, err := s.verifySecondFactor(r.Context(), userID, req.Code)
+	if err != nil {
+		slog.Error("Failed to verify second factor", "error", err, "userID", userID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to regenerate recovery codes")
+		return
+	}
+	if !ok {
+		respondWithError(w, http.StatusBadRequest, "Invalid verification code")
+		return
+	}
+
+	var codes []string
+	err = s.db.ExecTx(r.Context(), func(q *db.Queries) error {
+		var err error
+		codes, err = replaceRecoveryCodes(r.Context(), q, userID)
+		return err
+	})
+	if err != nil {
+		slog.Error("Failed to regenerate recovery codes", "error", err, "userID", userID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to regenerate recovery codes")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, MFARecoveryCodesResponseDTO{RecoveryCodes: codes})
+}
+
+// handleGetSecuritySettings returns the runtime security settings.
+// @Summary Get security settings
+// @Description Returns the security settings administrators can change at runtime. Requires the 'users:manage' permission.
+// @Tags Settings
+// @Produce json
+// @Success 200 {object} SecuritySettingsDTO "Current security settings"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Missing permission"
+// @Failure 500 {object} ErrorResponse "Failed to retrieve security settings"
+// @Security BearerAuth
+// @Router /settings/security [get]
+func (s *Server) handleGetSecuritySettings(w http.ResponseWriter, r *http.Request) {
+	required, err := s.adminMFARequired(r.Context())
+	if err != nil {
+		slog.Error("Failed to read admin MFA setting", "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve security settings")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, SecuritySettingsDTO{RequireAdminMFA: required})
+}
+
+// handleUpdateSecuritySettings changes the runtime security settings.
+// @Summary Update security settings
+// @Description Turns the requirement for administrators to use two-factor authentication on or off.
+// @Description Administrators whose session did not pass 2FA lose their privileges until they log in with it.
+// @Description To avoid locking everyone out, only an administrator whose own session passed 2FA can turn it on.
+// @Description Requires the 'users:manage' permission.
+// @Tags Settings
+// @Accept json
+// @Produce json
+// @Param settings body SecuritySettingsDTO true "New security settings"
+// @Success 200 {object} SecuritySettingsDTO "Updated security settings"
+// @Failure 400 {object} ErrorResponse "Invalid request payload"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Missing permission"
+// @Failure 409 {object} ErrorResponse "Caller's session did not pass two-factor authentication"
+// @Failure 500 {object} ErrorResponse "Failed to update security settings"
+// @Security BearerAuth
+// @Router /settings/security [put]
+func (s *Server) handleUpdateSecuritySettings(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromContext(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req SecuritySettingsDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	if req.RequireAdminMFA && !getMFAVerifiedFromContext(r.Context()) {
+		respondWithError(w, http.StatusConflict, "Enable two-factor authentication and log in with it before requiring it for administrators")
+		return
+	}
+
+	err = s.db.UpsertSystemSetting(r.Context(), db.UpsertSystemSettingParams{
+		Key:       settingRequireAdminMFA,
+		Value:     strconv.FormatBool(req.RequireAdminMFA),
+		UpdatedBy: userID,
+	})
+	if err != nil {
+		slog.Error("Failed to update admin MFA setting", "error", err, "userID", userID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to update security settings")
+		return
+	}
+	slog.Info("Security settings updated", "requireAdminMFA", req.RequireAdminMFA, "userID", userID)
+	respondWithJSON(w, http.StatusOK, req)
+}
@@ -2,15 +2,25 @@ package server
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
 	"net/http"
 	"strings"
+
+	"github.com/dukunuu/hackathon_backend/db"
+	"github.com/jackc/pgx/v5"
 )
 
 func (s *Server) AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
-			respondWithError(w, http.StatusUnauthorized, "Authorization header required")
+			if apiKey := r.Header.Get("X-API-Key"); apiKey != "" {
+				s.authenticateAPIKey(w, r, apiKey, next)
+				return
+			}
+			respondWithError(w, http.StatusUnauthorized, "Authorization header or X-API-Key required")
 			return
 		}
 
@@ -21,17 +31,55 @@ func (s *Server) AuthMiddleware(next http.Handler) http.Handler {
 		}
 		tokenString := parts[1]
 
-		claims, err := parseJWT(tokenString, s.jwtSecret)
+		claims, err := parseJWT(tokenString, s.signingKeys)
 		if err != nil {
 			respondWithError(w, http.StatusUnauthorized, "Invalid token: "+err.Error())
 			return
 		}
 
+		if claims.Purpose != "" {
+			respondWithError(w, http.StatusUnauthorized, "Invalid token: not an access token")
+			return
+		}
+
+		if !claims.SessionID.Valid {
+			respondWithError(w, http.StatusUnauthorized, "Invalid token: missing session")
+			return
+		}
+
+		// Checking the session on every request makes logout and revocation
+		// immediate. The role claim may also be stale after a promotion or
+		// demotion, so the current role and MFA state come from the same lookup.
+		session, err := s.db.GetActiveSession(r.Context(), db.GetActiveSessionParams{
+			ID:     claims.SessionID,
+			UserID: claims.UserID,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+				respondWithError(w, http.StatusUnauthorized, "Session has been revoked or has expired")
+				return
+			}
+			slog.Error("Failed to validate session", "error", err, "userID", claims.UserID, "sessionID", claims.SessionID)
+			respondWithError(w, http.StatusInternalServerError, "Failed to authorize request")
+			return
+		}
+
+		mfaRequired := false
+		if session.Role == db.UserRoleADMIN && !session.MfaVerified {
+			mfaRequired, err = s.adminMFARequired(r.Context())
+			if err != nil {
+				slog.Error("Failed to read admin MFA setting", "error", err)
+				respondWithError(w, http.StatusInternalServerError, "Failed to authorize request")
+				return
+			}
+		}
+
 		// Add user information to context
 		ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
-		if claims.Role != nil {
-			ctx = context.WithValue(ctx, UserRoleKey, claims.Role)
-		}
+		ctx = context.WithValue(ctx, UserRoleKey, session.Role)
+		ctx = context.WithValue(ctx, SessionIDKey, claims.SessionID)
+		ctx = context.WithValue(ctx, MFAVerifiedKey, session.MfaVerified)
+		ctx = context.WithValue(ctx, MFARequiredKey, mfaRequired)
 
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
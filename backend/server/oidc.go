@@ -0,0 +1,419 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dukunuu/hackathon_backend/db"
+	"github.com/dukunuu/hackathon_backend/oidc"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"golang.org/x/oauth2"
+)
+
+const (
+	oidcAuthRequestTTL = 10 * time.Minute
+	// The login code handed to the frontend only has to survive one redirect.
+	oidcLoginCodeTTL = 2 * time.Minute
+	// oidcAuthCookie binds a login attempt to the browser that started it.
+	oidcAuthCookie     = "oidc_auth"
+	oidcAuthCookiePath = "/api/v1/auth/oidc/"
+)
+
+var (
+	// errUnverifiedIdentityEmail means the identity's email belongs to an
+	// existing account but the provider has not verified it, so it cannot be
+	// linked.
+	errUnverifiedIdentityEmail = errors.New("identity email is not verified by the provider")
+	// errUnverifiedAccountEmail means the existing account with the identity's
+	// email never proved it owns that address. Whoever registered it may not be
+	// the person signing in, so it is not linked; a password reset verifies it.
+	errUnverifiedAccountEmail = errors.New("account email is not verified")
+)
+
+// OIDCProvidersResponseDTO lists the identity providers users can sign in with.
+// swagger:model OIDCProvidersResponse
+type OIDCProvidersResponseDTO struct {
+	Providers []string `json:"providers" example:"google,stub"`
+}
+
+// OIDCExchangeRequest defines the expected JSON body for redeeming an OIDC login code.
+// swagger:model OIDCExchangeRequest
+type OIDCExchangeRequest struct {
+	Code string `json:"code" example:"Xq3v9kT0..."`
+}
+
+// oidcRedirect sends the browser back to the frontend with either a login
+// code or an error identifier in the query string.
+func (s *Server) oidcRedirect(w http.ResponseWriter, r *http.Request, key, value string) {
+	http.Redirect(w, r, s.appBaseURL+"/auth/oidc/callback?"+key+"="+url.QueryEscape(value), http.StatusFound)
+}
+
+// setOIDCAuthCookie remembers the state and nonce of a login attempt in the
+// browser that started it. maxAge -1 deletes the cookie.
+func (s *Server) setOIDCAuthCookie(w http.ResponseWriter, value string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcAuthCookie,
+		Value:    value,
+		Path:     oidcAuthCookiePath,
+		MaxAge:   maxAge,
+		Secure:   s.secureCookies,
+		HttpOnly: true,
+		// Lax still sends the cookie on the provider's top-level redirect back.
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// oidcAuthCookieMatches reports whether the request carries the cookie set
+// when the login with this state and nonce started.
+func oidcAuthCookieMatches(r *http.Request, state, nonce string) bool {
+	cookie, err := r.Cookie(oidcAuthCookie)
+	if err != nil {
+		return false
+	}
+	return state != "" && subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state+"."+nonce)) == 1
+}
+
+// oidcNames splits the profile claims into first and last name, falling back
+// to the full name and then to the email address.
+func oidcNames(identity oidc.Identity) (string, string) {
+	firstName, lastName := strings.TrimSpace(identity.GivenName), strings.TrimSpace(identity.FamilyName)
+	if firstName == "" && lastName == "" {
+		if parts := strings.Fields(identity.Name); len(parts) > 0 {
+			firstName = parts[0]
+			lastName = strings.Join(parts[1:], " ")
+		}
+	}
+	if firstName == "" {
+		firstName, _, _ = strings.Cut(identity.Email, "@")
+	}
+	return firstName, lastName
+}
+
+// userForIdentity returns the user linked to the external identity. An
+// unknown identity is linked to the account with the same email only when
+// both the provider and the account have verified that address; without such
+// an account a new one is created.
+func (s *Server) userForIdentity(ctx context.Context, provider string, identity oidc.Identity) (db.User, error) {
+	linked, err := s.db.GetUserIdentity(ctx, db.GetUserIdentityParams{Provider: provider, Subject: identity.Subject})
+	if err == nil {
+		if err := s.db.TouchUserIdentity(ctx, db.TouchUserIdentityParams{ID: linked.ID, Email: toPgtypeText(identity.Email)}); err != nil {
+			slog.Error("Failed to update user identity", "error", err, "identityID", linked.ID)
+		}
+		return s.db.GetUserByID(ctx, linked.UserID)
+	}
+	if !errors.Is(err, sql.ErrNoRows) && !errors.Is(err, pgx.ErrNoRows) {
+		return db.User{}, err
+	}
+
+	if identity.Email == "" {
+		return db.User{}, errors.New("identity provider did not return an email address")
+	}
+
+	user, err := s.db.GetUserByEmail(ctx, identity.Email)
+	switch {
+	case err == nil:
+		if !identity.EmailVerified {
+			return db.User{}, errUnverifiedIdentityEmail
+		}
+		if !user.EmailVerifiedAt.Valid {
+			return db.User{}, errUnverifiedAccountEmail
+		}
+	case errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows):
+		user, err = s.createOIDCUser(ctx, identity)
+		if err != nil {
+			return db.User{}, err
+		}
+	default:
+		return db.User{}, err
+	}
+
+	_, err = s.db.CreateUserIdentity(ctx, db.CreateUserIdentityParams{
+		UserID:   user.ID,
+		Provider: provider,
+		Subject:  identity.Subject,
+		Email:    toPgtypeText(identity.Email),
+	})
+	if err != nil {
+		return db.User{}, fmt.Errorf("failed to link identity: %w", err)
+	}
+	slog.Info("Linked external identity", "userID", user.ID, "provider", provider)
+	return user, nil
+}
+
+// createOIDCUser registers a user on their first external login. The account
+// gets an unusable random password; one can be set through password reset.
+func (s *Server) createOIDCUser(ctx context.Context, identity oidc.Identity) (db.User, error) {
+	randomPassword, _, err := newOpaqueToken()
+	if err != nil {
+		return db.User{}, err
+	}
+	hashedPassword, err := hashPassword(randomPassword)
+	if err != nil {
+		return db.User{}, err
+	}
+
+	firstName, lastName := oidcNames(identity)
+	user, err := s.db.CreateUser(ctx, db.CreateUserParams{
+		FirstName:      firstName,
+		LastName:       lastName,
+		IsVolunteering: false,
+		Email:          identity.Email,
+		Role:           db.UserRoleUSER,
+		PasswordHash:   hashedPassword,
+	})
+	if err != nil {
+		return db.User{}, err
+	}
+	if identity.EmailVerified {
+		if user, err = s.db.MarkUserEmailVerified(ctx, user.ID); err != nil {
+			return db.User{}, err
+		}
+	}
+	slog.Info("Created user from external identity", "userID", user.ID)
+	return user, nil
+}
+
+// handleListOIDCProviders returns the configured identity providers.
+// @Summary List OIDC providers
+// @Description Returns the names of the identity providers that can be used with GET /auth/oidc/{provider}/login.
+// @Tags Authentication
+// @Produce json
+// @Success 200 {object} OIDCProvidersResponseDTO "Configured providers"
+// @Router /auth/oidc/providers [get]
+func (s *Server) handleListOIDCProviders(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, OIDCProvidersResponseDTO{Providers: s.oidc.Names()})
+}
+
+// handleOIDCLogin starts an authorization code flow with PKCE.
+// @Summary Start OIDC login
+// @Description Redirects the browser to the identity provider and sets a short-lived HttpOnly cookie that ties the
+// @Description attempt to this browser. After the user signs in, the provider returns to
+// @Description GET /auth/oidc/{provider}/callback, which redirects to the frontend at '/auth/oidc/callback' with a
+// @Description one-time 'code' (or an 'error'). Exchange that code at POST /auth/oidc/exchange.
+// @Tags Authentication
+// @Param provider path string true "Provider name"
+// @Success 302 "Redirect to the identity provider"
+// @Failure 404 {object} ErrorResponse "Unknown provider"
+// @Failure 502 {object} ErrorResponse "Identity provider unavailable"
+// @Failure 500 {object} ErrorResponse "Failed to start login"
+// @Router /auth/oidc/{provider}/login [get]
+func (s *Server) handleOIDCLogin(w http.ResponseWriter, r *http.Request) {
+	providerName := chi.URLParam(r, "provider")
+	provider, ok := s.oidc.Get(providerName)
+	if !ok {
+		respondWithError(w, http.StatusNotFound, "Unknown identity provider")
+		return
+	}
+
+	state, _, err := newOpaqueToken()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to start login")
+		return
+	}
+	nonce, _, err := newOpaqueToken()
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Failed to start login")
+		return
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	authURL, err := provider.AuthCodeURL(state, nonce, verifier)
+	if err != nil {
+		slog.Error("Failed to reach identity provider", "error", err, "provider", providerName)
+		respondWithError(w, http.StatusBadGateway, "Identity provider is unavailable")
+		return
+	}
+
+	if err := s.db.DeleteExpiredOIDCAuthRequests(r.Context()); err != nil {
+		slog.Error("Failed to delete expired OIDC auth requests", "error", err)
+	}
+	err = s.db.CreateOIDCAuthRequest(r.Context(), db.CreateOIDCAuthRequestParams{
+		State:        state,
+		Provider:     providerName,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    pgtype.Timestamptz{Time: time.Now().Add(oidcAuthRequestTTL), Valid: true},
+	})
+	if err != nil {
+		slog.Error("Failed to store OIDC auth request", "error", err, "provider", providerName)
+		respondWithError(w, http.StatusInternalServerError, "Failed to start login")
+		return
+	}
+
+	s.setOIDCAuthCookie(w, state+"."+nonce, int(oidcAuthRequestTTL/time.Second))
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// handleOIDCCallback completes the flow at the identity provider's redirect.
+// @Summary OIDC callback
+// @Description Called by the identity provider. Verifies the ID token, links or creates the user and redirects to
+// @Description the frontend with a one-time login code. The browser must carry the HttpOnly cookie set by the login
+// @Description endpoint, otherwise the error is 'invalid_state'. Existing accounts are only linked when the provider
+// @Description reports the email address as verified ('email_not_verified' otherwise) and the account has verified it
+// @Description too ('account_email_not_verified' otherwise; a password reset verifies it).
+// @Tags Authentication
+// @Param provider path string true "Provider name"
+// @Param state query string true "State issued by the login endpoint"
+// @Param code query string true "Authorization code"
+// @Success 302 "Redirect to the frontend with 'code' or 'error'"
+// @Router /auth/oidc/{provider}/callback [get]
+func (s *Server) handleOIDCCallback(w http.ResponseWriter, r *http.Request) {
+	providerName := chi.URLParam(r, "provider")
+	provider, ok := s.oidc.Get(providerName)
+	if !ok {
+		respondWithError(w, http.StatusNotFound, "Unknown identity provider")
+		return
+	}
+
+	// The attempt is single use whatever the outcome.
+	s.setOIDCAuthCookie(w, "", -1)
+
+	query := r.URL.Query()
+	authRequest, err := s.db.ConsumeOIDCAuthRequest(r.Context(), db.ConsumeOIDCAuthRequestParams{
+		State:    query.Get("state"),
+		Provider: providerName,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			s.oidcRedirect(w, r, "error", "invalid_state")
+			return
+		}
+		slog.Error("Failed to load OIDC auth request", "error", err, "provider", providerName)
+		s.oidcRedirect(w, r, "error", "server_error")
+		return
+	}
+	// A callback URL from someone else's login must not sign this browser in.
+	if !oidcAuthCookieMatches(r, authRequest.State, authRequest.Nonce) {
+		slog.Info("OIDC callback without the matching auth cookie", "provider", providerName)
+		s.oidcRedirect(w, r, "error", "invalid_state")
+		return
+	}
+	if providerErr := query.Get("error"); providerErr != "" {
+		slog.Info("Identity provider returned an error", "provider", providerName, "error", providerErr)
+		s.oidcRedirect(w, r, "error", "access_denied")
+		return
+	}
+
+	identity, err := provider.Exchange(r.Context(), query.Get("code"), authRequest.CodeVerifier, authRequest.Nonce)
+	if err != nil {
+		slog.Error("OIDC code exchange failed", "error", err, "provider", providerName)
+		s.oidcRedirect(w, r, "error", "exchange_failed")
+		return
+	}
+
+	user, err := s.userForIdentity(r.Context(), providerName, identity)
+	if err != nil {
+		if errors.Is(err, errUnverifiedIdentityEmail) {
+			s.oidcRedirect(w, r, "error", "email_not_verified")
+			return
+		}
+		if errors.Is(err, errUnverifiedAccountEmail) {
+			s.oidcRedirect(w, r, "error", "account_email_not_verified")
+			return
+		}
+		slog.Error("Failed to resolve user for external identity", "error", err, "provider", providerName)
+		s.oidcRedirect(w, r, "error", "server_error")
+		return
+	}
+	if user.DeletedAt.Valid {
+		// Restoring needs the password login, which asks for confirmation.
+		s.oidcRedirect(w, r, "error", "account_deleted")
+		return
+	}
+
+	loginCode, err := s.issueUserToken(r.Context(), user.ID, db.UserTokenPurposeOidcLogin, "", oidcLoginCodeTTL)
+	if err != nil {
+		slog.Error("Failed to issue OIDC login code", "error", err, "userID", user.ID)
+		s.oidcRedirect(w, r, "error", "server_error")
+		return
+	}
+	s.oidcRedirect(w, r, "code", loginCode)
+}
+
+// handleOIDCExchange trades a one-time login code for a session.
+// @Summary Complete OIDC login
+// @Description Redeems the code the frontend received from the OIDC callback. Returns the same tokens as password login,
+// @Description or a 202 with an 'mfa_token' when the account has two-factor authentication enabled.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param exchange body OIDCExchangeRequest true "Login code"
+// @Success 200 {object} LoginResponsePayloadDTO "Login successful"
+// @Success 202 {object} MFAChallengeResponseDTO "Complete the login at POST /auth/mfa/verify"
+// @Failure 400 {object} ErrorResponse "Code is invalid, expired or has already been used"
+// @Failure 409 {object} ErrorResponse "Account is scheduled for deletion"
+// @Failure 429 {object} ErrorResponse "Account temporarily locked"
+// @Failure 500 {object} ErrorResponse "Login failed"
+// @Router /auth/oidc/exchange [post]
+func (s *Server) handleOIDCExchange(w http.ResponseWriter, r *http.Request) {
+	var req OIDCExchangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	userToken, ok := s.consumeUserToken(w, r, req.Code, db.UserTokenPurposeOidcLogin)
+	if !ok {
+		return
+	}
+
+	user, err := s.db.GetUserByID(r.Context(), userToken.UserID)
+	if err != nil {
+		slog.Error("Failed to fetch user for OIDC login", "error", err, "userID", userToken.UserID)
+		respondWithError(w, http.StatusInternalServerError, "Login failed")
+		return
+	}
+	if user.DeletedAt.Valid {
+		respondWithError(w, http.StatusConflict, "Account is scheduled for deletion")
+		return
+	}
+
+	locked, err := s.accountLockedFor(r.Context(), user.ID)
+	if err != nil {
+		slog.Error("Failed to check account lockout", "error", err, "userID", user.ID)
+		respondWithError(w, http.StatusInternalServerError, "Login failed")
+		return
+	}
+	if locked > 0 {
+		respondTooManyAttempts(w, locked)
+		return
+	}
+
+	mfaEnabled, err := s.mfaEnabled(r.Context(), user.ID)
+	if err != nil {
+		slog.Error("Failed to check MFA enrollment", "error", err, "userID", user.ID)
+		respondWithError(w, http.StatusInternalServerError, "Login failed")
+		return
+	}
+	if mfaEnabled {
+		expiresAt := time.Now().Add(mfaPendingTokenTTL)
+		mfaToken, err := generateMFAPendingJWT(user.ID, user.Email, s.signingKeys, expiresAt)
+		if err != nil {
+			slog.Error("Failed to generate MFA token", "error", err, "userID", user.ID)
+			respondWithError(w, http.StatusInternalServerError, "Failed to generate token")
+			return
+		}
+		respondWithJSON(w, http.StatusAccepted, MFAChallengeResponseDTO{MFARequired: true, MFAToken: mfaToken, ExpiresAt: expiresAt})
+		return
+	}
+
+	tokens, err := s.issueSession(r.Context(), r, user.ID, user.Email, user.Role, false)
+	if err != nil {
+		slog.Error("Failed to start session", "error", err, "userID", user.ID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to generate token")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, ToLoginResponsePayloadDTO(tokens, user))
+}
@@ -0,0 +1,157 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// PageDTO is one page of a cursor-paginated listing. Pass NextCursor as the
+// 'cursor' query parameter to fetch the following page; it is omitted on the
+// last page.
+type PageDTO[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty" example:"eyJ0IjoiMjAyMy0wMS0wMVQxMjowMDowMFoiLCJpZCI6ImExYjJjM2Q0In0"`
+}
+
+// pageCursor identifies the last row of a page. Clients only ever see it
+// base64-encoded and must treat it as opaque.
+type pageCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uuid.UUID `json:"id"`
+	Asc       bool      `json:"asc,omitempty"`
+}
+
+func (c pageCursor) encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodePageCursor(s string) (pageCursor, error) {
+	var c pageCursor
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, err
+	}
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return c, err
+	}
+	if c.CreatedAt.IsZero() || c.ID == uuid.Nil {
+		return c, fmt.Errorf("incomplete cursor")
+	}
+	return c, nil
+}
+
+// pageRequest holds the paging parameters shared by every listing: 'limit',
+// 'sort' (created_at or -created_at) and 'cursor'.
+type pageRequest struct {
+	Size    int32
+	SortAsc bool
+	After   *pageCursor
+}
+
+// parsePageRequest reads the paging query parameters. The error message is
+// meant for a 400 response.
+func parsePageRequest(r *http.Request) (pageRequest, error) {
+	q := r.URL.Query()
+	page := pageRequest{Size: defaultPageSize}
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxPageSize {
+			return page, fmt.Errorf("limit must be between 1 and %d", maxPageSize)
+		}
+		page.Size = int32(limit)
+	}
+
+	switch q.Get("sort") {
+	case "", "-created_at":
+	case "created_at":
+		page.SortAsc = true
+	default:
+		return page, fmt.Errorf("sort must be 'created_at' or '-created_at'")
+	}
+
+	if v := q.Get("cursor"); v != "" {
+		cursor, err := decodePageCursor(v)
+		if err != nil {
+			return page, fmt.Errorf("cursor is invalid")
+		}
+		if cursor.Asc != page.SortAsc {
+			return page, fmt.Errorf("cursor was issued for a different sort order")
+		}
+		page.After = &cursor
+	}
+	return page, nil
+}
+
+// fetchSize asks for one row more than the page holds, to learn whether
+// another page follows.
+func (p pageRequest) fetchSize() int32 {
+	return p.Size + 1
+}
+
+func (p pageRequest) cursorCreatedAt() pgtype.Timestamptz {
+	if p.After == nil {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: p.After.CreatedAt, Valid: true}
+}
+
+func (p pageRequest) cursorID() pgtype.UUID {
+	if p.After == nil {
+		return pgtype.UUID{}
+	}
+	return toPgtypeUUID(p.After.ID)
+}
+
+// trimPage drops the look-ahead row fetched by fetchSize and returns the
+// cursor of the next page, or "" when rows was the last page.
+func trimPage[T any](p pageRequest, rows []T, key func(T) (pgtype.Timestamptz, pgtype.UUID)) ([]T, string) {
+	if int32(len(rows)) <= p.Size {
+		return rows, ""
+	}
+	rows = rows[:p.Size]
+	createdAt, id := key(rows[len(rows)-1])
+	return rows, pageCursor{CreatedAt: createdAt.Time, ID: id.Bytes, Asc: p.SortAsc}.encode()
+}
+
+// parseTimeParam reads an optional RFC 3339 timestamp or YYYY-MM-DD date
+// from the query string.
+func parseTimeParam(r *http.Request, name string) (pgtype.Timestamptz, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return pgtype.Timestamptz{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		if t, err = time.Parse(time.DateOnly, v); err != nil {
+			return pgtype.Timestamptz{}, fmt.Errorf("%s must be an RFC 3339 timestamp or a YYYY-MM-DD date", name)
+		}
+	}
+	return pgtype.Timestamptz{Time: t, Valid: true}, nil
+}
+
+// parseUUIDParam reads an optional UUID from the query string.
+func parseUUIDParam(r *http.Request, name string) (pgtype.UUID, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return pgtype.UUID{}, nil
+	}
+	id, err := uuid.Parse(v)
+	if err != nil {
+		return pgtype.UUID{}, fmt.Errorf("%s must be a UUID", name)
+	}
+	return toPgtypeUUID(id), nil
+}
@@ -0,0 +1,139 @@
+package server
+
+import (
+	"encoding/base64"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestPageCursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2025, 3, 1, 12, 30, 15, 123456000, time.UTC)
+	id := uuid.MustParse("a1b2c3d4-e5f6-7777-8888-99990000aaaa")
+
+	for _, asc := range []bool{false, true} {
+		want := pageCursor{CreatedAt: createdAt, ID: id, Asc: asc}
+		got, err := decodePageCursor(want.encode())
+		if err != nil {
+			t.Fatalf("decodePageCursor(asc=%v): %v", asc, err)
+		}
+		if !got.CreatedAt.Equal(want.CreatedAt) || got.ID != want.ID || got.Asc != want.Asc {
+			t.Errorf("round trip (asc=%v) = %+v, want %+v", asc, got, want)
+		}
+	}
+}
+
+func TestDecodePageCursorRejectsTampering(t *testing.T) {
+	valid := pageCursor{CreatedAt: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC), ID: uuid.New()}.encode()
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "not base64", cursor: "not a cursor!"},
+		{name: "padded base64", cursor: valid + "=="},
+		{name: "truncated", cursor: valid[:len(valid)-4]},
+		{name: "not json", cursor: encode("created_at=yesterday")},
+		{name: "missing id", cursor: encode(`{"t":"2025-03-01T12:00:00Z"}`)},
+		{name: "nil id", cursor: encode(`{"t":"2025-03-01T12:00:00Z","id":"00000000-0000-0000-0000-000000000000"}`)},
+		{name: "missing time", cursor: encode(`{"id":"a1b2c3d4-e5f6-7777-8888-99990000aaaa"}`)},
+		{name: "malformed id", cursor: encode(`{"t":"2025-03-01T12:00:00Z","id":"a1b2"}`)},
+		{name: "malformed time", cursor: encode(`{"t":"yesterday","id":"a1b2c3d4-e5f6-7777-8888-99990000aaaa"}`)},
+		{name: "wrong type", cursor: encode(`{"t":"2025-03-01T12:00:00Z","id":"a1b2c3d4-e5f6-7777-8888-99990000aaaa","asc":"yes"}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if c, err := decodePageCursor(tt.cursor); err == nil {
+				t.Errorf("decodePageCursor(%q) = %+v, want an error", tt.cursor, c)
+			}
+		})
+	}
+}
+
+func TestParsePageRequest(t *testing.T) {
+	desc := pageCursor{CreatedAt: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC), ID: uuid.New()}.encode()
+	asc := pageCursor{CreatedAt: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC), ID: uuid.New(), Asc: true}.encode()
+
+	tests := []struct {
+		name       string
+		query      url.Values
+		wantErr    bool
+		wantSize   int32
+		wantAsc    bool
+		wantCursor bool
+	}{
+		{name: "defaults", query: url.Values{}, wantSize: defaultPageSize},
+		{name: "minimum limit", query: url.Values{"limit": {"1"}}, wantSize: 1},
+		{name: "maximum limit", query: url.Values{"limit": {"100"}}, wantSize: maxPageSize},
+		{name: "zero limit", query: url.Values{"limit": {"0"}}, wantErr: true},
+		{name: "limit over maximum", query: url.Values{"limit": {"101"}}, wantErr: true},
+		{name: "non-numeric limit", query: url.Values{"limit": {"ten"}}, wantErr: true},
+		{name: "ascending", query: url.Values{"sort": {"created_at"}}, wantSize: defaultPageSize, wantAsc: true},
+		{name: "descending", query: url.Values{"sort": {"-created_at"}}, wantSize: defaultPageSize},
+		{name: "unknown sort", query: url.Values{"sort": {"title"}}, wantErr: true},
+		{name: "descending cursor", query: url.Values{"cursor": {desc}}, wantSize: defaultPageSize, wantCursor: true},
+		{name: "ascending cursor", query: url.Values{"sort": {"created_at"}, "cursor": {asc}}, wantSize: defaultPageSize, wantAsc: true, wantCursor: true},
+		{name: "ascending cursor on descending listing", query: url.Values{"cursor": {asc}}, wantErr: true},
+		{name: "descending cursor on ascending listing", query: url.Values{"sort": {"created_at"}, "cursor": {desc}}, wantErr: true},
+		{name: "invalid cursor", query: url.Values{"cursor": {"garbage"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/api/v1/posts?"+tt.query.Encode(), nil)
+			page, err := parsePageRequest(r)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parsePageRequest = %+v, want an error", page)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePageRequest: %v", err)
+			}
+			if page.Size != tt.wantSize || page.SortAsc != tt.wantAsc || (page.After != nil) != tt.wantCursor {
+				t.Errorf("parsePageRequest = {Size:%d SortAsc:%v After:%v}, want {Size:%d SortAsc:%v cursor:%v}",
+					page.Size, page.SortAsc, page.After, tt.wantSize, tt.wantAsc, tt.wantCursor)
+			}
+		})
+	}
+}
+
+func TestTrimPage(t *testing.T) {
+	type row struct {
+		createdAt time.Time
+		id        uuid.UUID
+	}
+	base := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	rows := make([]row, 4)
+	for i := range rows {
+		rows[i] = row{createdAt: base.Add(-time.Duration(i) * time.Minute), id: uuid.New()}
+	}
+	key := func(r row) (pgtype.Timestamptz, pgtype.UUID) {
+		return pgtype.Timestamptz{Time: r.createdAt, Valid: true}, toPgtypeUUID(r.id)
+	}
+
+	page := pageRequest{Size: 3}
+	got, next := trimPage(page, rows, key)
+	if len(got) != 3 {
+		t.Fatalf("trimPage kept %d rows, want 3", len(got))
+	}
+	cursor, err := decodePageCursor(next)
+	if err != nil {
+		t.Fatalf("next cursor does not decode: %v", err)
+	}
+	if !cursor.CreatedAt.Equal(rows[2].createdAt) || cursor.ID != rows[2].id || cursor.Asc {
+		t.Errorf("next cursor = %+v, want the last row of the page", cursor)
+	}
+
+	got, next = trimPage(page, rows[:3], key)
+	if len(got) != 3 || next != "" {
+		t.Errorf("last page: kept %d rows with cursor %q, want 3 rows and no cursor", len(got), next)
+	}
+}
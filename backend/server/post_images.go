@@ -0,0 +1,472 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"time"
+
+	"github.com/dukunuu/hackathon_backend/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// PostImageDTO is an image of a post, in display order.
+// swagger:model PostImageDTO
+type PostImageDTO struct {
+	ID        uuid.UUID `json:"id" format:"uuid"`
+	URL       string    `json:"url" example:"http://localhost:9000/hackathon/post_images/9b2e.../1700000000_abc.jpg"`
+	Position  int32     `json:"position" example:"0"`
+	IsPrimary bool      `json:"is_primary" example:"true"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReorderPostImagesRequest lists every image of a post in its new order.
+// swagger:model ReorderPostImagesRequest
+type ReorderPostImagesRequest struct {
+	ImageIDs []uuid.UUID `json:"image_ids"`
+}
+
+func toPostImageDTOs(images []db.PostImage) []PostImageDTO {
+	dtos := make([]PostImageDTO, len(images))
+	for i, img := range images {
+		dtos[i] = PostImageDTO{
+			ID:        img.ID.Bytes,
+			URL:       img.ImageUrl,
+			Position:  img.Position,
+			IsPrimary: img.Position == 0,
+			CreatedAt: img.CreatedAt.Time,
+		}
+	}
+	return dtos
+}
+
+// readImageUpload reads an uploaded image and checks its size and type. On
+// failure it writes the error response and reports false.
+func readImageUpload(w http.ResponseWriter, fh *multipart.FileHeader, maxSize int64) ([]byte, string, bool) {
+	if fh.Size > maxSize {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Image '%s' too large. Maximum size is %dMB.", fh.Filename, maxSize/(1024*1024)))
+		return nil, "", false
+	}
+	f, err := fh.Open()
+	if err != nil {
+		slog.Error("Failed to open uploaded image", "filename", fh.Filename, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Error processing uploaded file: "+fh.Filename)
+		return nil, "", false
+	}
+	defer f.Close()
+
+	content, err := io.ReadAll(io.LimitReader(f, maxSize+1))
+	if err != nil {
+		slog.Error("Failed to read uploaded image", "filename", fh.Filename, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Could not read image content: "+fh.Filename)
+		return nil, "", false
+	}
+	if int64(len(content)) > maxSize {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Image '%s' too large. Maximum size is %dMB.", fh.Filename, maxSize/(1024*1024)))
+		return nil, "", false
+	}
+
+	contentType := http.DetectContentType(content)
+	if !allowedMimeTypes[contentType] {
+		slog.Warn("Invalid post image type uploaded", "contentType", contentType, "filename", fh.Filename)
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Invalid image type for '%s': %s. Allowed types: jpeg, png, gif, webp.", fh.Filename, contentType))
+		return nil, "", false
+	}
+	return content, contentType, true
+}
+
+// removeUploadedObjects deletes objects uploaded for a request that then
+// failed. It keeps going when the client has gone away, and only logs
+// failures; the request is failing already.
+func (s *Server) removeUploadedObjects(ctx context.Context, objectNames []string) {
+	ctx = context.WithoutCancel(ctx)
+	for _, objectName := range objectNames {
+		if err := s.filestore.DeleteObject(ctx, objectName); err != nil {
+			slog.Error("Failed to remove uploaded object after failed request", "error", err, "object", objectName)
+		}
+	}
+}
+
+// postForImageChange loads the post named in the path and checks that the
+// caller may change its images: its author or, when allowModerator is set, a
+// moderator, and that the If-Match header carries the post's current ETag.
+// On failure it writes the error response and reports false.
+func (s *Server) postForImageChange(w http.ResponseWriter, r *http.Request, allowModerator bool) (db.GetPostRow, bool) {
+	authUserID, err := getUserIDFromContext(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return db.GetPostRow{}, false
+	}
+
+	postID, err := uuid.Parse(r.PathValue("postId"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid post ID format")
+		return db.GetPostRow{}, false
+	}
+
+	post, err := s.db.GetPost(r.Context(), toPgtypeUUID(postID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Post not found")
+			return db.GetPostRow{}, false
+		}
+		slog.Error("Failed to get post for image change", "error", err, "postID", postID)
+		respondWithError(w, http.StatusInternalServerError, "Could not verify post ownership")
+		return db.GetPostRow{}, false
+	}
+
+	isAuthor := post.UserID.Valid && post.UserID.Bytes == authUserID.Bytes
+	if !isAuthor && !(allowModerator && hasPermission(r.Context(), PermPostsModerate)) {
+		respondWithError(w, http.StatusForbidden, "You are not authorized to change the images of this post")
+		return db.GetPostRow{}, false
+	}
+	if !checkIfMatch(w, r, versionETag(post.UpdatedAt)) {
+		return db.GetPostRow{}, false
+	}
+	return post, true
+}
+
+// syncPostPreview makes the post's preview follow its primary image. A
+// failure is only logged; the images themselves were changed.
+func (s *Server) syncPostPreview(r *http.Request, postID pgtype.UUID) {
+	if err := s.db.SyncPostPreview(r.Context(), postID); err != nil {
+		slog.Error("Failed to update post preview", "error", err, "postID", postID)
+	}
+}
+
+// respondWithPostImages answers with the post's images in display order.
+func (s *Server) respondWithPostImages(w http.ResponseWriter, r *http.Request, code int, postID pgtype.UUID) {
+	images, err := s.db.ListPostImages(r.Context(), postID)
+	if err != nil {
+		slog.Error("Failed to list post images", "error", err, "postID", postID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve post images")
+		return
+	}
+	respondWithJSON(w, code, toPostImageDTOs(images))
+}
+
+// handleListPostImages lists the images of a post.
+// @Summary List post images
+// @Description Returns the images of a post in display order. The first one is the primary image, which is also the post's preview.
+// @Tags Posts
+// @Produce json
+// @Param postId path string true "Post ID" format(uuid)
+// @Success 200 {array} PostImageDTO "Images in display order"
+// @Failure 400 {object} ErrorResponse "Invalid post ID format"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 500 {object} ErrorResponse "Failed to retrieve post images"
+// @Security BearerAuth
+// @Router /posts/{postId}/images [get]
+func (s *Server) handleListPostImages(w http.ResponseWriter, r *http.Request) {
+	postID, err := uuid.Parse(r.PathValue("postId"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid post ID format")
+		return
+	}
+	s.respondWithPostImages(w, r, http.StatusOK, toPgtypeUUID(postID))
+}
+
+// handleAddPostImages uploads more images to an existing post.
+// @Summary Add post images
+// @Description Uploads images and appends them to the post. A post has at most 5 images.
+// @Description When the post had none, the first new image becomes its primary image and preview. Only the author can add images.
+// @Description The If-Match header must carry the post's current ETag so concurrent changes are not overwritten.
+// @Tags Posts
+// @Accept multipart/form-data
+// @Produce json
+// @Param postId path string true "Post ID" format(uuid)
+// @Param If-Match header string true "ETag of the post from GET /posts/{postId}"
+// @Param postImages formData file true "Image files (max 5MB each, types: jpeg, png, gif, webp)"
+// @Success 201 {array} PostImageDTO "All images of the post in display order"
+// @Failure 400 {object} ErrorResponse "No images, too many images, or an invalid image"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Not the author of the post"
+// @Failure 404 {object} ErrorResponse "Post not found"
+// @Failure 409 {object} ErrorResponse "Images were changed concurrently"
+// @Failure 412 {object} ErrorResponse "Post was modified since the ETag was issued"
+// @Failure 428 {object} ErrorResponse "If-Match header missing"
+// @Failure 500 {object} ErrorResponse "Failed to add images"
+// @Security BearerAuth
+// @Router /posts/{postId}/images [post]
+func (s *Server) handleAddPostImages(w http.ResponseWriter, r *http.Request) {
+	post, ok := s.postForImageChange(w, r, false)
+	if !ok {
+		return
+	}
+
+	if err := r.ParseMultipartForm(maxPostRequestSize); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Could not parse form: "+err.Error())
+		return
+	}
+	formFiles := r.MultipartForm.File["postImages"]
+	if len(formFiles) == 0 {
+		respondWithError(w, http.StatusBadRequest, "Missing 'postImages' form field.")
+		return
+	}
+
+	existing, err := s.db.ListPostImages(r.Context(), post.ID)
+	if err != nil {
+		slog.Error("Failed to list post images", "error", err, "postID", post.ID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to add images")
+		return
+	}
+	if len(existing)+len(formFiles) > maxPostImages {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Too many images. A post can have at most %d images and already has %d.", maxPostImages, len(existing)))
+		return
+	}
+
+	// Validate everything before uploading anything.
+	contents := make([][]byte, len(formFiles))
+	contentTypes := make([]string, len(formFiles))
+	for i, fh := range formFiles {
+		if contents[i], contentTypes[i], ok = readImageUpload(w, fh, maxPostImageFileSize); !ok {
+			return
+		}
+	}
+
+	imageURLs := make([]string, 0, len(formFiles))
+	objectNames := make([]string, 0, len(formFiles))
+	for i, fh := range formFiles {
+		imageURL, objectName, err := s.filestore.UploadPostImage(r.Context(), contents[i], fh.Filename, contentTypes[i], post.ID.Bytes)
+		if err != nil {
+			slog.Error("Failed to upload post image via filestore", "filename", fh.Filename, "error", err)
+			s.removeUploadedObjects(r.Context(), objectNames)
+			respondWithError(w, http.StatusInternalServerError, "Could not upload image '"+fh.Filename+"'")
+			return
+		}
+		imageURLs = append(imageURLs, imageURL)
+		objectNames = append(objectNames, objectName)
+	}
+
+	err = s.db.ExecTx(r.Context(), func(q *db.Queries) error {
+		for _, imageURL := range imageURLs {
+			if _, err := q.CreatePostImage(r.Context(), db.CreatePostImageParams{PostID: post.ID, ImageUrl: imageURL}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.removeUploadedObjects(r.Context(), objectNames)
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // Unique violation on the position
+			respondWithError(w, http.StatusConflict, "Images were changed concurrently; reload and try again")
+			return
+		}
+		slog.Error("Failed to create post_image records in DB", "postID", post.ID, "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to add images")
+		return
+	}
+
+	if len(existing) == 0 {
+		s.syncPostPreview(r, post.ID)
+	}
+	s.respondWithPostImages(w, r, http.StatusCreated, post.ID)
+}
+
+// handleDeletePostImage removes an image from a post.
+// @Summary Delete post image
+// @Description Removes the image and its stored file. The images after it move up; when the primary image is removed the next
+// @Description one becomes primary and the post's preview. The author or a moderator (posts:moderate) can delete images.
+// @Description The If-Match header must carry the post's current ETag so concurrent changes are not overwritten.
+// @Tags Posts
+// @Produce json
+// @Param postId path string true "Post ID" format(uuid)
+// @Param imageId path string true "Image ID" format(uuid)
+// @Param If-Match header string true "ETag of the post from GET /posts/{postId}"
+// @Success 200 {array} PostImageDTO "Remaining images in display order"
+// @Failure 400 {object} ErrorResponse "Invalid post or image ID format"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Not the author of the post or a moderator"
+// @Failure 404 {object} ErrorResponse "Post or image not found"
+// @Failure 412 {object} ErrorResponse "Post was modified since the ETag was issued"
+// @Failure 428 {object} ErrorResponse "If-Match header missing"
+// @Failure 500 {object} ErrorResponse "Failed to delete image"
+// @Security BearerAuth
+// @Router /posts/{postId}/images/{imageId} [delete]
+func (s *Server) handleDeletePostImage(w http.ResponseWriter, r *http.Request) {
+	imageID, err := uuid.Parse(r.PathValue("imageId"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid image ID format")
+		return
+	}
+	post, ok := s.postForImageChange(w, r, true)
+	if !ok {
+		return
+	}
+
+	deleted, err := s.db.DeletePostImage(r.Context(), db.DeletePostImageParams{ID: toPgtypeUUID(imageID), PostID: post.ID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Image not found")
+			return
+		}
+		slog.Error("Failed to delete post image", "error", err, "postID", post.ID, "imageID", imageID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to delete image")
+		return
+	}
+
+	// The row is gone either way; a leftover object only costs storage.
+	if objectName, ok := s.filestore.ObjectNameFromURL(deleted.ImageUrl); ok {
+		if err := s.filestore.DeleteObject(r.Context(), objectName); err != nil {
+			slog.Error("Failed to remove deleted post image from storage", "error", err, "object", objectName)
+		}
+	}
+
+	if deleted.Position == 0 {
+		s.syncPostPreview(r, post.ID)
+	}
+	s.respondWithPostImages(w, r, http.StatusOK, post.ID)
+}
+
+// reorderPostImages puts the post's images in the given order, which must
+// name each of them exactly once, and updates the preview when the primary
+// image changed. On failure it writes the error response and reports false.
+func (s *Server) reorderPostImages(w http.ResponseWriter, r *http.Request, postID pgtype.UUID, current []db.PostImage, order []uuid.UUID) bool {
+	if len(order) != len(current) {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("image_ids must list all %d images of the post", len(current)))
+		return false
+	}
+	known := make(map[uuid.UUID]bool, len(current))
+	for _, img := range current {
+		known[img.ID.Bytes] = true
+	}
+	ids := make([]pgtype.UUID, len(order))
+	for i, id := range order {
+		if !known[id] {
+			respondWithError(w, http.StatusBadRequest, "image_ids must list each image of the post exactly once")
+			return false
+		}
+		delete(known, id)
+		ids[i] = toPgtypeUUID(id)
+	}
+
+	if _, err := s.db.ReorderPostImages(r.Context(), db.ReorderPostImagesParams{ImageIds: ids, PostID: postID}); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			respondWithError(w, http.StatusConflict, "Images were changed concurrently; reload and try again")
+			return false
+		}
+		slog.Error("Failed to reorder post images", "error", err, "postID", postID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to reorder images")
+		return false
+	}
+	if len(order) > 0 && order[0] != uuid.UUID(current[0].ID.Bytes) {
+		s.syncPostPreview(r, postID)
+	}
+	return true
+}
+
+// handleReorderPostImages sets the display order of a post's images.
+// @Summary Reorder post images
+// @Description Sets the display order of the post's images. 'image_ids' must list every image of the post exactly once;
+// @Description the first one becomes the primary image and the post's preview. Only the author can reorder images.
+// @Description The If-Match header must carry the post's current ETag so concurrent changes are not overwritten.
+// @Tags Posts
+// @Accept json
+// @Produce json
+// @Param postId path string true "Post ID" format(uuid)
+// @Param If-Match header string true "ETag of the post from GET /posts/{postId}"
+// @Param order body ReorderPostImagesRequest true "Every image ID in the new order"
+// @Success 200 {array} PostImageDTO "Images in the new order"
+// @Failure 400 {object} ErrorResponse "Invalid request payload or image list"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Not the author of the post"
+// @Failure 404 {object} ErrorResponse "Post not found"
+// @Failure 409 {object} ErrorResponse "Images were changed concurrently"
+// @Failure 412 {object} ErrorResponse "Post was modified since the ETag was issued"
+// @Failure 428 {object} ErrorResponse "If-Match header missing"
+// @Failure 500 {object} ErrorResponse "Failed to reorder images"
+// @Security BearerAuth
+// @Router /posts/{postId}/images/order [put]
+func (s *Server) handleReorderPostImages(w http.ResponseWriter, r *http.Request) {
+	post, ok := s.postForImageChange(w, r, false)
+	if !ok {
+		return
+	}
+
+	var req ReorderPostImagesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload: "+err.Error())
+		return
+	}
+	defer r.Body.Close()
+
+	current, err := s.db.ListPostImages(r.Context(), post.ID)
+	if err != nil {
+		slog.Error("Failed to list post images", "error", err, "postID", post.ID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to reorder images")
+		return
+	}
+	if !s.reorderPostImages(w, r, post.ID, current, req.ImageIDs) {
+		return
+	}
+	s.respondWithPostImages(w, r, http.StatusOK, post.ID)
+}
+
+// handleSetPrimaryPostImage makes an image the primary image of its post.
+// @Summary Set primary post image
+// @Description Moves the image to the front of the post's images, making it the primary image and the post's preview.
+// @Description The other images keep their relative order. Only the author can choose the primary image.
+// @Description The If-Match header must carry the post's current ETag so concurrent changes are not overwritten.
+// @Tags Posts
+// @Produce json
+// @Param postId path string true "Post ID" format(uuid)
+// @Param imageId path string true "Image ID" format(uuid)
+// @Param If-Match header string true "ETag of the post from GET /posts/{postId}"
+// @Success 200 {array} PostImageDTO "Images in the new order"
+// @Failure 400 {object} ErrorResponse "Invalid post or image ID format"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Not the author of the post"
+// @Failure 404 {object} ErrorResponse "Post or image not found"
+// @Failure 409 {object} ErrorResponse "Images were changed concurrently"
+// @Failure 412 {object} ErrorResponse "Post was modified since the ETag was issued"
+// @Failure 428 {object} ErrorResponse "If-Match header missing"
+// @Failure 500 {object} ErrorResponse "Failed to reorder images"
+// @Security BearerAuth
+// @Router /posts/{postId}/images/{imageId}/primary [post]
+func (s *Server) handleSetPrimaryPostImage(w http.ResponseWriter, r *http.Request) {
+	imageID, err := uuid.Parse(r.PathValue("imageId"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid image ID format")
+		return
+	}
+	post, ok := s.postForImageChange(w, r, false)
+	if !ok {
+		return
+	}
+
+	current, err := s.db.ListPostImages(r.Context(), post.ID)
+	if err != nil {
+		slog.Error("Failed to list post images", "error", err, "postID", post.ID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to reorder images")
+		return
+	}
+	order := []uuid.UUID{imageID}
+	found := false
+	for _, img := range current {
+		if img.ID.Bytes == imageID {
+			found = true
+			continue
+		}
+		order = append(order, img.ID.Bytes)
+	}
+	if !found {
+		respondWithError(w, http.StatusNotFound, "Image not found")
+		return
+	}
+
+	if !s.reorderPostImages(w, r, post.ID, current, order) {
+		return
+	}
+	s.respondWithPostImages(w, r, http.StatusOK, post.ID)
+}
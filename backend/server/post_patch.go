@@ -0,0 +1,269 @@
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"unicode/utf8"
+
+	"github.com/dukunuu/hackathon_backend/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const maxPostTitleLength = 150
+
+// patchField is one member of a JSON Merge Patch (RFC 7396). Set is false
+// when the key is absent and Null is true when it was explicitly null.
+type patchField[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+func (f *patchField[T]) UnmarshalJSON(b []byte) error {
+	f.Set = true
+	if string(b) == "null" {
+		f.Null = true
+		return nil
+	}
+	return json.Unmarshal(b, &f.Value)
+}
+
+// PatchPostRequest is the JSON Merge Patch body for PATCH /posts/{postId}.
+// Absent keys are left unchanged; null clears preview_url, category_id,
+// address_text and, given for both coordinates, the location.
+// swagger:model PatchPostRequest
+type PatchPostRequest struct {
+	Title         patchField[string]    `json:"title" swaggertype:"string" example:"Urgent: Park Cleanup Drive"`
+	Description   patchField[string]    `json:"description" swaggertype:"string" example:"The local park needs volunteers urgently."`
+	Status        patchField[string]    `json:"status" swaggertype:"string" example:"Шийдвэрлэгдэж байгаа"`
+	Priority      patchField[string]    `json:"priority" swaggertype:"string" example:"өндөр"`
+	PostType      patchField[string]    `json:"post_type" swaggertype:"string" example:"хандив"`
+	MaxVolunteers patchField[int32]     `json:"max_volunteers" swaggertype:"integer" example:"15"`
+	PreviewURL    patchField[string]    `json:"preview_url" swaggertype:"string" extensions:"x-nullable" example:"http://example.com/new_image.jpg"`
+	CategoryID    patchField[uuid.UUID] `json:"category_id" swaggertype:"string" format:"uuid" extensions:"x-nullable"`
+	LocationLat   patchField[float64]   `json:"location_lat" swaggertype:"number" extensions:"x-nullable" example:"47.9200"`
+	LocationLng   patchField[float64]   `json:"location_lng" swaggertype:"number" extensions:"x-nullable" example:"106.9250"`
+	AddressText   patchField[string]    `json:"address_text" swaggertype:"string" extensions:"x-nullable" example:"Sukhbaatar Square, Ulaanbaatar"`
+}
+
+// clearableText turns an optional text member into its column value; null
+// and the empty string both clear it.
+func clearableText(f patchField[string]) pgtype.Text {
+	if f.Null || f.Value == "" {
+		return pgtype.Text{}
+	}
+	return pgtype.Text{String: f.Value, Valid: true}
+}
+
+// patchPostParams validates a merge patch against the post it applies to.
+// The error message is meant for a 400 response.
+func patchPostParams(req PatchPostRequest, post db.GetPostRow) (db.PatchPostParams, error) {
+	params := db.PatchPostParams{ID: post.ID}
+
+	if req.Title.Set {
+		n := utf8.RuneCountInString(req.Title.Value)
+		if req.Title.Null || n == 0 {
+			return params, fmt.Errorf("title cannot be empty")
+		}
+		if n > maxPostTitleLength {
+			return params, fmt.Errorf("title must be at most %d characters", maxPostTitleLength)
+		}
+		params.Title = pgtype.Text{String: req.Title.Value, Valid: true}
+	}
+	if req.Description.Set {
+		if req.Description.Null || req.Description.Value == "" {
+			return params, fmt.Errorf("description cannot be empty")
+		}
+		params.Description = pgtype.Text{String: req.Description.Value, Valid: true}
+	}
+	if req.Status.Set && (req.Status.Null || !isValidPostStatus(db.PostStatus(req.Status.Value))) {
+		return params, fmt.Errorf("invalid status")
+	}
+	if req.Priority.Set {
+		if req.Priority.Null || !isValidPostPriority(db.PostPriority(req.Priority.Value)) {
+			return params, fmt.Errorf("invalid priority")
+		}
+		params.Priority = db.NullPostPriority{PostPriority: db.PostPriority(req.Priority.Value), Valid: true}
+	}
+	if req.PostType.Set {
+		if req.PostType.Null || !isValidPostType(db.PostType(req.PostType.Value)) {
+			return params, fmt.Errorf("invalid post_type")
+		}
+		params.PostType = db.NullPostType{PostType: db.PostType(req.PostType.Value), Valid: true}
+	}
+	if req.MaxVolunteers.Set {
+		if req.MaxVolunteers.Null || req.MaxVolunteers.Value < 0 {
+			return params, fmt.Errorf("max_volunteers must be a non-negative number")
+		}
+		if req.MaxVolunteers.Value < post.CurrentVolunteers {
+			return params, fmt.Errorf("max_volunteers cannot be lower than the %d volunteers already on the post", post.CurrentVolunteers)
+		}
+		params.MaxVolunteers = pgtype.Int4{Int32: req.MaxVolunteers.Value, Valid: true}
+	}
+
+	if req.PreviewURL.Set {
+		params.SetPreviewUrl = true
+		params.PreviewUrl = clearableText(req.PreviewURL)
+	}
+	if req.CategoryID.Set {
+		params.SetCategoryID = true
+		if !req.CategoryID.Null {
+			params.CategoryID = toPgtypeUUID(req.CategoryID.Value)
+		}
+	}
+	if req.AddressText.Set {
+		params.SetAddressText = true
+		params.AddressText = clearableText(req.AddressText)
+	}
+
+	// A location is only ever stored whole, so both coordinates change together.
+	if req.LocationLat.Set || req.LocationLng.Set {
+		if req.LocationLat.Set != req.LocationLng.Set || req.LocationLat.Null != req.LocationLng.Null {
+			return params, fmt.Errorf("location_lat and location_lng must be changed together")
+		}
+		params.SetLocation = true
+		if !req.LocationLat.Null {
+			lat, lng, err := postLocation(req.LocationLat.Value, req.LocationLng.Value)
+			if err != nil {
+				return params, fmt.Errorf("invalid location: %w", err)
+			}
+			params.LocationLat, params.LocationLng = lat, lng
+		}
+	}
+	return params, nil
+}
+
+// handlePatchPost partially updates a post.
+// @Summary Partially update post
+// @Description Applies a JSON Merge Patch (RFC 7396) to a post. Only the owner of the post can update it.
+// @Description Keys that are absent stay unchanged. An explicit null clears preview_url, category_id and address_text,
+// @Description and clears the location when given for both location_lat and location_lng; the other fields cannot be null.
+// @Description A changed status must be an allowed transition, see POST /posts/{postId}/status.
+// @Description A complaint cannot be changed to another post_type.
+// @Description The If-Match header must carry the post's current ETag so concurrent edits are not overwritten.
+// @Tags Posts
+// @Accept application/merge-patch+json
+// @Accept json
+// @Produce json
+// @Param postId path string true "Post ID" format(uuid)
+// @Param If-Match header string true "ETag of the post from GET /posts/{postId}"
+// @Param postPatch body PatchPostRequest true "Fields to change"
+// @Success 200 {object} PostResponseDTO "Successfully updated post"
+// @Header 200 {string} ETag "New version of the post"
+// @Failure 400 {object} ErrorResponse "Invalid patch document, field value or post ID format"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Forbidden - not authorized to update this post"
+// @Failure 404 {object} ErrorResponse "Post not found"
+// @Failure 409 {object} ErrorResponse "Status transition or post_type change not allowed"
+// @Failure 412 {object} ErrorResponse "Post was modified since the ETag was issued"
+// @Failure 428 {object} ErrorResponse "If-Match header missing"
+// @Failure 500 {object} ErrorResponse "Failed to update post"
+// @Security BearerAuth
+// @Router /posts/{postId} [patch]
+func (s *Server) handlePatchPost(w http.ResponseWriter, r *http.Request) {
+	authUserID, err := getUserIDFromContext(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	postID, err := uuid.Parse(r.PathValue("postId"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid post ID format")
+		return
+	}
+
+	existingPost, err := s.db.GetPost(r.Context(), toPgtypeUUID(postID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Post not found")
+			return
+		}
+		slog.Error("Failed to get post for patch authorization", "error", err, "postID", postID)
+		respondWithError(w, http.StatusInternalServerError, "Could not verify post ownership for update")
+		return
+	}
+
+	if existingPost.UserID.Bytes != authUserID.Bytes {
+		respondWithError(w, http.StatusForbidden, "You are not authorized to update this post")
+		return
+	}
+	if !checkIfMatch(w, r, versionETag(existingPost.UpdatedAt)) {
+		return
+	}
+
+	var req PatchPostRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid patch document: "+err.Error())
+		return
+	}
+	defer r.Body.Close()
+
+	params, err := patchPostParams(req, existingPost)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if params.PostType.Valid {
+		if err := checkPostTypeChange(existingPost, params.PostType.PostType); err != nil {
+			respondWithError(w, http.StatusConflict, err.Error())
+			return
+		}
+	}
+
+	if params.SetCategoryID && params.CategoryID.Valid {
+		if _, err := s.db.GetCategoryName(r.Context(), params.CategoryID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+				respondWithError(w, http.StatusBadRequest, "Category does not exist")
+				return
+			}
+			slog.Error("Failed to get category for post patch", "error", err, "postID", postID)
+			respondWithError(w, http.StatusInternalServerError, "Failed to update post")
+			return
+		}
+	}
+
+	newStatus := db.PostStatus(req.Status.Value)
+	changeStatus := req.Status.Set && newStatus != existingPost.Status
+	if changeStatus {
+		if err := checkPostTransition(r.Context(), existingPost, authUserID, newStatus); err != nil {
+			respondWithStatusChangeError(w, err, postID)
+			return
+		}
+	}
+
+	params.UpdatedAt = existingPost.UpdatedAt
+	err = s.db.ExecTx(r.Context(), func(q *db.Queries) error {
+		if _, err := q.PatchPost(r.Context(), params); err != nil {
+			return err
+		}
+		if changeStatus {
+			_, err := changePostStatus(r.Context(), q, existingPost, authUserID, newStatus, "")
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows):
+			respondWithError(w, http.StatusPreconditionFailed, errResourceModified)
+		case isVolunteerCapacityViolation(err):
+			respondWithError(w, http.StatusConflict, errVolunteerCapacityBelowApproved)
+		case isStatusChangeRefusal(err):
+			respondWithStatusChangeError(w, err, postID)
+		default:
+			slog.Error("Failed to patch post", "error", err, "postID", postID)
+			respondWithError(w, http.StatusInternalServerError, "Failed to update post")
+		}
+		return
+	}
+	s.respondWithPost(w, r, postID)
+}
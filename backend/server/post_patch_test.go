@@ -0,0 +1,162 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/dukunuu/hackathon_backend/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestPatchFieldDecoding(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want patchField[string]
+	}{
+		{name: "absent", body: `{}`, want: patchField[string]{}},
+		{name: "null", body: `{"address_text": null}`, want: patchField[string]{Set: true, Null: true}},
+		{name: "empty string", body: `{"address_text": ""}`, want: patchField[string]{Set: true}},
+		{name: "value", body: `{"address_text": "Sukhbaatar Square"}`, want: patchField[string]{Set: true, Value: "Sukhbaatar Square"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req PatchPostRequest
+			if err := json.Unmarshal([]byte(tt.body), &req); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if req.AddressText != tt.want {
+				t.Errorf("AddressText = %+v, want %+v", req.AddressText, tt.want)
+			}
+		})
+	}
+
+	var req PatchPostRequest
+	if err := json.Unmarshal([]byte(`{"max_volunteers": "ten"}`), &req); err == nil {
+		t.Error("Unmarshal accepted a string for max_volunteers")
+	}
+}
+
+func TestPatchPostParams(t *testing.T) {
+	post := db.GetPostRow{
+		ID:                pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		CurrentVolunteers: 3,
+	}
+	categoryID := uuid.New()
+
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+		check   func(t *testing.T, p db.PatchPostParams)
+	}{
+		{
+			name: "empty patch changes nothing",
+			body: `{}`,
+			check: func(t *testing.T, p db.PatchPostParams) {
+				if p.Title.Valid || p.Description.Valid || p.Priority.Valid || p.PostType.Valid || p.MaxVolunteers.Valid ||
+					p.SetPreviewUrl || p.SetCategoryID || p.SetAddressText || p.SetLocation {
+					t.Errorf("params = %+v, want no changes", p)
+				}
+			},
+		},
+		{
+			name: "category set",
+			body: `{"category_id": "` + categoryID.String() + `"}`,
+			check: func(t *testing.T, p db.PatchPostParams) {
+				if !p.SetCategoryID || !p.CategoryID.Valid || uuid.UUID(p.CategoryID.Bytes) != categoryID {
+					t.Errorf("SetCategoryID = %v, CategoryID = %+v; want %s", p.SetCategoryID, p.CategoryID, categoryID)
+				}
+			},
+		},
+		{
+			name: "category null clears it",
+			body: `{"category_id": null}`,
+			check: func(t *testing.T, p db.PatchPostParams) {
+				if !p.SetCategoryID || p.CategoryID.Valid {
+					t.Errorf("SetCategoryID = %v, CategoryID = %+v; want a cleared category", p.SetCategoryID, p.CategoryID)
+				}
+			},
+		},
+		{
+			name: "address null clears it",
+			body: `{"address_text": null}`,
+			check: func(t *testing.T, p db.PatchPostParams) {
+				if !p.SetAddressText || p.AddressText.Valid {
+					t.Errorf("SetAddressText = %v, AddressText = %+v; want a cleared address", p.SetAddressText, p.AddressText)
+				}
+			},
+		},
+		{
+			name: "preview value",
+			body: `{"preview_url": "http://example.com/a.jpg"}`,
+			check: func(t *testing.T, p db.PatchPostParams) {
+				if !p.SetPreviewUrl || p.PreviewUrl.String != "http://example.com/a.jpg" {
+					t.Errorf("SetPreviewUrl = %v, PreviewUrl = %+v", p.SetPreviewUrl, p.PreviewUrl)
+				}
+			},
+		},
+		{
+			name: "location null clears it",
+			body: `{"location_lat": null, "location_lng": null}`,
+			check: func(t *testing.T, p db.PatchPostParams) {
+				if !p.SetLocation || p.LocationLat.Valid || p.LocationLng.Valid {
+					t.Errorf("SetLocation = %v, location = %+v,%+v; want a cleared location", p.SetLocation, p.LocationLat, p.LocationLng)
+				}
+			},
+		},
+		{
+			name: "location value",
+			body: `{"location_lat": 47.92, "location_lng": 106.925}`,
+			check: func(t *testing.T, p db.PatchPostParams) {
+				if !p.SetLocation || p.LocationLat.Float64 != 47.92 || p.LocationLng.Float64 != 106.925 {
+					t.Errorf("SetLocation = %v, location = %+v,%+v", p.SetLocation, p.LocationLat, p.LocationLng)
+				}
+			},
+		},
+		{
+			name: "title value",
+			body: `{"title": "New title"}`,
+			check: func(t *testing.T, p db.PatchPostParams) {
+				if p.Title.String != "New title" || !p.Title.Valid {
+					t.Errorf("Title = %+v", p.Title)
+				}
+			},
+		},
+		{name: "title null", body: `{"title": null}`, wantErr: true},
+		{name: "title empty", body: `{"title": ""}`, wantErr: true},
+		{name: "description null", body: `{"description": null}`, wantErr: true},
+		{name: "status null", body: `{"status": null}`, wantErr: true},
+		{name: "priority null", body: `{"priority": null}`, wantErr: true},
+		{name: "post_type null", body: `{"post_type": null}`, wantErr: true},
+		{name: "max_volunteers null", body: `{"max_volunteers": null}`, wantErr: true},
+		{name: "max_volunteers below current", body: `{"max_volunteers": 2}`, wantErr: true},
+		{name: "only one coordinate", body: `{"location_lat": 47.92}`, wantErr: true},
+		{name: "one coordinate null", body: `{"location_lat": null, "location_lng": 106.925}`, wantErr: true},
+		{name: "latitude out of range", body: `{"location_lat": 91, "location_lng": 106.925}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req PatchPostRequest
+			if err := json.Unmarshal([]byte(tt.body), &req); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			params, err := patchPostParams(req, post)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("patchPostParams error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if params.ID != post.ID {
+				t.Errorf("ID = %+v, want %+v", params.ID, post.ID)
+			}
+			if tt.check != nil {
+				tt.check(t, params)
+			}
+		})
+	}
+}
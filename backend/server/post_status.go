@@ -0,0 +1,329 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dukunuu/hackathon_backend/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// The post statuses by role in the workflow.
+const (
+	postStatusPending    = db.PostStatusValue4 // Хүлээгдэж байгаа
+	postStatusInProgress = db.PostStatusValue3 // Шийдвэрлэгдэж байгаа
+	postStatusPaused     = db.PostStatusValue1 // Түр завсарласан
+	postStatusCancelled  = db.PostStatusValue2 // Цуцлагдсан
+	postStatusResolved   = db.PostStatusValue0 // Шийдвэрлэгдсэн
+
+	postTypeComplaint = db.PostTypeValue1 // гомдол
+)
+
+const maxStatusCommentLength = 1000
+
+// postActor is a set of parties that may make a status transition.
+type postActor uint8
+
+const (
+	actorAuthor postActor = 1 << iota
+	actorModerator
+)
+
+// postStatusTransitions lists, for every status, the statuses a post may move
+// to next and who may move it there. Anything not listed is rejected.
+var postStatusTransitions = map[db.PostStatus]map[db.PostStatus]postActor{
+	postStatusPending: {
+		postStatusInProgress: actorAuthor | actorModerator,
+		postStatusCancelled:  actorAuthor | actorModerator,
+	},
+	postStatusInProgress: {
+		postStatusPaused:    actorAuthor | actorModerator,
+		postStatusResolved:  actorAuthor | actorModerator,
+		postStatusCancelled: actorAuthor | actorModerator,
+	},
+	postStatusPaused: {
+		postStatusInProgress: actorAuthor | actorModerator,
+		postStatusCancelled:  actorAuthor | actorModerator,
+	},
+	// Reopening a closed post is up to moderators.
+	postStatusResolved: {
+		postStatusInProgress: actorModerator,
+	},
+	postStatusCancelled: {
+		postStatusPending: actorModerator,
+	},
+}
+
+var (
+	errInvalidPostTransition   = errors.New("post cannot move to that status from its current status")
+	errPostTransitionForbidden = errors.New("you are not allowed to move this post to that status")
+	errPostStatusChanged       = errors.New("post status was changed by someone else, reload and try again")
+	// A complaint keeps its type, or its author could turn it into something
+	// else and resolve it without a moderator.
+	errComplaintTypeChange = errors.New("a complaint cannot be changed to another post type")
+)
+
+// postTransitionActors returns who may move a post of the given type from one
+// status to another, and false if the workflow does not allow it at all.
+func postTransitionActors(postType db.PostType, from, to db.PostStatus) (postActor, bool) {
+	actors, ok := postStatusTransitions[from][to]
+	if !ok {
+		return 0, false
+	}
+	// Only moderators decide that a complaint has been dealt with.
+	if postType == postTypeComplaint && to == postStatusResolved {
+		actors &= actorModerator
+	}
+	return actors, true
+}
+
+// checkPostTypeChange reports whether a post may be given postType.
+func checkPostTypeChange(post db.GetPostRow, postType db.PostType) error {
+	if post.PostType == postTypeComplaint && postType != postTypeComplaint {
+		return errComplaintTypeChange
+	}
+	return nil
+}
+
+// callerPostActor reports in which capacities the caller acts on a post.
+func callerPostActor(ctx context.Context, authorID pgtype.UUID, callerID pgtype.UUID) postActor {
+	var actor postActor
+	if authorID.Valid && authorID.Bytes == callerID.Bytes {
+		actor |= actorAuthor
+	}
+	if hasPermission(ctx, PermPostsModerate) {
+		actor |= actorModerator
+	}
+	return actor
+}
+
+// checkPostTransition reports whether the caller may move a post to status,
+// failing with errInvalidPostTransition or errPostTransitionForbidden.
+func checkPostTransition(ctx context.Context, post db.GetPostRow, callerID pgtype.UUID, status db.PostStatus) error {
+	actors, ok := postTransitionActors(post.PostType, post.Status, status)
+	if !ok {
+		return errInvalidPostTransition
+	}
+	if actors&callerPostActor(ctx, post.UserID, callerID) == 0 {
+		return errPostTransitionForbidden
+	}
+	return nil
+}
+
+// changePostStatus moves a post to status on behalf of the caller and
+// records the transition. It fails with the errors of checkPostTransition,
+// or errPostStatusChanged when the post moved on in the meantime. Updates
+// that change other fields as well pass the transaction's q.
+func changePostStatus(ctx context.Context, q *db.Queries, post db.GetPostRow, callerID pgtype.UUID, status db.PostStatus, comment string) (db.PostStatusHistory, error) {
+	if err := checkPostTransition(ctx, post, callerID, status); err != nil {
+		return db.PostStatusHistory{}, err
+	}
+
+	entry, err := q.TransitionPostStatus(ctx, db.TransitionPostStatusParams{
+		ToStatus:   status,
+		PostID:     post.ID,
+		FromStatus: post.Status,
+		ChangedBy:  callerID,
+		Comment:    pgtype.Text{String: comment, Valid: comment != ""},
+	})
+	if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+		return db.PostStatusHistory{}, errPostStatusChanged
+	}
+	return entry, err
+}
+
+// isStatusChangeRefusal reports whether err is changePostStatus turning the
+// change down rather than failing.
+func isStatusChangeRefusal(err error) bool {
+	return errors.Is(err, errInvalidPostTransition) || errors.Is(err, errPostTransitionForbidden) || errors.Is(err, errPostStatusChanged)
+}
+
+// respondWithStatusChangeError answers a failed changePostStatus.
+func respondWithStatusChangeError(w http.ResponseWriter, err error, postID uuid.UUID) {
+	switch {
+	case errors.Is(err, errInvalidPostTransition), errors.Is(err, errPostStatusChanged):
+		respondWithError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, errPostTransitionForbidden):
+		respondWithError(w, http.StatusForbidden, err.Error())
+	default:
+		slog.Error("Failed to change post status", "error", err, "postID", postID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to change post status")
+	}
+}
+
+// ChangePostStatusRequest defines the JSON body for moving a post to another status.
+// swagger:model ChangePostStatusRequest
+type ChangePostStatusRequest struct {
+	Status  string `json:"status" example:"Шийдвэрлэгдсэн"`
+	Comment string `json:"comment,omitempty" example:"Хогийг ачиж дууслаа"`
+}
+
+// PostStatusHistoryDTO is one status transition of a post.
+// swagger:model PostStatusHistoryDTO
+type PostStatusHistoryDTO struct {
+	ID            uuid.UUID  `json:"id" format:"uuid"`
+	PostID        uuid.UUID  `json:"post_id" format:"uuid"`
+	FromStatus    string     `json:"from_status,omitempty" example:"Шийдвэрлэгдэж байгаа"`
+	ToStatus      string     `json:"to_status" example:"Шийдвэрлэгдсэн"`
+	ChangedBy     *uuid.UUID `json:"changed_by,omitempty" format:"uuid"`
+	ChangedByName string     `json:"changed_by_name,omitempty" example:"Бат Дорж"`
+	Comment       string     `json:"comment,omitempty" example:"Хогийг ачиж дууслаа"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+func toPostStatusHistoryDTO(h db.PostStatusHistory) PostStatusHistoryDTO {
+	dto := PostStatusHistoryDTO{
+		ID:         h.ID.Bytes,
+		PostID:     h.PostID.Bytes,
+		FromStatus: string(h.FromStatus.PostStatus),
+		ToStatus:   string(h.ToStatus),
+		Comment:    h.Comment.String,
+		CreatedAt:  h.CreatedAt.Time,
+	}
+	if h.ChangedBy.Valid {
+		changedBy := uuid.UUID(h.ChangedBy.Bytes)
+		dto.ChangedBy = &changedBy
+	}
+	return dto
+}
+
+// handleChangePostStatus moves a post through its status workflow.
+// @Summary Change post status
+// @Description Moves a post to another status and records the transition in its history.
+// @Description Pending posts can be started or cancelled, posts in progress can be paused, resolved or cancelled,
+// @Description and paused posts can be resumed or cancelled, by the author or a moderator (posts:moderate).
+// @Description Only moderators can resolve a complaint or reopen a resolved or cancelled post.
+// @Description The If-Match header must carry the post's current ETag so concurrent changes are not overwritten.
+// @Tags Posts
+// @Accept json
+// @Produce json
+// @Param postId path string true "Post ID" format(uuid)
+// @Param If-Match header string true "ETag of the post from GET /posts/{postId}"
+// @Param statusChange body ChangePostStatusRequest true "New status and an optional comment"
+// @Success 200 {object} PostStatusHistoryDTO "The recorded transition"
+// @Failure 400 {object} ErrorResponse "Invalid request payload or post ID format"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Caller may not make this transition"
+// @Failure 404 {object} ErrorResponse "Post not found"
+// @Failure 409 {object} ErrorResponse "Transition not allowed from the current status, or the status changed concurrently"
+// @Failure 412 {object} ErrorResponse "Post was modified since the ETag was issued"
+// @Failure 428 {object} ErrorResponse "If-Match header missing"
+// @Failure 500 {object} ErrorResponse "Failed to change post status"
+// @Security BearerAuth
+// @Router /posts/{postId}/status [post]
+func (s *Server) handleChangePostStatus(w http.ResponseWriter, r *http.Request) {
+	authUserID, err := getUserIDFromContext(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	postID, err := uuid.Parse(r.PathValue("postId"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid post ID format")
+		return
+	}
+
+	var req ChangePostStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload: "+err.Error())
+		return
+	}
+	defer r.Body.Close()
+
+	if !isValidPostStatus(db.PostStatus(req.Status)) {
+		respondWithError(w, http.StatusBadRequest, "Invalid status")
+		return
+	}
+	if utf8.RuneCountInString(req.Comment) > maxStatusCommentLength {
+		respondWithError(w, http.StatusBadRequest, "Comment is too long")
+		return
+	}
+
+	post, err := s.db.GetPost(r.Context(), toPgtypeUUID(postID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Post not found")
+			return
+		}
+		slog.Error("Failed to get post for status change", "error", err, "postID", postID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to change post status")
+		return
+	}
+
+	if !checkIfMatch(w, r, versionETag(post.UpdatedAt)) {
+		return
+	}
+
+	entry, err := changePostStatus(r.Context(), s.db.Queries, post, authUserID, db.PostStatus(req.Status), req.Comment)
+	if err != nil {
+		respondWithStatusChangeError(w, err, postID)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, toPostStatusHistoryDTO(entry))
+}
+
+// handleGetPostStatusHistory lists the status transitions of a post.
+// @Summary Get post status history
+// @Description Returns every status the post has been in, oldest first, with who moved it there and their comment.
+// @Description The first entry is the status the post was created with and has no from_status.
+// @Tags Posts
+// @Produce json
+// @Param postId path string true "Post ID" format(uuid)
+// @Success 200 {array} PostStatusHistoryDTO "Status transitions"
+// @Failure 400 {object} ErrorResponse "Invalid post ID format"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 404 {object} ErrorResponse "Post not found"
+// @Failure 500 {object} ErrorResponse "Failed to retrieve post history"
+// @Security BearerAuth
+// @Router /posts/{postId}/history [get]
+func (s *Server) handleGetPostStatusHistory(w http.ResponseWriter, r *http.Request) {
+	postID, err := uuid.Parse(r.PathValue("postId"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid post ID format")
+		return
+	}
+
+	rows, err := s.db.ListPostStatusHistory(r.Context(), toPgtypeUUID(postID))
+	if err != nil {
+		slog.Error("Failed to list post status history", "error", err, "postID", postID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve post history")
+		return
+	}
+	if len(rows) == 0 {
+		if _, err := s.db.GetPost(r.Context(), toPgtypeUUID(postID)); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+				respondWithError(w, http.StatusNotFound, "Post not found")
+				return
+			}
+			slog.Error("Failed to get post for status history", "error", err, "postID", postID)
+			respondWithError(w, http.StatusInternalServerError, "Failed to retrieve post history")
+			return
+		}
+	}
+
+	dtos := make([]PostStatusHistoryDTO, len(rows))
+	for i, row := range rows {
+		dtos[i] = toPostStatusHistoryDTO(db.PostStatusHistory{
+			ID:         row.ID,
+			PostID:     row.PostID,
+			FromStatus: row.FromStatus,
+			ToStatus:   row.ToStatus,
+			ChangedBy:  row.ChangedBy,
+			Comment:    row.Comment,
+			CreatedAt:  row.CreatedAt,
+		})
+		if row.ChangedByFirstName.Valid {
+			dtos[i].ChangedByName = row.ChangedByFirstName.String + " " + row.ChangedByLastName.String
+		}
+	}
+	respondWithJSON(w, http.StatusOK, dtos)
+}
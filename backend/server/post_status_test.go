@@ -0,0 +1,60 @@
+package server
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dukunuu/hackathon_backend/db"
+)
+
+func TestPostTransitionActors(t *testing.T) {
+	const donation = db.PostTypeValue0
+
+	tests := []struct {
+		name     string
+		postType db.PostType
+		from, to db.PostStatus
+		want     postActor
+		ok       bool
+	}{
+		{name: "author resolves a donation", postType: donation, from: postStatusInProgress, to: postStatusResolved, want: actorAuthor | actorModerator, ok: true},
+		{name: "only moderators resolve a complaint", postType: postTypeComplaint, from: postStatusInProgress, to: postStatusResolved, want: actorModerator, ok: true},
+		{name: "author cancels a complaint", postType: postTypeComplaint, from: postStatusPending, to: postStatusCancelled, want: actorAuthor | actorModerator, ok: true},
+		{name: "only moderators reopen", postType: donation, from: postStatusResolved, to: postStatusInProgress, want: actorModerator, ok: true},
+		{name: "pending cannot be resolved", postType: donation, from: postStatusPending, to: postStatusResolved, ok: false},
+		{name: "cancelled cannot be resumed", postType: donation, from: postStatusCancelled, to: postStatusInProgress, ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := postTransitionActors(tt.postType, tt.from, tt.to)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("postTransitionActors = %b, %v; want %b, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestCheckPostTypeChange(t *testing.T) {
+	const donation = db.PostTypeValue0
+
+	tests := []struct {
+		name     string
+		from, to db.PostType
+		want     error
+	}{
+		{name: "complaint stays a complaint", from: postTypeComplaint, to: postTypeComplaint},
+		{name: "complaint becomes a donation", from: postTypeComplaint, to: donation, want: errComplaintTypeChange},
+		{name: "donation becomes a complaint", from: donation, to: postTypeComplaint},
+		{name: "donation stays a donation", from: donation, to: donation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkPostTypeChange(db.GetPostRow{PostType: tt.from}, tt.to)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("checkPostTypeChange = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/dukunuu/hackathon_backend/db" // ADJUST THIS IMPORT PATH
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 // CreatePostRequest defines the JSON body for creating a new post.
@@ -16,7 +17,6 @@ import (
 type CreatePostRequest struct {
 	Title         string          `json:"title" example:"Need help cleaning the park"`
 	Description   string          `json:"description" example:"The local park needs volunteers for a cleanup drive."`
-	Status        string   				`json:"status,omitempty" example:"Хүлээгдэж байгаа"`
 	Priority      string				  `json:"priority,omitempty" example:"дунд"`
 	PreviewURL    string          `json:"preview_url,omitempty" example:"http://example.com/image.jpg"`
 	PostType      string		      `json:"post_type" example:"хандив"`
@@ -28,22 +28,31 @@ type CreatePostRequest struct {
 }
 
 // UpdatePostRequest defines the JSON body for updating an existing post.
+// Omitted fields keep their current value.
 // swagger:model UpdatePostRequest
 type UpdatePostRequest struct {
-	Title             string          `json:"title" example:"Urgent: Park Cleanup Drive"`
-	Description       string          `json:"description" example:"Updated details: The local park needs volunteers urgently."`
+	Title             *string         `json:"title,omitempty" example:"Urgent: Park Cleanup Drive"`
+	Description       *string         `json:"description,omitempty" example:"Updated details: The local park needs volunteers urgently."`
 	Status            string		      `json:"status" example:"Шийдвэрлэгдэж байгаа"`
 	Priority          string   			  `json:"priority" example:"өндөр"`
 	PreviewURL        string          `json:"preview_url,omitempty" example:"http://example.com/new_image.jpg"`
 	PostType          string		      `json:"post_type" example:"хандив"`
-	MaxVolunteers     int32           `json:"max_volunteers" example:"15"`
-	CurrentVolunteers int32           `json:"current_volunteers" example:"5"`
+	MaxVolunteers     *int32          `json:"max_volunteers,omitempty" example:"15"`
 	CategoryID        uuid.UUID       `json:"category_id,omitempty" format:"uuid"`
 	LocationLat       float64         `json:"location_lat,omitempty" example:"47.9200"`
 	LocationLng       float64         `json:"location_lng,omitempty" example:"106.9250"`
 	AddressText       string          `json:"address_text,omitempty" example:"Sukhbaatar Square, Ulaanbaatar"`
 }
 
+// CreateCategoryRequest defines the JSON body for creating a category.
+// swagger:model CreateCategoryRequest
+type CreateCategoryRequest struct {
+	Name         string `json:"name" example:"Хог хаягдал"`
+	Description  string `json:"description,omitempty" example:"Хог хаягдлын цэг, цэвэрлэгээ"`
+	Endpoint     string `json:"endpoint" example:"waste"`
+	CanVolunteer bool   `json:"can_volunteer" example:"true"`
+}
+
 // swagger:model CategoryDTO
 type CategoryDTO struct{
 	ID        uuid.UUID   `json:"id" format:"uuid"`
@@ -111,14 +120,13 @@ type PostResponseDTO struct {
 	UserID            uuid.UUID          `json:"user_id" format:"uuid"`
 	MaxVolunteers     int32              `json:"max_volunteers"`
 	CurrentVolunteers int32              `json:"current_volunteers"`
-	CategoryID        uuid.UUID        `json:"category_id,omitempty"`
+	CategoryID        *uuid.UUID         `json:"category_id,omitempty" format:"uuid"` // Omitted for uncategorized posts
 	LocationLat       float32      `json:"location_lat,omitempty"`
 	LocationLng       float32      `json:"location_lng,omitempty"`
 	AddressText       string        `json:"address_text,omitempty"`
 	CreatedAt         time.Time          `json:"created_at"`
 	UpdatedAt         time.Time          `json:"updated_at"`
 	Images            []string           `json:"images"`
-	Volunteers        []PostVolunteerDTO `json:"volunteers"`
 }
 
 // ApproveRejectVolunteerRequest defines the JSON body for approving or rejecting a volunteer.
@@ -128,12 +136,16 @@ type ApproveRejectVolunteerRequest struct {
 	VolunteerUserID uuid.UUID `json:"volunteer_user_id" format:"uuid" example:"a1b2c3d4-e5f6-7777-8888-99990000bbbb"`
 }
 
-func toPostResponseDTO(p db.Post) PostResponseDTO {
-	categoryId,err := uuid.Parse(p.CategoryID.String())
-	if err != nil {
-		slog.Default().Error("")
-		return PostResponseDTO{}
+// optionalUUID returns nil for a NULL UUID.
+func optionalUUID(id pgtype.UUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
 	}
+	u := uuid.UUID(id.Bytes)
+	return &u
+}
+
+func toPostResponseDTO(p db.Post) PostResponseDTO {
 	return PostResponseDTO{
 		ID:                p.ID.Bytes,
 		Title:             p.Title,
@@ -145,14 +157,13 @@ func toPostResponseDTO(p db.Post) PostResponseDTO {
 		UserID:            p.UserID.Bytes,
 		MaxVolunteers:     p.MaxVolunteers,
 		CurrentVolunteers: p.CurrentVolunteers,
-		CategoryID:        categoryId,
+		CategoryID:        optionalUUID(p.CategoryID),
 		LocationLat:       float32(p.LocationLat.Float64),
 		LocationLng:       float32(p.LocationLng.Float64),
 		AddressText:       p.AddressText.String,
 		CreatedAt:         p.CreatedAt.Time,
 		UpdatedAt:         p.UpdatedAt.Time,
 		Images:            []string{},
-		Volunteers:        []PostVolunteerDTO{},
 	}
 }
 
@@ -164,31 +175,6 @@ func toPostResponseDTOFromGetPostRow(row db.GetPostRow) (PostResponseDTO, error)
 		}
 	}
 
-	categoryId,err := uuid.Parse(row.CategoryID.String())
-	if err != nil {
-		slog.Default().Error("")
-		return PostResponseDTO{}, err
-	}
-
-	var volunteers []PostVolunteerDTO
-	if row.Volunteers != nil && len(row.Volunteers) > 0 {
-		var dbVolunteers []db.PostVolunteer
-		if err := json.Unmarshal(row.Volunteers, &dbVolunteers); err != nil {
-			return PostResponseDTO{}, fmt.Errorf("failed to unmarshal volunteers: %w", err)
-		}
-		for _, v := range dbVolunteers {
-			volunteers = append(volunteers, PostVolunteerDTO{
-				ID:        v.ID.Bytes,
-				UserID:    v.UserID.Bytes,
-				PostID:    v.PostID.Bytes,
-				Status:    v.Status.String,
-				Notes:     v.Notes.String,
-				CreatedAt: v.CreatedAt.Time,
-				UpdatedAt: v.UpdatedAt.Time,
-			})
-		}
-	}
-
 	return PostResponseDTO{
 		ID:                row.ID.Bytes,
 		Title:             row.Title,
@@ -200,14 +186,13 @@ func toPostResponseDTOFromGetPostRow(row db.GetPostRow) (PostResponseDTO, error)
 		UserID:            row.UserID.Bytes,
 		MaxVolunteers:     row.MaxVolunteers,
 		CurrentVolunteers: row.CurrentVolunteers,
-		CategoryID:        categoryId,
+		CategoryID:        optionalUUID(row.CategoryID),
 		LocationLat:       float32(row.LocationLat.Float64),
 		LocationLng:       float32(row.LocationLng.Float64),
 		AddressText:       row.AddressText.String,
 		CreatedAt:         row.CreatedAt.Time,
 		UpdatedAt:         row.UpdatedAt.Time,
 		Images:            images,
-		Volunteers:        volunteers,
 	}, nil
 }
 
@@ -219,31 +204,6 @@ func toPostResponseDTOFromListPostsRow(row db.ListPostsRow) (PostResponseDTO, er
 		}
 	}
 
-	categoryId,err := uuid.Parse(row.CategoryID.String())
-	if err != nil && len(categoryId) == 0 {
-		slog.Default().Error("")
-		return PostResponseDTO{}, err
-	}
-
-	var volunteers []PostVolunteerDTO
-	if row.Volunteers != nil && len(row.Volunteers) > 0 {
-		var dbVolunteers []db.PostVolunteer
-		if err := json.Unmarshal(row.Volunteers, &dbVolunteers); err != nil {
-			return PostResponseDTO{}, fmt.Errorf("failed to unmarshal volunteers: %w", err)
-		}
-		for _, v := range dbVolunteers {
-			volunteers = append(volunteers, PostVolunteerDTO{
-				ID:        v.ID.Bytes,
-				UserID:    v.UserID.Bytes,
-				PostID:    v.PostID.Bytes,
-				Status:    v.Status.String,
-				Notes:     v.Notes.String,
-				CreatedAt: v.CreatedAt.Time,
-				UpdatedAt: v.UpdatedAt.Time,
-			})
-		}
-	}
-
 	return PostResponseDTO{
 		ID:                row.ID.Bytes,
 		Title:             row.Title,
@@ -255,13 +215,12 @@ func toPostResponseDTOFromListPostsRow(row db.ListPostsRow) (PostResponseDTO, er
 		UserID:            row.UserID.Bytes,
 		MaxVolunteers:     row.MaxVolunteers,
 		CurrentVolunteers: row.CurrentVolunteers,
-		CategoryID:        categoryId,
+		CategoryID:        optionalUUID(row.CategoryID),
 		LocationLat:       float32(row.LocationLat.Float64),
 		LocationLng:       float32(row.LocationLng.Float64),
 		AddressText:       row.AddressText.String,
 		CreatedAt:         row.CreatedAt.Time,
 		UpdatedAt:         row.UpdatedAt.Time,
 		Images:            images,
-		Volunteers:        volunteers,
 	}, nil
 }
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/dukunuu/hackathon_backend/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestPostResponseDTOCategory(t *testing.T) {
+	categoryID := uuid.New()
+
+	tests := []struct {
+		name     string
+		category pgtype.UUID
+		want     *uuid.UUID
+	}{
+		{name: "uncategorized", category: pgtype.UUID{}},
+		{name: "categorized", category: pgtype.UUID{Bytes: categoryID, Valid: true}, want: &categoryID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := db.GetPostRow{
+				ID:         pgtype.UUID{Bytes: uuid.New(), Valid: true},
+				UserID:     pgtype.UUID{Bytes: uuid.New(), Valid: true},
+				CategoryID: tt.category,
+			}
+			dto, err := toPostResponseDTOFromGetPostRow(row)
+			if err != nil {
+				t.Fatalf("toPostResponseDTOFromGetPostRow: %v", err)
+			}
+			if (dto.CategoryID == nil) != (tt.want == nil) || (tt.want != nil && *dto.CategoryID != *tt.want) {
+				t.Errorf("CategoryID = %v, want %v", dto.CategoryID, tt.want)
+			}
+
+			body, err := json.Marshal(dto)
+			if err != nil {
+				t.Fatalf("json.Marshal: %v", err)
+			}
+			if has := strings.Contains(string(body), `"category_id"`); has != (tt.want != nil) {
+				t.Errorf("category_id present = %v in %s", has, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dukunuu/hackathon_backend/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Visibility used for users who never changed their privacy settings. Keep
+// in sync with the column defaults of user_privacy_settings.
+const (
+	defaultPhoneVisibility = db.ProfileFieldVisibilityOrganizers
+	defaultEmailVisibility = db.ProfileFieldVisibilityPrivate
+)
+
+// UserStatsDTO holds the public volunteering statistics of a user.
+// swagger:model UserStatsDTO
+type UserStatsDTO struct {
+	PostsCount              int64 `json:"posts_count" example:"4"`
+	ResolvedPostsCount      int64 `json:"resolved_posts_count" example:"2"`
+	VolunteerCount          int64 `json:"volunteer_count" example:"7"`
+	CompletedVolunteerCount int64 `json:"completed_volunteer_count" example:"5"`
+}
+
+// PublicUserProfileDTO is what other users may see of an account. Contact
+// details are only included when the owner's privacy settings allow it.
+// swagger:model PublicUserProfile
+type PublicUserProfileDTO struct {
+	ID             uuid.UUID    `json:"id" format:"uuid"`
+	FirstName      string       `json:"first_name" example:"John"`
+	LastName       string       `json:"last_name" example:"Doe"`
+	ProfileUrl     *string      `json:"profile_url,omitempty" example:"http://example.com/profile.jpg"`
+	IsVolunteering bool         `json:"is_volunteering" example:"true"`
+	Stats          UserStatsDTO `json:"stats"`
+	JoinedAt       time.Time    `json:"joined_at" example:"2023-01-01T12:00:00Z"`
+	Phone          *string      `json:"phone,omitempty" example:"99119911"`
+	Email          *string      `json:"email,omitempty" example:"john.doe@example.com"`
+}
+
+// PrivacySettingsDTO controls who may see a user's contact details:
+// 'private' (only the user and admins), 'organizers' (also authors of posts
+// the user volunteered on) or 'public' (any signed-in user).
+// swagger:model PrivacySettings
+type PrivacySettingsDTO struct {
+	PhoneVisibility db.ProfileFieldVisibility `json:"phone_visibility" enums:"private,organizers,public" example:"organizers"`
+	EmailVisibility db.ProfileFieldVisibility `json:"email_visibility" enums:"private,organizers,public" example:"private"`
+}
+
+func isValidProfileFieldVisibility(v db.ProfileFieldVisibility) bool {
+	switch v {
+	case db.ProfileFieldVisibilityPrivate, db.ProfileFieldVisibilityOrganizers, db.ProfileFieldVisibilityPublic:
+		return true
+	}
+	return false
+}
+
+// canViewFullUser reports whether the caller may see the complete record of
+// the user: their own account, or any account with 'users:manage'.
+func canViewFullUser(ctx context.Context, userID pgtype.UUID) bool {
+	viewerID, err := getUserIDFromContext(ctx)
+	if err == nil && viewerID.Bytes == userID.Bytes {
+		return true
+	}
+	return hasPermission(ctx, PermUsersManage)
+}
+
+// fieldVisible applies one privacy setting for the viewer.
+func fieldVisible(visibility db.ProfileFieldVisibility, viewerIsOrganizer bool) bool {
+	switch visibility {
+	case db.ProfileFieldVisibilityPublic:
+		return true
+	case db.ProfileFieldVisibilityOrganizers:
+		return viewerIsOrganizer
+	default:
+		return false
+	}
+}
+
+// publicProfiles builds the public profiles of users as the caller may see
+// them, with statistics and privacy settings loaded in batches.
+func (s *Server) publicProfiles(ctx context.Context, users []db.User) ([]PublicUserProfileDTO, error) {
+	ids := make([]pgtype.UUID, len(users))
+	for i, u := range users {
+		ids[i] = u.ID
+	}
+
+	stats, err := s.db.ListUserStats(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	statsByUser := make(map[[16]byte]db.ListUserStatsRow, len(stats))
+	for _, st := range stats {
+		statsByUser[st.UserID.Bytes] = st
+	}
+
+	settings, err := s.db.ListUserPrivacySettings(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	settingsByUser := make(map[[16]byte]db.UserPrivacySetting, len(settings))
+	for _, st := range settings {
+		settingsByUser[st.UserID.Bytes] = st
+	}
+
+	organizerOf := make(map[[16]byte]bool)
+	if viewerID, err := getUserIDFromContext(ctx); err == nil {
+		volunteers, err := s.db.ListVolunteersOfOrganizer(ctx, db.ListVolunteersOfOrganizerParams{
+			OrganizerID: viewerID,
+			UserIds:     ids,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range volunteers {
+			organizerOf[id.Bytes] = true
+		}
+	}
+
+	profiles := make([]PublicUserProfileDTO, len(users))
+	for i, u := range users {
+		st := statsByUser[u.ID.Bytes]
+		profile := PublicUserProfileDTO{
+			ID:             u.ID.Bytes,
+			FirstName:      u.FirstName,
+			LastName:       u.LastName,
+			IsVolunteering: u.IsVolunteering,
+			JoinedAt:       u.CreatedAt.Time,
+			Stats: UserStatsDTO{
+				PostsCount:              st.PostsCount,
+				ResolvedPostsCount:      st.ResolvedPostsCount,
+				VolunteerCount:          st.VolunteerCount,
+				CompletedVolunteerCount: st.CompletedVolunteerCount,
+			},
+		}
+		if u.ProfileUrl.Valid {
+			profile.ProfileUrl = &u.ProfileUrl.String
+		}
+
+		phoneVisibility, emailVisibility := defaultPhoneVisibility, defaultEmailVisibility
+		if setting, ok := settingsByUser[u.ID.Bytes]; ok {
+			phoneVisibility, emailVisibility = setting.PhoneVisibility, setting.EmailVisibility
+		}
+		if u.Phone.Valid && fieldVisible(phoneVisibility, organizerOf[u.ID.Bytes]) {
+			profile.Phone = &u.Phone.String
+		}
+		if fieldVisible(emailVisibility, organizerOf[u.ID.Bytes]) {
+			profile.Email = &u.Email
+		}
+		profiles[i] = profile
+	}
+	return profiles, nil
+}
+
+// handleGetPrivacySettings returns the current user's privacy settings.
+// @Summary Get privacy settings
+// @Description Returns who may see the current user's phone number and email address on their public profile.
+// @Tags Users
+// @Produce json
+// @Success 200 {object} PrivacySettingsDTO "Privacy settings"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 500 {object} ErrorResponse "Failed to retrieve privacy settings"
+// @Security BearerAuth
+// @Router /users/me/privacy [get]
+func (s *Server) handleGetPrivacySettings(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromContext(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	settings, err := s.db.ListUserPrivacySettings(r.Context(), []pgtype.UUID{userID})
+	if err != nil {
+		slog.Error("Failed to get privacy settings", "error", err, "userID", userID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve privacy settings")
+		return
+	}
+
+	dto := PrivacySettingsDTO{PhoneVisibility: defaultPhoneVisibility, EmailVisibility: defaultEmailVisibility}
+	if len(settings) > 0 {
+		dto = PrivacySettingsDTO{PhoneVisibility: settings[0].PhoneVisibility, EmailVisibility: settings[0].EmailVisibility}
+	}
+	respondWithJSON(w, http.StatusOK, dto)
+}
+
+// handleUpdatePrivacySettings changes the current user's privacy settings.
+// @Summary Update privacy settings
+// @Description Sets who may see the current user's phone number and email address: 'private', 'organizers' or 'public'.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param settings body PrivacySettingsDTO true "New privacy settings"
+// @Success 200 {object} PrivacySettingsDTO "Updated privacy settings"
+// @Failure 400 {object} ErrorResponse "Invalid visibility value"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 500 {object} ErrorResponse "Failed to update privacy settings"
+// @Security BearerAuth
+// @Router /users/me/privacy [put]
+func (s *Server) handleUpdatePrivacySettings(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromContext(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req PrivacySettingsDTO
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	if !isValidProfileFieldVisibility(req.PhoneVisibility) || !isValidProfileFieldVisibility(req.EmailVisibility) {
+		respondWithError(w, http.StatusBadRequest, "phone_visibility and email_visibility must be one of: private, organizers, public")
+		return
+	}
+
+	settings, err := s.db.UpsertUserPrivacySettings(r.Context(), db.UpsertUserPrivacySettingsParams{
+		UserID:          userID,
+		PhoneVisibility: req.PhoneVisibility,
+		EmailVisibility: req.EmailVisibility,
+	})
+	if err != nil {
+		slog.Error("Failed to update privacy settings", "error", err, "userID", userID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to update privacy settings")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, PrivacySettingsDTO{PhoneVisibility: settings.PhoneVisibility, EmailVisibility: settings.EmailVisibility})
+}
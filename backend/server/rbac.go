@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/dukunuu/hackathon_backend/db"
+)
+
+// Permission is a named capability granted to one or more roles.
+type Permission string
+
+const (
+	PermPostsModerate   Permission = "posts:moderate"
+	PermUsersManage     Permission = "users:manage"
+	PermCategoriesWrite Permission = "categories:write"
+)
+
+// rolePermissions is the single source of truth for what each role may do.
+// Roles not listed here (or listed with no permissions) can only act on
+// resources they own.
+var rolePermissions = map[db.UserRole][]Permission{
+	db.UserRoleADMIN:     {PermPostsModerate, PermUsersManage, PermCategoriesWrite},
+	db.UserRoleMODERATOR: {PermPostsModerate},
+	db.UserRoleUSER:      {},
+}
+
+func isValidRole(role db.UserRole) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+func roleHasPermission(role db.UserRole, perm Permission) bool {
+	return slices.Contains(rolePermissions[role], perm)
+}
+
+func getUserRoleFromContext(ctx context.Context) (db.UserRole, error) {
+	roleVal := ctx.Value(UserRoleKey)
+	if roleVal == nil {
+		return "", errors.New("user role not found in context")
+	}
+	role, ok := roleVal.(db.UserRole)
+	if !ok {
+		return "", errors.New("user role in context is of invalid type")
+	}
+	return role, nil
+}
+
+// mfaRequired reports whether policy demands a second factor the caller's
+// session did not pass. Such callers keep their account but lose their
+// role's privileges until they log in with 2FA.
+func mfaRequired(ctx context.Context) bool {
+	required, _ := ctx.Value(MFARequiredKey).(bool)
+	return required
+}
+
+// hasPermission reports whether the authenticated caller's role grants perm
+// and, for API keys, whether the key was given that scope.
+func hasPermission(ctx context.Context, perm Permission) bool {
+	if mfaRequired(ctx) {
+		return false
+	}
+	role, err := getUserRoleFromContext(ctx)
+	if err != nil {
+		return false
+	}
+	return roleHasPermission(role, perm) && apiKeyAllows(ctx, perm)
+}
+
+// RequirePermission only lets the request through when the caller's role
+// grants perm. It must be mounted after AuthMiddleware.
+func (s *Server) RequirePermission(perm Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			role, err := getUserRoleFromContext(r.Context())
+			if err != nil {
+				respondWithError(w, http.StatusUnauthorized, "Authentication required")
+				return
+			}
+			if mfaRequired(r.Context()) {
+				respondWithError(w, http.StatusForbidden, errAdminMFARequired)
+				return
+			}
+			if !roleHasPermission(role, perm) {
+				respondWithError(w, http.StatusForbidden, "Missing permission: "+string(perm))
+				return
+			}
+			if !apiKeyAllows(r.Context(), perm) {
+				respondWithError(w, http.StatusForbidden, "API key is missing scope: "+string(perm))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"html"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/dukunuu/hackathon_backend/db"
+	"github.com/google/uuid"
+)
+
+// maxSearchTerms caps how many words of a search are matched.
+const maxSearchTerms = 10
+
+// PostSearchResultDTO is a post matching a search. The highlight fields are
+// HTML-escaped text with the matched words wrapped in <mark> tags.
+// swagger:model PostSearchResultDTO
+type PostSearchResultDTO struct {
+	ID               uuid.UUID `json:"id" format:"uuid"`
+	Title            string    `json:"title"`
+	Status           string    `json:"status"`
+	Priority         string    `json:"priority"`
+	PostType         string    `json:"post_type"`
+	CategoryID       uuid.UUID `json:"category_id,omitempty" format:"uuid"`
+	AddressText      string    `json:"address_text,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+	Rank             float32   `json:"rank" example:"0.42"`
+	TitleHighlight   string    `json:"title_highlight" example:"<mark>Гэрэлтүүлэг</mark> ажиллахгүй байна"`
+	Snippet          string    `json:"snippet" example:"…гудамжны <mark>гэрэлтүүлэг</mark> долоо хоног асахгүй…"`
+	AddressHighlight string    `json:"address_highlight,omitempty"`
+}
+
+// postSearchQuery turns free text into a to_tsquery expression that requires
+// every word, each matched as a prefix so inflected Mongolian forms are
+// found. Only letters and digits survive, so the result is always valid
+// tsquery syntax. It returns "" when the text holds no words.
+func postSearchQuery(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) > maxSearchTerms {
+		words = words[:maxSearchTerms]
+	}
+	for i, w := range words {
+		words[i] = w + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+// markHighlights escapes a ts_headline result and turns its match markers
+// into <mark> tags.
+func markHighlights(s string) string {
+	s = html.EscapeString(s)
+	s = strings.ReplaceAll(s, "\x02", "<mark>")
+	return strings.ReplaceAll(s, "\x03", "</mark>")
+}
+
+func toPostSearchResultDTO(row db.SearchPostsRow) PostSearchResultDTO {
+	dto := PostSearchResultDTO{
+		ID:             row.ID.Bytes,
+		Title:          row.Title,
+		Status:         string(row.Status),
+		Priority:       string(row.Priority),
+		PostType:       string(row.PostType),
+		CategoryID:     row.CategoryID.Bytes,
+		AddressText:    row.AddressText.String,
+		CreatedAt:      row.CreatedAt.Time,
+		Rank:           row.Rank,
+		TitleHighlight: markHighlights(row.TitleHighlight),
+		Snippet:        markHighlights(row.Snippet),
+	}
+	if row.AddressText.Valid {
+		dto.AddressHighlight = markHighlights(row.AddressHighlight)
+	}
+	return dto
+}
+
+// handleSearchPosts runs a full-text search over posts.
+// @Summary Search posts
+// @Description Finds posts whose title, description or address contain every word of 'q', best matches first.
+// @Description Words match by prefix, so 'Гэрэлтүүлэг' also finds 'гэрэлтүүлэгийн'. Title matches rank above description and address matches.
+// @Description Highlights are HTML-escaped with matches wrapped in <mark> tags.
+// @Tags Posts
+// @Produce json
+// @Param q query string true "Search text"
+// @Param status query string false "Only posts with this status"
+// @Param priority query string false "Only posts with this priority"
+// @Param post_type query string false "Only posts of this type"
+// @Param category_id query string false "Only posts in this category" format(uuid)
+// @Param limit query int false "Maximum number of results, 1-100 (default 20)"
+// @Success 200 {array} PostSearchResultDTO "Matching posts"
+// @Failure 400 {object} ErrorResponse "Missing search text or invalid filter"
+// @Failure 500 {object} ErrorResponse "Failed to search posts"
+// @Router /posts/search [get]
+func (s *Server) handleSearchPosts(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	query := postSearchQuery(q.Get("q"))
+	if query == "" {
+		respondWithError(w, http.StatusBadRequest, "Search text 'q' is required")
+		return
+	}
+
+	limit, err := parseResultLimit(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	filters, err := parsePostFilters(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	rows, err := s.db.SearchPosts(r.Context(), db.SearchPostsParams{
+		Query:       query,
+		Status:      filters.Status,
+		Priority:    filters.Priority,
+		PostType:    filters.PostType,
+		CategoryID:  filters.CategoryID,
+		ResultLimit: limit,
+	})
+	if err != nil {
+		slog.Error("Failed to search posts", "error", err, "query", query)
+		respondWithError(w, http.StatusInternalServerError, "Failed to search posts")
+		return
+	}
+
+	results := make([]PostSearchResultDTO, len(rows))
+	for i, row := range rows {
+		results[i] = toPostSearchResultDTO(row)
+	}
+	respondWithJSON(w, http.StatusOK, results)
+}
@@ -1,13 +1,20 @@
 package server
 
 import (
+	"context"
 	"log"
 	"log/slog"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/dukunuu/hackathon_backend/ai"
+	"github.com/dukunuu/hackathon_backend/config"
 	"github.com/dukunuu/hackathon_backend/db"
 	"github.com/dukunuu/hackathon_backend/file"
+	"github.com/dukunuu/hackathon_backend/jwtkeys"
+	"github.com/dukunuu/hackathon_backend/mail"
+	"github.com/dukunuu/hackathon_backend/oidc"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
@@ -15,20 +22,36 @@ import (
 )
 
 type Server struct {
-	db        *db.Queries
-	filestore *file.MinioStore
-	addr      string
-	jwtSecret string
-	aiModel   *ai.OllamaModel // Add the AI model
+	db                   *db.Store
+	filestore            *file.MinioStore
+	addr                 string
+	signingKeys          *jwtkeys.KeySet
+	accountDeletionGrace time.Duration
+	accessTokenTTL       time.Duration
+	refreshTokenTTL      time.Duration
+	aiModel              *ai.OllamaModel // Add the AI model
+	mailer               mail.Sender
+	appBaseURL           string
+	mfaIssuer            string
+	oidc                 *oidc.Registry
+	secureCookies        bool
 }
 
-func Init(addr, jwtSecret string, database *db.Queries, filestore *file.MinioStore, aiModel *ai.OllamaModel) *Server {
+func Init(cfg *config.Config, database *db.Store, filestore *file.MinioStore, aiModel *ai.OllamaModel, mailer mail.Sender, oidcProviders *oidc.Registry, signingKeys *jwtkeys.KeySet) *Server {
 	return &Server{
-		db:        database,
-		addr:      addr,
-		jwtSecret: jwtSecret,
-		filestore: filestore,
-		aiModel: aiModel,
+		db:                   database,
+		addr:                 cfg.HOST,
+		signingKeys:          signingKeys,
+		accountDeletionGrace: cfg.ACCOUNT_DELETION_GRACE,
+		accessTokenTTL:       cfg.ACCESS_TOKEN_TTL,
+		refreshTokenTTL:      cfg.REFRESH_TOKEN_TTL,
+		filestore:            filestore,
+		aiModel:              aiModel,
+		mailer:               mailer,
+		appBaseURL:           cfg.APP_BASE_URL,
+		mfaIssuer:            cfg.MFA_ISSUER,
+		oidc:                 oidcProviders,
+		secureCookies:        strings.HasPrefix(cfg.API_BASE_URL, "https://"),
 	}
 }
 func (s *Server) Start() {
@@ -36,9 +59,9 @@ func (s *Server) Start() {
 
 	c := cors.New(cors.Options{
 		AllowedOrigins:   []string{"*"}, // Allow all origins
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-API-Key", "X-CSRF-Token", "If-Match", "If-None-Match"},
+		ExposedHeaders:   []string{"ETag", "Link", "Retry-After"},
 		AllowCredentials: true,
 		MaxAge:           300, // Maximum value not ignored by any major browsers
 	})
@@ -50,37 +73,105 @@ func (s *Server) Start() {
 	r.Use(middleware.Recoverer)
 
 	r.Get("/docs/*", httpSwagger.WrapHandler)
+	r.Get("/.well-known/jwks.json", s.handleJWKS)
 
 	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
 		respondWithJSON(w, http.StatusOK, map[string]string{"message": "Welcome to the API"})
 	})
 	r.Post("/api/v1/users/register", s.handleCreateUser)
 	r.Post("/api/v1/users/login", s.handleLogin)
+	r.Post("/api/v1/auth/refresh", s.handleRefreshToken)
+	r.Post("/api/v1/auth/mfa/verify", s.handleVerifyMFA)
+	r.Post("/api/v1/auth/verify-email", s.handleVerifyEmail)
+	r.Post("/api/v1/auth/confirm-email-change", s.handleConfirmEmailChange)
+	r.Post("/api/v1/auth/password/forgot", s.handleForgotPassword)
+	r.Post("/api/v1/auth/password/reset", s.handleResetPassword)
+	r.Get("/api/v1/auth/oidc/providers", s.handleListOIDCProviders)
+	r.Get("/api/v1/auth/oidc/{provider}/login", s.handleOIDCLogin)
+	r.Get("/api/v1/auth/oidc/{provider}/callback", s.handleOIDCCallback)
+	r.Post("/api/v1/auth/oidc/exchange", s.handleOIDCExchange)
 
 	r.Get("/api/v1/posts", s.handleListPosts)
+	r.Get("/api/v1/posts/search", s.handleSearchPosts)
+	r.Get("/api/v1/posts/nearby", s.handleListPostsNearby)
+	r.Get("/api/v1/posts/in-bounds", s.handleListPostsInBounds)
+	r.Get("/api/v1/posts.geojson", s.handlePostsGeoJSON)
+	r.Get("/api/v1/tiles/{z}/{x}/{y}.mvt", s.handlePostTile)
 	r.Get("/api/v1/category/{categoryId}", s.handleGetCategoryName)
 	r.Get("/api/v1/categories", s.handleGetCategories)
 
 r.Group(func(rauth chi.Router) {
 		rauth.Use(s.AuthMiddleware)
+		rauth.Post("/api/v1/auth/verify-email/resend", s.handleResendVerificationEmail)
+
 		rauth.Get("/api/v1/users/me", s.handleGetCurrentUser)
 		rauth.Put("/api/v1/users/me/details", s.handleUpdateUserDetails)
-		rauth.Put("/api/v1/users/me/email", s.handleUpdateUserEmail)
-		rauth.Put("/api/v1/users/me/password", s.handleUpdateUserPassword)
+		rauth.Get("/api/v1/users/me/privacy", s.handleGetPrivacySettings)
+		rauth.Put("/api/v1/users/me/privacy", s.handleUpdatePrivacySettings)
+		rauth.Get("/api/v1/users/me/volunteering", s.handleListMyVolunteering)
+		rauth.Get("/api/v1/users/me/volunteer-profile", s.handleGetVolunteerProfile)
+		rauth.Put("/api/v1/users/me/volunteer-profile", s.handleUpdateVolunteerProfile)
+
+		// Credentials, sessions and API keys can only be managed after an interactive login.
+		rauth.Group(func(rsession chi.Router) {
+			rsession.Use(s.RequireSession)
+			rsession.Post("/api/v1/auth/logout", s.handleLogout)
+
+			rsession.Put("/api/v1/users/me/email", s.handleUpdateUserEmail)
+			rsession.Put("/api/v1/users/me/password", s.handleUpdateUserPassword)
+
+			rsession.Get("/api/v1/users/me/mfa", s.handleGetMFAStatus)
+			rsession.Post("/api/v1/users/me/mfa/setup", s.handleSetupMFA)
+			rsession.Post("/api/v1/users/me/mfa/enable", s.handleEnableMFA)
+			rsession.Post("/api/v1/users/me/mfa/disable", s.handleDisableMFA)
+			rsession.Post("/api/v1/users/me/mfa/recovery-codes", s.handleRegenerateRecoveryCodes)
+
+			rsession.Get("/api/v1/users/me/api-keys", s.handleListAPIKeys)
+			rsession.Post("/api/v1/users/me/api-keys", s.handleCreateAPIKey)
+			rsession.Delete("/api/v1/users/me/api-keys/{keyID}", s.handleRevokeAPIKey)
+
+			rsession.Get("/api/v1/users/me/export", s.handleExportAccountData)
+
+			rsession.With(s.RequirePermission(PermUsersManage)).Put("/api/v1/settings/security", s.handleUpdateSecuritySettings)
+		})
 
 		rauth.Get("/api/v1/users", s.handleListUsers)
 		rauth.Get("/api/v1/users/{userID}", s.handleGetUserByID)
 		rauth.Delete("/api/v1/users/{userID}", s.handleDeleteUser)
-		rauth.Get("/api/v1/users/by-email", s.handleGetUserByEmail)
+		rauth.With(s.RequirePermission(PermUsersManage)).Get("/api/v1/users/by-email", s.handleGetUserByEmail)
 
 		rauth.Get("/api/v1/users/{userId}/posts", s.handleGetUserPosts)
-		rauth.Post("/api/v1/posts", s.handleCreatePost)
+		rauth.With(s.RequireVerifiedEmail).Post("/api/v1/posts", s.handleCreatePost)
 
 		rauth.Get("/api/v1/posts/{postId}", s.handleGetPost)
 		rauth.Put("/api/v1/posts/{postId}", s.handleUpdatePost)
+		rauth.Patch("/api/v1/posts/{postId}", s.handlePatchPost)
 		rauth.Delete("/api/v1/posts/{postId}", s.handleDeletePost)
-
-		rauth.Get("/api/v1/posts/volunteers", s.handleListPostVolunteers)
+		rauth.Post("/api/v1/posts/{postId}/status", s.handleChangePostStatus)
+		rauth.Get("/api/v1/posts/{postId}/history", s.handleGetPostStatusHistory)
+		rauth.Get("/api/v1/posts/{postId}/images", s.handleListPostImages)
+		rauth.Post("/api/v1/posts/{postId}/images", s.handleAddPostImages)
+		rauth.Put("/api/v1/posts/{postId}/images/order", s.handleReorderPostImages)
+		rauth.Delete("/api/v1/posts/{postId}/images/{imageId}", s.handleDeletePostImage)
+		rauth.Post("/api/v1/posts/{postId}/images/{imageId}/primary", s.handleSetPrimaryPostImage)
+
+		rauth.Get("/api/v1/posts/{postId}/volunteers", s.handleListPostVolunteers)
+		rauth.With(s.RequireVerifiedEmail).Post("/api/v1/posts/{postId}/volunteers", s.handleApplyToPost)
+		rauth.Post("/api/v1/posts/{postId}/volunteers/withdraw", s.handleWithdrawFromPost)
+		rauth.Post("/api/v1/posts/{postId}/volunteers/{userId}/complete", s.handleCompleteVolunteer)
+		rauth.Post("/api/v1/posts/{postId}/volunteers/{userId}/no-show", s.handleMarkVolunteerNoShow)
+		rauth.Get("/api/v1/posts/{postId}/suggested-volunteers", s.handleSuggestVolunteers)
+		rauth.Get("/api/v1/posts/{postId}/skills", s.handleGetPostSkills)
+		rauth.Put("/api/v1/posts/{postId}/skills", s.handleUpdatePostSkills)
+
+		rauth.Get("/api/v1/posts/{postId}/shifts", s.handleListPostShifts)
+		rauth.Post("/api/v1/posts/{postId}/shifts", s.handleCreatePostShift)
+		rauth.Delete("/api/v1/posts/{postId}/shifts/{shiftId}", s.handleDeletePostShift)
+		rauth.Get("/api/v1/posts/{postId}/shifts/{shiftId}/signups", s.handleListShiftSignups)
+		rauth.Post("/api/v1/posts/{postId}/shifts/{shiftId}/signup", s.handleSignUpForShift)
+		rauth.Delete("/api/v1/posts/{postId}/shifts/{shiftId}/signup", s.handleWithdrawFromShift)
+		rauth.Post("/api/v1/posts/{postId}/shifts/{shiftId}/check-in", s.handleShiftCheckIn)
+		rauth.Post("/api/v1/posts/{postId}/shifts/{shiftId}/check-out", s.handleShiftCheckOut)
 
 		rauth.Delete("/api/v1/posts/volunteers/{userId}", s.handleDeletePostVolunteer)
 
@@ -88,7 +179,17 @@ r.Group(func(rauth chi.Router) {
 		rauth.Post("/api/v1/reject_volunteer", s.handleRejectVolunteer)
 
 		rauth.Get("/api/v1/users/{userId}/stats", s.handleGetUserStats)
+
+		rauth.With(s.RequirePermission(PermUsersManage)).Put("/api/v1/users/{userID}/role", s.handleUpdateUserRole)
+		rauth.With(s.RequirePermission(PermUsersManage)).Post("/api/v1/users/{userID}/unlock", s.handleUnlockUser)
+		rauth.With(s.RequirePermission(PermUsersManage)).Post("/api/v1/users/{userID}/restore", s.handleRestoreUser)
+		rauth.With(s.RequirePermission(PermUsersManage)).Get("/api/v1/users/{userID}/lockouts", s.handleListUserLockouts)
+		rauth.With(s.RequirePermission(PermCategoriesWrite)).Post("/api/v1/categories", s.handleCreateCategory)
+
+		rauth.With(s.RequirePermission(PermUsersManage)).Get("/api/v1/settings/security", s.handleGetSecuritySettings)
 	})
+	go s.runAccountPurge(context.Background())
+
 	slog.Info("Server starting", "address", s.addr)
 	if err := http.ListenAndServe(s.addr, r); err != nil {
 		slog.Error("Failed to start server", "error", err)
@@ -0,0 +1,277 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/dukunuu/hackathon_backend/db"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const refreshTokenBytes = 32
+
+// RefreshTokenRequest defines the expected JSON body for refreshing an access token.
+// swagger:model RefreshTokenRequest
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" example:"5q8b0Qm1..."`
+}
+
+// LogoutRequest defines the optional JSON body for logging out.
+// swagger:model LogoutRequest
+type LogoutRequest struct {
+	AllSessions bool `json:"all_sessions" example:"false"`
+}
+
+// sessionTokens is the token pair issueSession hands back to handlers.
+type sessionTokens struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time
+}
+
+// newOpaqueToken returns a random URL-safe token and the hash that is
+// persisted in its place.
+func newOpaqueToken() (token string, tokenHash string, err error) {
+	buf := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	token = base64.RawURLEncoding.EncodeToString(buf)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// clientIP returns the caller's address as resolved by middleware.RealIP.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// amrFor lists the authentication methods behind a session for the amr claim.
+func amrFor(mfaVerified bool) []string {
+	if mfaVerified {
+		return []string{"pwd", "otp"}
+	}
+	return []string{"pwd"}
+}
+
+// issueSession starts a new server-side session for the user and returns an
+// access token bound to it together with the first refresh token.
+func (s *Server) issueSession(ctx context.Context, r *http.Request, userID pgtype.UUID, email string, role db.UserRole, mfaVerified bool) (sessionTokens, error) {
+	refreshToken, refreshHash, err := newOpaqueToken()
+	if err != nil {
+		return sessionTokens{}, err
+	}
+
+	session, err := s.db.CreateSession(ctx, db.CreateSessionParams{
+		UserID:           userID,
+		RefreshTokenHash: refreshHash,
+		UserAgent:        toPgtypeText(r.UserAgent()),
+		IpAddress:        toPgtypeText(clientIP(r)),
+		ExpiresAt:        pgtype.Timestamptz{Time: time.Now().Add(s.refreshTokenTTL), Valid: true},
+		MfaVerified:      mfaVerified,
+	})
+	if err != nil {
+		return sessionTokens{}, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	expiresAt := time.Now().Add(s.accessTokenTTL)
+	accessToken, err := generateJWT(userID, session.ID, email, role, amrFor(session.MfaVerified), s.signingKeys, expiresAt)
+	if err != nil {
+		return sessionTokens{}, fmt.Errorf("failed to generate access token: %w", err)
+	}
+
+	return sessionTokens{AccessToken: accessToken, RefreshToken: refreshToken, ExpiresAt: expiresAt}, nil
+}
+
+// handleRefreshToken exchanges a refresh token for a new access/refresh token pair.
+// @Summary Refresh access token
+// @Description Exchanges a valid refresh token for a new short-lived access token and a new refresh token.
+// @Description Refresh tokens are single-use: presenting one that was already rotated revokes the whole session.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param refresh body RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} TokenResponseDTO "New token pair"
+// @Failure 400 {object} ErrorResponse "Invalid request payload or missing refresh token"
+// @Failure 401 {object} ErrorResponse "Refresh token invalid, expired, revoked or reused"
+// @Failure 500 {object} ErrorResponse "Failed to refresh token"
+// @Router /auth/refresh [post]
+func (s *Server) handleRefreshToken(w http.ResponseWriter, r *http.Request) {
+	var req RefreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	if req.RefreshToken == "" {
+		respondWithError(w, http.StatusBadRequest, "Refresh token is required")
+		return
+	}
+
+	presentedHash := hashToken(req.RefreshToken)
+	session, err := s.db.GetSessionByRefreshTokenHash(r.Context(), presentedHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			s.handleRefreshTokenReuse(r.Context(), presentedHash)
+			respondWithError(w, http.StatusUnauthorized, "Invalid refresh token")
+			return
+		}
+		slog.Error("Failed to look up session by refresh token", "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to refresh token")
+		return
+	}
+
+	if session.RevokedAt.Valid || !session.ExpiresAt.Time.After(time.Now()) {
+		respondWithError(w, http.StatusUnauthorized, "Session has been revoked or has expired")
+		return
+	}
+
+	user, err := s.db.GetUserByID(r.Context(), session.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			respondWithError(w, http.StatusUnauthorized, "Invalid refresh token")
+			return
+		}
+		slog.Error("Failed to fetch user for token refresh", "error", err, "userID", session.UserID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to refresh token")
+		return
+	}
+
+	newRefreshToken, newRefreshHash, err := newOpaqueToken()
+	if err != nil {
+		slog.Error("Failed to generate refresh token", "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to refresh token")
+		return
+	}
+
+	rotated, err := s.db.RotateSessionToken(r.Context(), db.RotateSessionTokenParams{
+		NewTokenHash:     newRefreshHash,
+		ID:               session.ID,
+		CurrentTokenHash: presentedHash,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			// Another request rotated or revoked the session first.
+			respondWithError(w, http.StatusUnauthorized, "Invalid refresh token")
+			return
+		}
+		slog.Error("Failed to rotate refresh token", "error", err, "sessionID", session.ID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to refresh token")
+		return
+	}
+
+	expiresAt := time.Now().Add(s.accessTokenTTL)
+	accessToken, err := generateJWT(user.ID, rotated.ID, user.Email, user.Role, amrFor(rotated.MfaVerified), s.signingKeys, expiresAt)
+	if err != nil {
+		slog.Error("Failed to generate token", "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to generate token")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, TokenResponseDTO{
+		Token:        accessToken,
+		RefreshToken: newRefreshToken,
+		ExpiresAt:    expiresAt,
+	})
+}
+
+// handleRefreshTokenReuse revokes the session a rotated-away refresh token
+// belonged to. A legitimate client never presents the same token twice, so
+// this means the token was stolen.
+func (s *Server) handleRefreshTokenReuse(ctx context.Context, presentedHash string) {
+	session, err := s.db.GetSessionByPreviousTokenHash(ctx, toPgtypeText(presentedHash))
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) && !errors.Is(err, pgx.ErrNoRows) {
+			slog.Error("Failed to check for refresh token reuse", "error", err)
+		}
+		return
+	}
+	slog.Warn("Refresh token reuse detected, revoking session", "sessionID", session.ID, "userID", session.UserID)
+	if err := s.db.RevokeSession(ctx, session.ID); err != nil {
+		slog.Error("Failed to revoke session after refresh token reuse", "error", err, "sessionID", session.ID)
+	}
+}
+
+// handleLogout revokes the current session, or every session of the user.
+// @Summary Logout
+// @Description Revokes the session the access token belongs to. With 'all_sessions' set, every session of the user is revoked.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param logout body LogoutRequest false "Logout options"
+// @Success 200 {object} map[string]string "message: Logged out successfully"
+// @Failure 400 {object} ErrorResponse "Invalid request payload"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 500 {object} ErrorResponse "Failed to logout"
+// @Security BearerAuth
+// @Router /auth/logout [post]
+func (s *Server) handleLogout(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromContext(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	sessionID, err := getSessionIDFromContext(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req LogoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	if req.AllSessions {
+		err = s.db.RevokeUserSessions(r.Context(), userID)
+	} else {
+		err = s.db.RevokeSession(r.Context(), sessionID)
+	}
+	if err != nil {
+		slog.Error("Failed to revoke session on logout", "error", err, "userID", userID, "sessionID", sessionID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to logout")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "Logged out successfully"})
+}
+
+// revokeOtherSessions logs the user out everywhere except the session making
+// the request. Failures are logged rather than surfaced because the
+// credential change that triggered it has already been committed.
+func (s *Server) revokeOtherSessions(ctx context.Context, userID pgtype.UUID) {
+	sessionID, err := getSessionIDFromContext(ctx)
+	if err != nil {
+		// No current session to keep, so revoke them all.
+		if err := s.db.RevokeUserSessions(ctx, userID); err != nil {
+			slog.Error("Failed to revoke user sessions", "error", err, "userID", userID)
+		}
+		return
+	}
+	if err := s.db.RevokeOtherUserSessions(ctx, db.RevokeOtherUserSessionsParams{UserID: userID, ID: sessionID}); err != nil {
+		slog.Error("Failed to revoke other user sessions", "error", err, "userID", userID)
+	}
+}
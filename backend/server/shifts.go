@@ -0,0 +1,614 @@
+package server
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dukunuu/hackathon_backend/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	// shiftCheckInLead is how early before its start a shift can be checked in to.
+	shiftCheckInLead        = 30 * time.Minute
+	maxShiftDuration        = 24 * time.Hour
+	maxMeetingPointLength   = 500
+	shiftCapacityConstraint = "chk_post_shifts_capacity"
+
+	errNotShiftOrganizer = "Only the author of the post can manage its shifts"
+)
+
+// CreateShiftRequest defines the JSON body for adding a shift to a post.
+// swagger:model CreateShiftRequest
+type CreateShiftRequest struct {
+	StartsAt     time.Time `json:"starts_at" example:"2025-06-01T09:00:00+08:00"`
+	EndsAt       time.Time `json:"ends_at" example:"2025-06-01T12:00:00+08:00"`
+	Capacity     int32     `json:"capacity" example:"5"`
+	MeetingPoint string    `json:"meeting_point,omitempty" example:"Main gate of the park"`
+	MeetingLat   float64   `json:"meeting_lat,omitempty" example:"47.9187"`
+	MeetingLng   float64   `json:"meeting_lng,omitempty" example:"106.9170"`
+}
+
+// ShiftCheckRequest is the optional body of a check-in or check-out.
+// swagger:model ShiftCheckRequest
+type ShiftCheckRequest struct {
+	Lat float64 `json:"lat,omitempty" example:"47.9188"`
+	Lng float64 `json:"lng,omitempty" example:"106.9171"`
+}
+
+// PostShiftDTO is a time slot of a post.
+// swagger:model PostShiftDTO
+type PostShiftDTO struct {
+	ID           uuid.UUID `json:"id" format:"uuid"`
+	PostID       uuid.UUID `json:"post_id" format:"uuid"`
+	StartsAt     time.Time `json:"starts_at"`
+	EndsAt       time.Time `json:"ends_at"`
+	MeetingPoint string    `json:"meeting_point,omitempty"`
+	MeetingLat   float64   `json:"meeting_lat,omitempty"`
+	MeetingLng   float64   `json:"meeting_lng,omitempty"`
+	Capacity     int32     `json:"capacity" example:"5"`
+	SignedUp     int32     `json:"signed_up" example:"3"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// ShiftSignupDTO is a volunteer's place on a shift. The name fields are only
+// filled in for the post's organizer.
+// swagger:model ShiftSignupDTO
+type ShiftSignupDTO struct {
+	ID           uuid.UUID  `json:"id" format:"uuid"`
+	ShiftID      uuid.UUID  `json:"shift_id" format:"uuid"`
+	UserID       uuid.UUID  `json:"user_id,omitempty" format:"uuid"`
+	FirstName    string     `json:"first_name,omitempty"`
+	LastName     string     `json:"last_name,omitempty"`
+	CheckedInAt  *time.Time `json:"checked_in_at,omitempty"`
+	CheckInLat   float64    `json:"check_in_lat,omitempty"`
+	CheckInLng   float64    `json:"check_in_lng,omitempty"`
+	CheckedOutAt *time.Time `json:"checked_out_at,omitempty"`
+	CheckOutLat  float64    `json:"check_out_lat,omitempty"`
+	CheckOutLng  float64    `json:"check_out_lng,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+func toPostShiftDTO(shift db.PostShift) PostShiftDTO {
+	return PostShiftDTO{
+		ID:           shift.ID.Bytes,
+		PostID:       shift.PostID.Bytes,
+		StartsAt:     shift.StartsAt.Time,
+		EndsAt:       shift.EndsAt.Time,
+		MeetingPoint: shift.MeetingPoint.String,
+		MeetingLat:   shift.MeetingLat.Float64,
+		MeetingLng:   shift.MeetingLng.Float64,
+		Capacity:     shift.Capacity,
+		SignedUp:     shift.SignedUp,
+		CreatedAt:    shift.CreatedAt.Time,
+	}
+}
+
+func toShiftSignupDTO(signup db.ShiftSignup, userID uuid.UUID) ShiftSignupDTO {
+	dto := ShiftSignupDTO{
+		ID:          signup.ID.Bytes,
+		ShiftID:     signup.ShiftID.Bytes,
+		UserID:      userID,
+		CheckInLat:  signup.CheckInLat.Float64,
+		CheckInLng:  signup.CheckInLng.Float64,
+		CheckOutLat: signup.CheckOutLat.Float64,
+		CheckOutLng: signup.CheckOutLng.Float64,
+		CreatedAt:   signup.CreatedAt.Time,
+	}
+	if signup.CheckedInAt.Valid {
+		dto.CheckedInAt = &signup.CheckedInAt.Time
+	}
+	if signup.CheckedOutAt.Valid {
+		dto.CheckedOutAt = &signup.CheckedOutAt.Time
+	}
+	return dto
+}
+
+// lastShiftOfPost reports whether checking out of shift at now finishes a
+// volunteer's work on the post: no other shift of the post ends later than
+// now. Completed volunteers cannot sign up for shifts, so a volunteer who
+// still has shifts ahead stays approved.
+func lastShiftOfPost(shifts []db.PostShift, shift db.PostShift, now time.Time) bool {
+	for _, other := range shifts {
+		if other.ID != shift.ID && other.EndsAt.Time.After(now) {
+			return false
+		}
+	}
+	return true
+}
+
+// shiftFromPath loads the shift named in the path, which must belong to the
+// post named there. On failure it writes the error response and reports
+// false.
+func (s *Server) shiftFromPath(w http.ResponseWriter, r *http.Request) (db.PostShift, bool) {
+	postID, err := uuid.Parse(r.PathValue("postId"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid post ID format")
+		return db.PostShift{}, false
+	}
+	shiftID, err := uuid.Parse(r.PathValue("shiftId"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid shift ID format")
+		return db.PostShift{}, false
+	}
+
+	shift, err := s.db.GetPostShift(r.Context(), db.GetPostShiftParams{ID: toPgtypeUUID(shiftID), PostID: toPgtypeUUID(postID)})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Shift not found")
+			return db.PostShift{}, false
+		}
+		slog.Error("Failed to get shift", "error", err, "postID", postID, "shiftID", shiftID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve shift")
+		return db.PostShift{}, false
+	}
+	return shift, true
+}
+
+// callerShiftSignup loads the caller's sign-up for a shift. On failure it
+// writes the error response and reports false.
+func (s *Server) callerShiftSignup(w http.ResponseWriter, r *http.Request, shift db.PostShift) (db.ShiftSignup, pgtype.UUID, bool) {
+	authUserID, err := getUserIDFromContext(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return db.ShiftSignup{}, pgtype.UUID{}, false
+	}
+	signup, err := s.db.GetShiftSignup(r.Context(), db.GetShiftSignupParams{ShiftID: shift.ID, UserID: authUserID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "You are not signed up for this shift")
+			return db.ShiftSignup{}, pgtype.UUID{}, false
+		}
+		slog.Error("Failed to get shift sign-up", "error", err, "shiftID", shift.ID, "userID", authUserID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve shift sign-up")
+		return db.ShiftSignup{}, pgtype.UUID{}, false
+	}
+	return signup, authUserID, true
+}
+
+// decodeShiftCheck reads the optional location of a check-in or check-out.
+// On failure it writes the error response and reports false.
+func decodeShiftCheck(w http.ResponseWriter, r *http.Request) (pgtype.Float8, pgtype.Float8, bool) {
+	var req ShiftCheckRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload: "+err.Error())
+		return pgtype.Float8{}, pgtype.Float8{}, false
+	}
+	defer r.Body.Close()
+	lat, lng, err := postLocation(req.Lat, req.Lng)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid location: "+err.Error())
+		return pgtype.Float8{}, pgtype.Float8{}, false
+	}
+	return lat, lng, true
+}
+
+// handleCreatePostShift adds a shift to a post.
+// @Summary Add a shift to a post
+// @Description Adds a time slot with its own capacity and optional meeting point. Approved volunteers of the post sign up
+// @Description for shifts. A shift lasts at most 24 hours and its capacity cannot exceed the post's max_volunteers.
+// @Description Only the author of a pending or in-progress post can add shifts.
+// @Tags Posts, Volunteers
+// @Accept json
+// @Produce json
+// @Param postId path string true "Post ID" format(uuid)
+// @Param shift body CreateShiftRequest true "Shift details"
+// @Success 201 {object} PostShiftDTO "Shift created"
+// @Failure 400 {object} ErrorResponse "Invalid request payload, times, capacity or meeting point"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Not the author of the post"
+// @Failure 404 {object} ErrorResponse "Post not found"
+// @Failure 409 {object} ErrorResponse "The post is no longer active"
+// @Failure 500 {object} ErrorResponse "Failed to create shift"
+// @Security BearerAuth
+// @Router /posts/{postId}/shifts [post]
+func (s *Server) handleCreatePostShift(w http.ResponseWriter, r *http.Request) {
+	post, ok := s.organizerPostFromPath(w, r, errNotShiftOrganizer)
+	if !ok {
+		return
+	}
+
+	var req CreateShiftRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload: "+err.Error())
+		return
+	}
+	defer r.Body.Close()
+
+	if req.StartsAt.IsZero() || req.EndsAt.IsZero() {
+		respondWithError(w, http.StatusBadRequest, "starts_at and ends_at are required")
+		return
+	}
+	if !req.EndsAt.After(req.StartsAt) {
+		respondWithError(w, http.StatusBadRequest, "ends_at must be after starts_at")
+		return
+	}
+	if req.EndsAt.Sub(req.StartsAt) > maxShiftDuration {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("A shift can last at most %d hours", int(maxShiftDuration.Hours())))
+		return
+	}
+	if !req.EndsAt.After(time.Now()) {
+		respondWithError(w, http.StatusBadRequest, "ends_at must be in the future")
+		return
+	}
+	if req.Capacity < 1 || req.Capacity > post.MaxVolunteers {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("capacity must be between 1 and the post's max_volunteers (%d)", post.MaxVolunteers))
+		return
+	}
+	if utf8.RuneCountInString(req.MeetingPoint) > maxMeetingPointLength {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("meeting_point must be at most %d characters", maxMeetingPointLength))
+		return
+	}
+	meetingLat, meetingLng, err := postLocation(req.MeetingLat, req.MeetingLng)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid meeting location: "+err.Error())
+		return
+	}
+	if post.Status != postStatusPending && post.Status != postStatusInProgress {
+		respondWithError(w, http.StatusConflict, "Shifts can only be added to pending or in-progress posts")
+		return
+	}
+
+	shift, err := s.db.CreatePostShift(r.Context(), db.CreatePostShiftParams{
+		PostID:       post.ID,
+		StartsAt:     pgtype.Timestamptz{Time: req.StartsAt, Valid: true},
+		EndsAt:       pgtype.Timestamptz{Time: req.EndsAt, Valid: true},
+		MeetingPoint: toPgtypeText(req.MeetingPoint),
+		MeetingLat:   meetingLat,
+		MeetingLng:   meetingLng,
+		Capacity:     req.Capacity,
+	})
+	if err != nil {
+		slog.Error("Failed to create shift", "error", err, "postID", post.ID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to create shift")
+		return
+	}
+	respondWithJSON(w, http.StatusCreated, toPostShiftDTO(shift))
+}
+
+// handleListPostShifts lists the shifts of a post.
+// @Summary List post shifts
+// @Description Returns the shifts of a post in start order, with how many volunteers signed up for each.
+// @Tags Posts, Volunteers
+// @Produce json
+// @Param postId path string true "Post ID" format(uuid)
+// @Success 200 {array} PostShiftDTO "Shifts of the post"
+// @Failure 400 {object} ErrorResponse "Invalid post ID format"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 500 {object} ErrorResponse "Failed to retrieve shifts"
+// @Security BearerAuth
+// @Router /posts/{postId}/shifts [get]
+func (s *Server) handleListPostShifts(w http.ResponseWriter, r *http.Request) {
+	postID, err := uuid.Parse(r.PathValue("postId"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid post ID format")
+		return
+	}
+
+	shifts, err := s.db.ListPostShifts(r.Context(), toPgtypeUUID(postID))
+	if err != nil {
+		slog.Error("Failed to list shifts", "error", err, "postID", postID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve shifts")
+		return
+	}
+	dtos := make([]PostShiftDTO, len(shifts))
+	for i, shift := range shifts {
+		dtos[i] = toPostShiftDTO(shift)
+	}
+	respondWithJSON(w, http.StatusOK, dtos)
+}
+
+// handleDeletePostShift removes a shift that has not started yet.
+// @Summary Delete a shift
+// @Description Removes a shift and its sign-ups. Shifts that have started are kept, since their check-ins count towards volunteered hours.
+// @Description Only the author of the post can delete shifts.
+// @Tags Posts, Volunteers
+// @Produce json
+// @Param postId path string true "Post ID" format(uuid)
+// @Param shiftId path string true "Shift ID" format(uuid)
+// @Success 200 {object} map[string]string "message: Shift deleted successfully"
+// @Failure 400 {object} ErrorResponse "Invalid post or shift ID format"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Not the author of the post"
+// @Failure 404 {object} ErrorResponse "Post or shift not found"
+// @Failure 409 {object} ErrorResponse "The shift has already started"
+// @Failure 500 {object} ErrorResponse "Failed to delete shift"
+// @Security BearerAuth
+// @Router /posts/{postId}/shifts/{shiftId} [delete]
+func (s *Server) handleDeletePostShift(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.organizerPostFromPath(w, r, errNotShiftOrganizer); !ok {
+		return
+	}
+	shift, ok := s.shiftFromPath(w, r)
+	if !ok {
+		return
+	}
+	if !time.Now().Before(shift.StartsAt.Time) {
+		respondWithError(w, http.StatusConflict, "Shifts cannot be deleted once they have started")
+		return
+	}
+
+	n, err := s.db.DeletePostShift(r.Context(), db.DeletePostShiftParams{ID: shift.ID, PostID: shift.PostID})
+	if err != nil {
+		slog.Error("Failed to delete shift", "error", err, "shiftID", shift.ID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to delete shift")
+		return
+	}
+	if n == 0 {
+		respondWithError(w, http.StatusNotFound, "Shift not found")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "Shift deleted successfully"})
+}
+
+// handleListShiftSignups lists who signed up for a shift.
+// @Summary List shift sign-ups
+// @Description Returns the volunteers signed up for a shift with their check-in and check-out. Only the author of the post can see them.
+// @Tags Posts, Volunteers
+// @Produce json
+// @Param postId path string true "Post ID" format(uuid)
+// @Param shiftId path string true "Shift ID" format(uuid)
+// @Success 200 {array} ShiftSignupDTO "Sign-ups in the order they were made"
+// @Failure 400 {object} ErrorResponse "Invalid post or shift ID format"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Not the author of the post"
+// @Failure 404 {object} ErrorResponse "Post or shift not found"
+// @Failure 500 {object} ErrorResponse "Failed to retrieve sign-ups"
+// @Security BearerAuth
+// @Router /posts/{postId}/shifts/{shiftId}/signups [get]
+func (s *Server) handleListShiftSignups(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.organizerPostFromPath(w, r, errNotShiftOrganizer); !ok {
+		return
+	}
+	shift, ok := s.shiftFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	rows, err := s.db.ListShiftSignups(r.Context(), shift.ID)
+	if err != nil {
+		slog.Error("Failed to list shift sign-ups", "error", err, "shiftID", shift.ID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve sign-ups")
+		return
+	}
+	dtos := make([]ShiftSignupDTO, len(rows))
+	for i, row := range rows {
+		dtos[i] = toShiftSignupDTO(db.ShiftSignup{
+			ID:           row.ID,
+			ShiftID:      row.ShiftID,
+			VolunteerID:  row.VolunteerID,
+			CheckedInAt:  row.CheckedInAt,
+			CheckInLat:   row.CheckInLat,
+			CheckInLng:   row.CheckInLng,
+			CheckedOutAt: row.CheckedOutAt,
+			CheckOutLat:  row.CheckOutLat,
+			CheckOutLng:  row.CheckOutLng,
+			CreatedAt:    row.CreatedAt,
+		}, row.UserID.Bytes)
+		dtos[i].FirstName = row.FirstName
+		dtos[i].LastName = row.LastName
+	}
+	respondWithJSON(w, http.StatusOK, dtos)
+}
+
+// handleSignUpForShift signs the caller up for a shift.
+// @Summary Sign up for a shift
+// @Description Takes a place on a shift. Only approved volunteers of the post can sign up, and only until the shift ends.
+// @Tags Posts, Volunteers
+// @Produce json
+// @Param postId path string true "Post ID" format(uuid)
+// @Param shiftId path string true "Shift ID" format(uuid)
+// @Success 201 {object} ShiftSignupDTO "Signed up"
+// @Failure 400 {object} ErrorResponse "Invalid post or shift ID format"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Not an approved volunteer of the post"
+// @Failure 404 {object} ErrorResponse "Shift not found"
+// @Failure 409 {object} ErrorResponse "Already signed up, shift is full or shift is over"
+// @Failure 500 {object} ErrorResponse "Failed to sign up"
+// @Security BearerAuth
+// @Router /posts/{postId}/shifts/{shiftId}/signup [post]
+func (s *Server) handleSignUpForShift(w http.ResponseWriter, r *http.Request) {
+	authUserID, err := getUserIDFromContext(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	shift, ok := s.shiftFromPath(w, r)
+	if !ok {
+		return
+	}
+	if !time.Now().Before(shift.EndsAt.Time) {
+		respondWithError(w, http.StatusConflict, "The shift is over")
+		return
+	}
+
+	signup, err := s.db.CreateShiftSignup(r.Context(), db.CreateShiftSignupParams{ShiftID: shift.ID, UserID: authUserID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusForbidden, "Only approved volunteers of the post can sign up for its shifts")
+			return
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch {
+			case pgErr.Code == "23505": // Unique violation
+				respondWithError(w, http.StatusConflict, "You are already signed up for this shift")
+				return
+			case pgErr.Code == "23514" && pgErr.ConstraintName == shiftCapacityConstraint:
+				respondWithError(w, http.StatusConflict, "The shift is full")
+				return
+			}
+		}
+		slog.Error("Failed to sign up for shift", "error", err, "shiftID", shift.ID, "userID", authUserID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to sign up")
+		return
+	}
+	respondWithJSON(w, http.StatusCreated, toShiftSignupDTO(signup, authUserID.Bytes))
+}
+
+// handleWithdrawFromShift gives up the caller's place on a shift.
+// @Summary Withdraw from a shift
+// @Description Gives up a place on a shift that has not been checked in to.
+// @Tags Posts, Volunteers
+// @Produce json
+// @Param postId path string true "Post ID" format(uuid)
+// @Param shiftId path string true "Shift ID" format(uuid)
+// @Success 200 {object} map[string]string "message: Withdrawn from shift"
+// @Failure 400 {object} ErrorResponse "Invalid post or shift ID format"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 404 {object} ErrorResponse "Shift not found or not signed up"
+// @Failure 409 {object} ErrorResponse "Already checked in"
+// @Failure 500 {object} ErrorResponse "Failed to withdraw"
+// @Security BearerAuth
+// @Router /posts/{postId}/shifts/{shiftId}/signup [delete]
+func (s *Server) handleWithdrawFromShift(w http.ResponseWriter, r *http.Request) {
+	shift, ok := s.shiftFromPath(w, r)
+	if !ok {
+		return
+	}
+	signup, _, ok := s.callerShiftSignup(w, r, shift)
+	if !ok {
+		return
+	}
+
+	n, err := s.db.DeleteShiftSignup(r.Context(), signup.ID)
+	if err != nil {
+		slog.Error("Failed to withdraw from shift", "error", err, "signupID", signup.ID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to withdraw")
+		return
+	}
+	if n == 0 {
+		respondWithError(w, http.StatusConflict, "You have already checked in to this shift")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "Withdrawn from shift"})
+}
+
+// handleShiftCheckIn records the caller's arrival at a shift.
+// @Summary Check in to a shift
+// @Description Records the check-in time and, optionally, where the volunteer is. Check-in opens 30 minutes before the shift starts and closes when it ends.
+// @Tags Posts, Volunteers
+// @Accept json
+// @Produce json
+// @Param postId path string true "Post ID" format(uuid)
+// @Param shiftId path string true "Shift ID" format(uuid)
+// @Param location body ShiftCheckRequest false "Optional current location"
+// @Success 200 {object} ShiftSignupDTO "Checked in"
+// @Failure 400 {object} ErrorResponse "Invalid ID format or location"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 404 {object} ErrorResponse "Shift not found or not signed up"
+// @Failure 409 {object} ErrorResponse "Already checked in or outside the check-in window"
+// @Failure 500 {object} ErrorResponse "Failed to check in"
+// @Security BearerAuth
+// @Router /posts/{postId}/shifts/{shiftId}/check-in [post]
+func (s *Server) handleShiftCheckIn(w http.ResponseWriter, r *http.Request) {
+	shift, ok := s.shiftFromPath(w, r)
+	if !ok {
+		return
+	}
+	signup, authUserID, ok := s.callerShiftSignup(w, r, shift)
+	if !ok {
+		return
+	}
+	lat, lng, ok := decodeShiftCheck(w, r)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	if now.Before(shift.StartsAt.Time.Add(-shiftCheckInLead)) {
+		respondWithError(w, http.StatusConflict, fmt.Sprintf("Check-in opens %d minutes before the shift starts", int(shiftCheckInLead.Minutes())))
+		return
+	}
+	if !now.Before(shift.EndsAt.Time) {
+		respondWithError(w, http.StatusConflict, "The shift is over")
+		return
+	}
+
+	checkedIn, err := s.db.CheckInShift(r.Context(), db.CheckInShiftParams{Lat: lat, Lng: lng, ID: signup.ID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusConflict, "You have already checked in to this shift")
+			return
+		}
+		slog.Error("Failed to check in to shift", "error", err, "signupID", signup.ID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to check in")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, toShiftSignupDTO(checkedIn, authUserID.Bytes))
+}
+
+// handleShiftCheckOut records the caller leaving a shift.
+// @Summary Check out of a shift
+// @Description Records the check-out time and, optionally, where the volunteer is. The time inside the shift between check-in
+// @Description and check-out counts towards the user's volunteered hours. Once no other shift of the post is still to come,
+// @Description a volunteer who has checked out of every shift they signed up for on the post is marked completed.
+// @Tags Posts, Volunteers
+// @Accept json
+// @Produce json
+// @Param postId path string true "Post ID" format(uuid)
+// @Param shiftId path string true "Shift ID" format(uuid)
+// @Param location body ShiftCheckRequest false "Optional current location"
+// @Success 200 {object} ShiftSignupDTO "Checked out"
+// @Failure 400 {object} ErrorResponse "Invalid ID format or location"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 404 {object} ErrorResponse "Shift not found or not signed up"
+// @Failure 409 {object} ErrorResponse "Not checked in, or already checked out"
+// @Failure 500 {object} ErrorResponse "Failed to check out"
+// @Security BearerAuth
+// @Router /posts/{postId}/shifts/{shiftId}/check-out [post]
+func (s *Server) handleShiftCheckOut(w http.ResponseWriter, r *http.Request) {
+	shift, ok := s.shiftFromPath(w, r)
+	if !ok {
+		return
+	}
+	signup, authUserID, ok := s.callerShiftSignup(w, r, shift)
+	if !ok {
+		return
+	}
+	lat, lng, ok := decodeShiftCheck(w, r)
+	if !ok {
+		return
+	}
+	if !signup.CheckedInAt.Valid {
+		respondWithError(w, http.StatusConflict, "You have not checked in to this shift")
+		return
+	}
+
+	var checkedOut db.ShiftSignup
+	err := s.db.ExecTx(r.Context(), func(q *db.Queries) error {
+		var err error
+		if checkedOut, err = q.CheckOutShift(r.Context(), db.CheckOutShiftParams{Lat: lat, Lng: lng, ID: signup.ID}); err != nil {
+			return err
+		}
+		shifts, err := q.ListPostShifts(r.Context(), shift.PostID)
+		if err != nil {
+			return err
+		}
+		if !lastShiftOfPost(shifts, shift, checkedOut.CheckedOutAt.Time) {
+			return nil
+		}
+		_, err = q.CompleteShiftVolunteer(r.Context(), signup.VolunteerID)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusConflict, "You have already checked out of this shift")
+			return
+		}
+		slog.Error("Failed to check out of shift", "error", err, "signupID", signup.ID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to check out")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, toShiftSignupDTO(checkedOut, authUserID.Bytes))
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dukunuu/hackathon_backend/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func testShift(starts, ends time.Time) db.PostShift {
+	return db.PostShift{
+		ID:       pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		StartsAt: pgtype.Timestamptz{Time: starts, Valid: true},
+		EndsAt:   pgtype.Timestamptz{Time: ends, Valid: true},
+	}
+}
+
+func TestLastShiftOfPostTwoShiftsInSequence(t *testing.T) {
+	day := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	morning := testShift(day.Add(9*time.Hour), day.Add(12*time.Hour))
+	afternoon := testShift(day.Add(13*time.Hour), day.Add(16*time.Hour))
+	shifts := []db.PostShift{morning, afternoon}
+
+	// Checking out of the morning shift leaves the afternoon one to work, so
+	// the volunteer stays approved and can still sign up for it.
+	if lastShiftOfPost(shifts, morning, day.Add(12*time.Hour)) {
+		t.Error("checking out of the morning shift completed the volunteer")
+	}
+	// Checking out of the afternoon shift finishes their work on the post,
+	// even when they leave a little early.
+	if !lastShiftOfPost(shifts, afternoon, day.Add(16*time.Hour)) {
+		t.Error("checking out of the afternoon shift did not complete the volunteer")
+	}
+	if !lastShiftOfPost(shifts, afternoon, day.Add(15*time.Hour+50*time.Minute)) {
+		t.Error("checking out of the afternoon shift early did not complete the volunteer")
+	}
+}
+
+func TestLastShiftOfPost(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	current := testShift(now.Add(-3*time.Hour), now)
+
+	tests := []struct {
+		name   string
+		others []db.PostShift
+		want   bool
+	}{
+		{name: "only shift", want: true},
+		{name: "earlier shift", others: []db.PostShift{testShift(now.Add(-26*time.Hour), now.Add(-23*time.Hour))}, want: true},
+		{name: "shift ending now", others: []db.PostShift{testShift(now.Add(-1*time.Hour), now)}, want: true},
+		{name: "overlapping shift still running", others: []db.PostShift{testShift(now.Add(-1*time.Hour), now.Add(time.Hour))}, want: false},
+		{name: "later shift", others: []db.PostShift{testShift(now.Add(24*time.Hour), now.Add(27*time.Hour))}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shifts := append([]db.PostShift{current}, tt.others...)
+			if got := lastShiftOfPost(shifts, current, now); got != tt.want {
+				t.Errorf("lastShiftOfPost = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
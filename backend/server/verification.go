@@ -0,0 +1,452 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/dukunuu/hackathon_backend/db"
+	"github.com/dukunuu/hackathon_backend/mail"
+	"github.com/dukunuu/hackathon_backend/password"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	emailVerificationTokenTTL = 48 * time.Hour
+	emailChangeTokenTTL       = 24 * time.Hour
+	passwordResetTokenTTL     = 1 * time.Hour
+)
+
+// VerifyEmailRequest defines the expected JSON body for redeeming an emailed token.
+// swagger:model VerifyEmailRequest
+type VerifyEmailRequest struct {
+	Token string `json:"token" example:"Xq3v9kT0..."`
+}
+
+// ForgotPasswordRequest defines the expected JSON body for requesting a password reset.
+// swagger:model ForgotPasswordRequest
+type ForgotPasswordRequest struct {
+	Email string `json:"email" example:"john.doe@example.com"`
+}
+
+// ResetPasswordRequest defines the expected JSON body for resetting a forgotten password.
+// swagger:model ResetPasswordRequest
+type ResetPasswordRequest struct {
+	Token       string `json:"token" example:"Xq3v9kT0..."`
+	NewPassword string `json:"new_password" example:"newStrongPassword456"`
+}
+
+// issueUserToken replaces any outstanding token of the given purpose with a
+// fresh one and returns the plain token to be emailed.
+func (s *Server) issueUserToken(ctx context.Context, userID pgtype.UUID, purpose db.UserTokenPurpose, newEmail string, ttl time.Duration) (string, error) {
+	if err := s.db.InvalidateUserTokens(ctx, db.InvalidateUserTokensParams{UserID: userID, Purpose: purpose}); err != nil {
+		return "", fmt.Errorf("failed to invalidate previous tokens: %w", err)
+	}
+
+	token, tokenHash, err := newOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	_, err = s.db.CreateUserToken(ctx, db.CreateUserTokenParams{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: tokenHash,
+		NewEmail:  toPgtypeText(newEmail),
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(ttl), Valid: true},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to store token: %w", err)
+	}
+	return token, nil
+}
+
+// appLink builds a link into the frontend carrying the token as a query parameter.
+func (s *Server) appLink(path, token string) string {
+	return s.appBaseURL + path + "?token=" + url.QueryEscape(token)
+}
+
+func (s *Server) sendVerificationEmail(ctx context.Context, user db.User) error {
+	token, err := s.issueUserToken(ctx, user.ID, db.UserTokenPurposeEmailVerification, "", emailVerificationTokenTTL)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Confirm your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease confirm your email address by opening the link below:\n\n%s\n\nThe link expires in %d hours.\n",
+			user.FirstName, s.appLink("/verify-email", token), int(emailVerificationTokenTTL.Hours())),
+	})
+}
+
+func (s *Server) sendEmailChangeConfirmation(ctx context.Context, user db.User, newEmail string) error {
+	token, err := s.issueUserToken(ctx, user.ID, db.UserTokenPurposeEmailChange, newEmail, emailChangeTokenTTL)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, mail.Message{
+		To:      newEmail,
+		Subject: "Confirm your new email address",
+		Body: fmt.Sprintf("Hi %s,\n\nWe received a request to change the email address of your account to this one.\nOpen the link below to confirm the change:\n\n%s\n\nThe link expires in %d hours. If you did not request this, you can ignore this email.\n",
+			user.FirstName, s.appLink("/confirm-email-change", token), int(emailChangeTokenTTL.Hours())),
+	})
+}
+
+func (s *Server) sendPasswordResetEmail(ctx context.Context, user db.User) error {
+	token, err := s.issueUserToken(ctx, user.ID, db.UserTokenPurposePasswordReset, "", passwordResetTokenTTL)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nOpen the link below to choose a new password:\n\n%s\n\nThe link expires in %d minutes. If you did not request a password reset, you can ignore this email.\n",
+			user.FirstName, s.appLink("/reset-password", token), int(passwordResetTokenTTL.Minutes())),
+	})
+}
+
+// consumeUserToken redeems a token for the given purpose. It reports false
+// after writing the error response when the token cannot be used.
+func (s *Server) consumeUserToken(w http.ResponseWriter, r *http.Request, token string, purpose db.UserTokenPurpose) (db.UserToken, bool) {
+	if token == "" {
+		respondWithError(w, http.StatusBadRequest, "Token is required")
+		return db.UserToken{}, false
+	}
+	userToken, err := s.db.ConsumeUserToken(r.Context(), db.ConsumeUserTokenParams{
+		TokenHash: hashToken(token),
+		Purpose:   purpose,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			respondWithError(w, http.StatusBadRequest, "Token is invalid, expired or has already been used")
+			return db.UserToken{}, false
+		}
+		slog.Error("Failed to consume user token", "error", err, "purpose", purpose)
+		respondWithError(w, http.StatusInternalServerError, "Failed to verify token")
+		return db.UserToken{}, false
+	}
+	return userToken, true
+}
+
+// RequireVerifiedEmail only lets the request through when the caller has
+// confirmed their email address. It must be mounted after AuthMiddleware.
+func (s *Server) RequireVerifiedEmail(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, err := getUserIDFromContext(r.Context())
+		if err != nil {
+			respondWithError(w, http.StatusUnauthorized, "Authentication required")
+			return
+		}
+		user, err := s.db.GetUserByID(r.Context(), userID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+				respondWithError(w, http.StatusUnauthorized, "User not found")
+				return
+			}
+			slog.Error("Failed to fetch user for email verification check", "error", err, "userID", userID)
+			respondWithError(w, http.StatusInternalServerError, "Failed to verify user")
+			return
+		}
+		if !user.EmailVerifiedAt.Valid {
+			respondWithError(w, http.StatusForbidden, "Please verify your email address first")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleVerifyEmail confirms the user's email address.
+// @Summary Verify email address
+// @Description Redeems the one-time token sent to the user's email address after registration.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param verification body VerifyEmailRequest true "Verification token"
+// @Success 200 {object} UserResponseDTO "Email address verified"
+// @Failure 400 {object} ErrorResponse "Invalid request payload or token invalid, expired or already used"
+// @Failure 404 {object} ErrorResponse "User not found"
+// @Failure 500 {object} ErrorResponse "Failed to verify email"
+// @Router /auth/verify-email [post]
+func (s *Server) handleVerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	userToken, ok := s.consumeUserToken(w, r, req.Token, db.UserTokenPurposeEmailVerification)
+	if !ok {
+		return
+	}
+
+	user, err := s.db.MarkUserEmailVerified(r.Context(), userToken.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "User not found")
+			return
+		}
+		slog.Error("Failed to mark email verified", "error", err, "userID", userToken.UserID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to verify email")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, ToUserResponseDTO(user))
+}
+
+// handleResendVerificationEmail sends a new verification link to the authenticated user.
+// @Summary Resend verification email
+// @Description Sends a new verification link to the current user's email address. Previously sent links stop working.
+// @Tags Authentication
+// @Produce json
+// @Success 202 {object} map[string]string "message: Verification email sent"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 404 {object} ErrorResponse "User not found"
+// @Failure 409 {object} ErrorResponse "Email address is already verified"
+// @Failure 500 {object} ErrorResponse "Failed to send verification email"
+// @Security BearerAuth
+// @Router /auth/verify-email/resend [post]
+func (s *Server) handleResendVerificationEmail(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromContext(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	user, err := s.db.GetUserByID(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "User not found")
+			return
+		}
+		slog.Error("Failed to fetch user for verification email", "error", err, "userID", userID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to send verification email")
+		return
+	}
+	if user.EmailVerifiedAt.Valid {
+		respondWithError(w, http.StatusConflict, "Email address is already verified")
+		return
+	}
+
+	if err := s.sendVerificationEmail(r.Context(), user); err != nil {
+		slog.Error("Failed to send verification email", "error", err, "userID", userID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to send verification email")
+		return
+	}
+	respondWithJSON(w, http.StatusAccepted, map[string]string{"message": "Verification email sent"})
+}
+
+// handleConfirmEmailChange applies a pending email change.
+// @Summary Confirm email change
+// @Description Redeems the one-time token sent to the new address by PUT /users/me/email and switches the account to it.
+// @Description All sessions of the user are revoked and the previous address is notified.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param confirmation body VerifyEmailRequest true "Email change token"
+// @Success 200 {object} UserResponseDTO "Email address changed"
+// @Failure 400 {object} ErrorResponse "Invalid request payload or token invalid, expired or already used"
+// @Failure 404 {object} ErrorResponse "User not found"
+// @Failure 409 {object} ErrorResponse "This email is already in use"
+// @Failure 500 {object} ErrorResponse "Failed to change email"
+// @Router /auth/confirm-email-change [post]
+func (s *Server) handleConfirmEmailChange(w http.ResponseWriter, r *http.Request) {
+	var req VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	userToken, ok := s.consumeUserToken(w, r, req.Token, db.UserTokenPurposeEmailChange)
+	if !ok {
+		return
+	}
+	if !userToken.NewEmail.Valid || userToken.NewEmail.String == "" {
+		slog.Error("Email change token without a new email", "tokenID", userToken.ID)
+		respondWithError(w, http.StatusBadRequest, "Token is invalid, expired or has already been used")
+		return
+	}
+
+	oldUser, err := s.db.GetUserByID(r.Context(), userToken.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "User not found")
+			return
+		}
+		slog.Error("Failed to fetch user for email change", "error", err, "userID", userToken.UserID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to change email")
+		return
+	}
+
+	updatedUser, err := s.db.UpdateUserEmail(r.Context(), db.UpdateUserEmailParams{
+		ID:    userToken.UserID,
+		Email: userToken.NewEmail.String,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			respondWithError(w, http.StatusConflict, "This email is already in use.")
+			return
+		}
+		slog.Error("Failed to update user email", "error", err, "userID", userToken.UserID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to change email")
+		return
+	}
+
+	if err := s.db.RevokeUserSessions(r.Context(), updatedUser.ID); err != nil {
+		slog.Error("Failed to revoke user sessions after email change", "error", err, "userID", updatedUser.ID)
+	}
+
+	err = s.mailer.Send(r.Context(), mail.Message{
+		To:      oldUser.Email,
+		Subject: "Your email address was changed",
+		Body: fmt.Sprintf("Hi %s,\n\nThe email address of your account was changed to %s.\nIf you did not make this change, please contact support immediately.\n",
+			oldUser.FirstName, updatedUser.Email),
+	})
+	if err != nil {
+		slog.Error("Failed to notify previous email address", "error", err, "userID", updatedUser.ID)
+	}
+
+	respondWithJSON(w, http.StatusOK, ToUserResponseDTO(updatedUser))
+}
+
+// handleForgotPassword emails a password reset link.
+// @Summary Request password reset
+// @Description Emails a one-time password reset link if an account exists for the address.
+// @Description The response is the same whether or not the account exists.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body ForgotPasswordRequest true "Account email"
+// @Success 202 {object} map[string]string "message: If an account exists for this email, a reset link has been sent"
+// @Failure 400 {object} ErrorResponse "Invalid request payload or email empty"
+// @Router /auth/password/forgot [post]
+func (s *Server) handleForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	if req.Email == "" {
+		respondWithError(w, http.StatusBadRequest, "Email cannot be empty")
+		return
+	}
+
+	user, err := s.db.GetUserByEmail(r.Context(), req.Email)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) && !errors.Is(err, pgx.ErrNoRows) {
+			slog.Error("Failed to fetch user for password reset", "error", err)
+		}
+	} else if user.DeletedAt.Valid {
+		slog.Info("Skipping password reset for account scheduled for deletion", "userID", user.ID)
+	} else if err := s.sendPasswordResetEmail(r.Context(), user); err != nil {
+		slog.Error("Failed to send password reset email", "error", err, "userID", user.ID)
+	}
+
+	respondWithJSON(w, http.StatusAccepted, map[string]string{"message": "If an account exists for this email, a reset link has been sent"})
+}
+
+// handleResetPassword sets a new password using a reset token.
+// @Summary Reset password
+// @Description Redeems a password reset token and sets a new password. The new password must satisfy the password policy;
+// @Description a rejected password does not use up the token. All sessions of the user are revoked.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param reset body ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} map[string]string "message: Password has been reset"
+// @Failure 400 {object} ErrorResponse "Invalid request payload, new password empty or token invalid, expired or already used"
+// @Failure 404 {object} ErrorResponse "User not found"
+// @Failure 422 {object} PasswordPolicyErrorResponse "New password does not meet the password policy"
+// @Failure 500 {object} ErrorResponse "Failed to reset password"
+// @Router /auth/password/reset [post]
+func (s *Server) handleResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	if req.NewPassword == "" {
+		respondWithError(w, http.StatusBadRequest, "New password cannot be empty")
+		return
+	}
+
+	if req.Token == "" {
+		respondWithError(w, http.StatusBadRequest, "Token is required")
+		return
+	}
+	pending, err := s.db.GetActiveUserToken(r.Context(), db.GetActiveUserTokenParams{
+		TokenHash: hashToken(req.Token),
+		Purpose:   db.UserTokenPurposePasswordReset,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			respondWithError(w, http.StatusBadRequest, "Token is invalid, expired or has already been used")
+			return
+		}
+		slog.Error("Failed to look up password reset token", "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to reset password")
+		return
+	}
+	user, err := s.db.GetUserByID(r.Context(), pending.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "User not found")
+			return
+		}
+		slog.Error("Failed to fetch user for password reset", "error", err, "userID", pending.UserID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to reset password")
+		return
+	}
+	if !checkPasswordPolicy(w, req.NewPassword, password.UserInfo{Email: user.Email, FirstName: user.FirstName, LastName: user.LastName}) {
+		return
+	}
+
+	userToken, ok := s.consumeUserToken(w, r, req.Token, db.UserTokenPurposePasswordReset)
+	if !ok {
+		return
+	}
+
+	newHashedPassword, err := hashPassword(req.NewPassword)
+	if err != nil {
+		slog.Error("Failed to hash new password", "error", err)
+		respondWithError(w, http.StatusInternalServerError, "Failed to reset password")
+		return
+	}
+
+	_, err = s.db.UpdateUserPassword(r.Context(), db.UpdateUserPasswordParams{
+		ID:           userToken.UserID,
+		PasswordHash: newHashedPassword,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "User not found")
+			return
+		}
+		slog.Error("Failed to reset user password", "error", err, "userID", userToken.UserID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to reset password")
+		return
+	}
+
+	// Receiving the reset link proves ownership of the address.
+	if _, err := s.db.MarkUserEmailVerified(r.Context(), userToken.UserID); err != nil {
+		slog.Error("Failed to mark email verified after password reset", "error", err, "userID", userToken.UserID)
+	}
+	if err := s.db.RevokeUserSessions(r.Context(), userToken.UserID); err != nil {
+		slog.Error("Failed to revoke user sessions after password reset", "error", err, "userID", userToken.UserID)
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]string{"message": "Password has been reset"})
+}
@@ -0,0 +1,645 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"unicode/utf8"
+
+	"github.com/dukunuu/hackathon_backend/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	maxVolunteerNotesLength = 1000
+
+	// volunteerCapacityConstraint keeps a post's approved volunteers within
+	// max_volunteers; see migration 000018.
+	volunteerCapacityConstraint = "chk_posts_volunteer_capacity"
+
+	errNoVolunteerPlaces              = "The post has no free volunteer places"
+	errVolunteerCapacityBelowApproved = "Volunteers were approved meanwhile; max_volunteers cannot be lower than the approved volunteers"
+	errNotVolunteerOrganizer          = "Only the author of the post can manage its volunteers"
+)
+
+// ApplyVolunteerRequest is the optional body of a volunteer application.
+// swagger:model ApplyVolunteerRequest
+type ApplyVolunteerRequest struct {
+	Notes string `json:"notes,omitempty" example:"I can bring gloves and bags."`
+}
+
+// PostVolunteerEntryDTO is a volunteer application with the volunteer's
+// profile, as the post's organizer sees it. Phone and email are only set for
+// approved volunteers whose privacy settings show them to organizers.
+// swagger:model PostVolunteerEntryDTO
+type PostVolunteerEntryDTO struct {
+	PostVolunteerDTO
+	FirstName  string  `json:"first_name" example:"John"`
+	LastName   string  `json:"last_name" example:"Doe"`
+	ProfileUrl *string `json:"profile_url,omitempty" example:"http://example.com/profile.jpg"`
+	Phone      *string `json:"phone,omitempty" example:"99119911"`
+	Email      *string `json:"email,omitempty" example:"john.doe@example.com"`
+}
+
+// VolunteerCountsDTO counts a post's volunteer applications by status.
+// swagger:model VolunteerCountsDTO
+type VolunteerCountsDTO struct {
+	Pending   int64 `json:"pending" example:"2"`
+	Approved  int64 `json:"approved" example:"3"`
+	Completed int64 `json:"completed" example:"1"`
+	Rejected  int64 `json:"rejected" example:"0"`
+	Withdrawn int64 `json:"withdrawn" example:"1"`
+	NoShow    int64 `json:"no_show" example:"0"`
+	Total     int64 `json:"total" example:"7"`
+}
+
+// PostVolunteersDTO is the volunteer listing of a post. Items and NextCursor
+// are only set for the post's organizer.
+// swagger:model PostVolunteersDTO
+type PostVolunteersDTO struct {
+	Counts     VolunteerCountsDTO      `json:"counts"`
+	Items      []PostVolunteerEntryDTO `json:"items,omitempty"`
+	NextCursor string                  `json:"next_cursor,omitempty"`
+}
+
+// VolunteeringDTO is one of the caller's volunteer applications with the post
+// and its organizer.
+// swagger:model VolunteeringDTO
+type VolunteeringDTO struct {
+	PostVolunteerDTO
+	PostTitle          string     `json:"post_title" example:"Clean up the park"`
+	PostStatus         string     `json:"post_status" example:"Хүлээгдэж байгаа"`
+	PostPreviewUrl     *string    `json:"post_preview_url,omitempty"`
+	OrganizerID        *uuid.UUID `json:"organizer_id,omitempty" format:"uuid"`
+	OrganizerFirstName string     `json:"organizer_first_name,omitempty" example:"Jane"`
+	OrganizerLastName  string     `json:"organizer_last_name,omitempty" example:"Doe"`
+}
+
+func toPostVolunteerDTO(v db.PostVolunteer) PostVolunteerDTO {
+	return PostVolunteerDTO{
+		ID:        v.ID.Bytes,
+		UserID:    v.UserID.Bytes,
+		PostID:    v.PostID.Bytes,
+		Status:    string(v.Status),
+		Notes:     v.Notes.String,
+		CreatedAt: v.CreatedAt.Time,
+		UpdatedAt: v.UpdatedAt.Time,
+	}
+}
+
+func isValidVolunteerStatus(status db.VolunteerStatus) bool {
+	switch status {
+	case db.VolunteerStatusPending, db.VolunteerStatusApproved, db.VolunteerStatusCompleted,
+		db.VolunteerStatusRejected, db.VolunteerStatusWithdrawn, db.VolunteerStatusNoShow:
+		return true
+	}
+	return false
+}
+
+// parseVolunteerStatusParam reads the optional 'status' filter of volunteer
+// listings. The error message is meant for a 400 response.
+func parseVolunteerStatusParam(r *http.Request) (db.NullVolunteerStatus, error) {
+	v := r.URL.Query().Get("status")
+	if v == "" {
+		return db.NullVolunteerStatus{}, nil
+	}
+	if !isValidVolunteerStatus(db.VolunteerStatus(v)) {
+		return db.NullVolunteerStatus{}, fmt.Errorf("status must be pending, approved, completed, rejected, withdrawn or no_show")
+	}
+	return db.NullVolunteerStatus{VolunteerStatus: db.VolunteerStatus(v), Valid: true}, nil
+}
+
+// isVolunteerCapacityViolation reports whether err is a write that would
+// have put more approved volunteers on a post than max_volunteers allows.
+func isVolunteerCapacityViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23514" && pgErr.ConstraintName == volunteerCapacityConstraint
+}
+
+// organizerPostFromPath loads the post named in the path and checks that the
+// caller is its author, answering forbidden otherwise. On failure it writes
+// the error response and reports false.
+func (s *Server) organizerPostFromPath(w http.ResponseWriter, r *http.Request, forbidden string) (db.GetPostRow, bool) {
+	authUserID, err := getUserIDFromContext(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return db.GetPostRow{}, false
+	}
+
+	postID, err := uuid.Parse(r.PathValue("postId"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid post ID format")
+		return db.GetPostRow{}, false
+	}
+
+	post, err := s.db.GetPost(r.Context(), toPgtypeUUID(postID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Post not found")
+			return db.GetPostRow{}, false
+		}
+		slog.Error("Failed to get post for organizer check", "error", err, "postID", postID)
+		respondWithError(w, http.StatusInternalServerError, "Could not verify post ownership")
+		return db.GetPostRow{}, false
+	}
+
+	if !post.UserID.Valid || post.UserID.Bytes != authUserID.Bytes {
+		respondWithError(w, http.StatusForbidden, forbidden)
+		return db.GetPostRow{}, false
+	}
+	return post, true
+}
+
+// endVolunteering moves a volunteer to a final status with transition and
+// frees the shift places they signed up for but never checked in to.
+func (s *Server) endVolunteering(ctx context.Context, transition func(*db.Queries) (db.PostVolunteer, error)) (db.PostVolunteer, error) {
+	var volunteer db.PostVolunteer
+	err := s.db.ExecTx(ctx, func(q *db.Queries) error {
+		var err error
+		if volunteer, err = transition(q); err != nil {
+			return err
+		}
+		_, err = q.DeleteOpenShiftSignups(ctx, volunteer.ID)
+		return err
+	})
+	return volunteer, err
+}
+
+// respondVolunteerTransitionRefused explains why a status change matched no
+// application: either there is none, or it is in a status the change does
+// not apply to.
+func (s *Server) respondVolunteerTransitionRefused(w http.ResponseWriter, r *http.Request, postID, userID pgtype.UUID, action string) {
+	volunteer, err := s.db.GetPostVolunteer(r.Context(), db.GetPostVolunteerParams{PostID: postID, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Volunteer application not found")
+			return
+		}
+		slog.Error("Failed to get volunteer application", "error", err, "postID", postID, "userID", userID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to "+action+" volunteer")
+		return
+	}
+	respondWithError(w, http.StatusConflict, fmt.Sprintf("Cannot %s a volunteer whose application is %s", action, volunteer.Status))
+}
+
+// volunteerUserFromPath parses the volunteer's user ID from the path. On
+// failure it writes the error response and reports false.
+func volunteerUserFromPath(w http.ResponseWriter, r *http.Request) (pgtype.UUID, bool) {
+	userID, err := uuid.Parse(r.PathValue("userId"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid volunteer user ID format")
+		return pgtype.UUID{}, false
+	}
+	return toPgtypeUUID(userID), true
+}
+
+// handleApplyToPost applies the caller as a volunteer on a post.
+// @Summary Volunteer on a post
+// @Description Creates a pending volunteer application for the caller, which the post's author can then approve or reject.
+// @Description Only posts whose category takes volunteers, that are pending or in progress and that have a free place
+// @Description accept applications. Authors cannot volunteer on their own posts.
+// @Tags Posts, Volunteers
+// @Accept json
+// @Produce json
+// @Param postId path string true "Post ID" format(uuid)
+// @Param application body ApplyVolunteerRequest false "Optional notes for the organizer"
+// @Success 201 {object} PostVolunteerDTO "Application created"
+// @Failure 400 {object} ErrorResponse "Invalid post ID format or request payload"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Email not verified, or the caller is the author of the post"
+// @Failure 404 {object} ErrorResponse "Post not found"
+// @Failure 409 {object} ErrorResponse "Already applied, post does not take volunteers, or no free places"
+// @Failure 500 {object} ErrorResponse "Failed to apply"
+// @Security BearerAuth
+// @Router /posts/{postId}/volunteers [post]
+func (s *Server) handleApplyToPost(w http.ResponseWriter, r *http.Request) {
+	authUserID, err := getUserIDFromContext(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	postID, err := uuid.Parse(r.PathValue("postId"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid post ID format")
+		return
+	}
+
+	var req ApplyVolunteerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload: "+err.Error())
+		return
+	}
+	defer r.Body.Close()
+	if utf8.RuneCountInString(req.Notes) > maxVolunteerNotesLength {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("notes must be at most %d characters", maxVolunteerNotesLength))
+		return
+	}
+
+	post, err := s.db.GetPost(r.Context(), toPgtypeUUID(postID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Post not found")
+			return
+		}
+		slog.Error("Failed to get post for volunteer application", "error", err, "postID", postID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to apply")
+		return
+	}
+	if post.UserID.Valid && post.UserID.Bytes == authUserID.Bytes {
+		respondWithError(w, http.StatusForbidden, "You cannot volunteer on your own post")
+		return
+	}
+	if post.Status != postStatusPending && post.Status != postStatusInProgress {
+		respondWithError(w, http.StatusConflict, "The post is no longer taking volunteers")
+		return
+	}
+	if post.CurrentVolunteers >= post.MaxVolunteers {
+		respondWithError(w, http.StatusConflict, errNoVolunteerPlaces)
+		return
+	}
+
+	var notes pgtype.Text
+	if req.Notes != "" {
+		notes = pgtype.Text{String: req.Notes, Valid: true}
+	}
+	application, err := s.db.CreatePostVolunteer(r.Context(), db.CreatePostVolunteerParams{
+		UserID: authUserID,
+		Notes:  notes,
+		PostID: post.ID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusConflict, "Posts in this category do not take volunteers")
+			return
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // Unique violation
+			respondWithError(w, http.StatusConflict, "You have already applied to this post")
+			return
+		}
+		slog.Error("Failed to create volunteer application", "error", err, "postID", postID, "userID", authUserID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to apply")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, toPostVolunteerDTO(application))
+}
+
+// handleWithdrawFromPost lets the caller leave a post they volunteered on.
+// @Summary Withdraw from a post
+// @Description Withdraws the caller's pending or approved application. An approved volunteer's place on the post and
+// @Description their shift places that were not checked in to are freed.
+// @Tags Posts, Volunteers
+// @Produce json
+// @Param postId path string true "Post ID" format(uuid)
+// @Success 200 {object} PostVolunteerDTO "Application withdrawn"
+// @Failure 400 {object} ErrorResponse "Invalid post ID format"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 404 {object} ErrorResponse "Volunteer application not found"
+// @Failure 409 {object} ErrorResponse "The application is no longer pending or approved"
+// @Failure 500 {object} ErrorResponse "Failed to withdraw"
+// @Security BearerAuth
+// @Router /posts/{postId}/volunteers/withdraw [post]
+func (s *Server) handleWithdrawFromPost(w http.ResponseWriter, r *http.Request) {
+	authUserID, err := getUserIDFromContext(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	postID, err := uuid.Parse(r.PathValue("postId"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid post ID format")
+		return
+	}
+
+	params := db.WithdrawPostVolunteerParams{PostID: toPgtypeUUID(postID), UserID: authUserID}
+	volunteer, err := s.endVolunteering(r.Context(), func(q *db.Queries) (db.PostVolunteer, error) {
+		return q.WithdrawPostVolunteer(r.Context(), params)
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+			s.respondVolunteerTransitionRefused(w, r, params.PostID, params.UserID, "withdraw")
+			return
+		}
+		slog.Error("Failed to withdraw volunteer", "error", err, "postID", postID, "userID", authUserID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to withdraw")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, toPostVolunteerDTO(volunteer))
+}
+
+// handleCompleteVolunteer marks an approved volunteer's work on a post done.
+// @Summary Complete a volunteer
+// @Description Marks an approved volunteer completed. Volunteers are also completed automatically when they check out
+// @Description of their last shift. Shift places they did not check in to are freed. Only the author of the post can complete volunteers.
+// @Tags Posts, Volunteers
+// @Produce json
+// @Param postId path string true "Post ID" format(uuid)
+// @Param userId path string true "Volunteer's user ID" format(uuid)
+// @Success 200 {object} PostVolunteerDTO "Volunteer completed"
+// @Failure 400 {object} ErrorResponse "Invalid post or user ID format"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Not the author of the post"
+// @Failure 404 {object} ErrorResponse "Post or volunteer application not found"
+// @Failure 409 {object} ErrorResponse "The volunteer is not approved"
+// @Failure 500 {object} ErrorResponse "Failed to complete volunteer"
+// @Security BearerAuth
+// @Router /posts/{postId}/volunteers/{userId}/complete [post]
+func (s *Server) handleCompleteVolunteer(w http.ResponseWriter, r *http.Request) {
+	post, ok := s.organizerPostFromPath(w, r, errNotVolunteerOrganizer)
+	if !ok {
+		return
+	}
+	userID, ok := volunteerUserFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	params := db.CompletePostVolunteerParams{PostID: post.ID, UserID: userID}
+	volunteer, err := s.endVolunteering(r.Context(), func(q *db.Queries) (db.PostVolunteer, error) {
+		return q.CompletePostVolunteer(r.Context(), params)
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+			s.respondVolunteerTransitionRefused(w, r, post.ID, userID, "complete")
+			return
+		}
+		slog.Error("Failed to complete volunteer", "error", err, "postID", post.ID, "volunteerID", userID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to complete volunteer")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, toPostVolunteerDTO(volunteer))
+}
+
+// handleMarkVolunteerNoShow records that an approved volunteer did not turn up.
+// @Summary Mark a volunteer as no-show
+// @Description Marks an approved volunteer who did not turn up as no_show, which frees their place on the post and
+// @Description their shift places that were not checked in to. Only the author of the post can mark no-shows.
+// @Tags Posts, Volunteers
+// @Produce json
+// @Param postId path string true "Post ID" format(uuid)
+// @Param userId path string true "Volunteer's user ID" format(uuid)
+// @Success 200 {object} PostVolunteerDTO "Volunteer marked as no-show"
+// @Failure 400 {object} ErrorResponse "Invalid post or user ID format"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 403 {object} ErrorResponse "Not the author of the post"
+// @Failure 404 {object} ErrorResponse "Post or volunteer application not found"
+// @Failure 409 {object} ErrorResponse "The volunteer is not approved"
+// @Failure 500 {object} ErrorResponse "Failed to mark volunteer as no-show"
+// @Security BearerAuth
+// @Router /posts/{postId}/volunteers/{userId}/no-show [post]
+func (s *Server) handleMarkVolunteerNoShow(w http.ResponseWriter, r *http.Request) {
+	post, ok := s.organizerPostFromPath(w, r, errNotVolunteerOrganizer)
+	if !ok {
+		return
+	}
+	userID, ok := volunteerUserFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	params := db.MarkPostVolunteerNoShowParams{PostID: post.ID, UserID: userID}
+	volunteer, err := s.endVolunteering(r.Context(), func(q *db.Queries) (db.PostVolunteer, error) {
+		return q.MarkPostVolunteerNoShow(r.Context(), params)
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+			s.respondVolunteerTransitionRefused(w, r, post.ID, userID, "mark as no-show")
+			return
+		}
+		slog.Error("Failed to mark volunteer as no-show", "error", err, "postID", post.ID, "volunteerID", userID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to mark volunteer as no-show")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, toPostVolunteerDTO(volunteer))
+}
+
+// handleListPostVolunteers lists the volunteers of a post.
+// @Summary List post volunteers
+// @Description Counts the post's volunteer applications by status. The post's organizer also gets the applications
+// @Description with the volunteers' profiles, one page at a time; phone and email are shown for approved volunteers
+// @Description whose privacy settings let organizers see them. Follow 'next_cursor' until it is omitted to read every page.
+// @Tags Posts, Volunteers
+// @Produce json
+// @Param postId path string true "Post ID" format(uuid)
+// @Param status query string false "Only applications with this status" Enums(pending, approved, completed, rejected, withdrawn, no_show)
+// @Param sort query string false "created_at (oldest first) or -created_at (newest first, default)" Enums(created_at, -created_at)
+// @Param limit query int false "Page size, 1-100 (default 20)"
+// @Param cursor query string false "next_cursor from the previous page"
+// @Success 200 {object} PostVolunteersDTO "Counts, and for the organizer one page of volunteers"
+// @Failure 400 {object} ErrorResponse "Invalid post ID, filter or cursor"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 404 {object} ErrorResponse "Post not found"
+// @Failure 500 {object} ErrorResponse "Failed to retrieve post volunteers"
+// @Security BearerAuth
+// @Router /posts/{postId}/volunteers [get]
+func (s *Server) handleListPostVolunteers(w http.ResponseWriter, r *http.Request) {
+	authUserID, err := getUserIDFromContext(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	postID, err := uuid.Parse(r.PathValue("postId"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid post ID format")
+		return
+	}
+	page, err := parsePageRequest(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	status, err := parseVolunteerStatusParam(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	post, err := s.db.GetPost(r.Context(), toPgtypeUUID(postID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Post not found")
+			return
+		}
+		slog.Error("Failed to get post for volunteer listing", "error", err, "postID", postID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve post volunteers")
+		return
+	}
+
+	counts, err := s.db.CountPostVolunteersByStatus(r.Context(), post.ID)
+	if err != nil {
+		slog.Error("Failed to count post volunteers", "error", err, "postID", postID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve post volunteers")
+		return
+	}
+	var resp PostVolunteersDTO
+	for _, c := range counts {
+		switch c.Status {
+		case db.VolunteerStatusPending:
+			resp.Counts.Pending = c.Count
+		case db.VolunteerStatusApproved:
+			resp.Counts.Approved = c.Count
+		case db.VolunteerStatusCompleted:
+			resp.Counts.Completed = c.Count
+		case db.VolunteerStatusRejected:
+			resp.Counts.Rejected = c.Count
+		case db.VolunteerStatusWithdrawn:
+			resp.Counts.Withdrawn = c.Count
+		case db.VolunteerStatusNoShow:
+			resp.Counts.NoShow = c.Count
+		}
+		resp.Counts.Total += c.Count
+	}
+
+	if !post.UserID.Valid || post.UserID.Bytes != authUserID.Bytes {
+		respondWithJSON(w, http.StatusOK, resp)
+		return
+	}
+
+	rows, err := s.db.ListPostVolunteerPage(r.Context(), db.ListPostVolunteerPageParams{
+		PostID:          post.ID,
+		Status:          status,
+		SortAsc:         page.SortAsc,
+		CursorCreatedAt: page.cursorCreatedAt(),
+		CursorID:        page.cursorID(),
+		PageSize:        page.fetchSize(),
+	})
+	if err != nil {
+		slog.Error("Failed to list post volunteers", "error", err, "postID", postID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve post volunteers")
+		return
+	}
+	rows, resp.NextCursor = trimPage(page, rows, func(row db.ListPostVolunteerPageRow) (pgtype.Timestamptz, pgtype.UUID) {
+		return row.CreatedAt, row.ID
+	})
+
+	resp.Items = make([]PostVolunteerEntryDTO, len(rows))
+	for i, row := range rows {
+		entry := PostVolunteerEntryDTO{
+			PostVolunteerDTO: toPostVolunteerDTO(db.PostVolunteer{
+				ID:        row.ID,
+				UserID:    row.UserID,
+				PostID:    row.PostID,
+				Status:    row.Status,
+				Notes:     row.Notes,
+				CreatedAt: row.CreatedAt,
+				UpdatedAt: row.UpdatedAt,
+			}),
+			FirstName: row.FirstName,
+			LastName:  row.LastName,
+		}
+		if row.ProfileUrl.Valid {
+			entry.ProfileUrl = &row.ProfileUrl.String
+		}
+		if row.Status == db.VolunteerStatusApproved {
+			phoneVisibility, emailVisibility := defaultPhoneVisibility, defaultEmailVisibility
+			if row.PhoneVisibility.Valid {
+				phoneVisibility = row.PhoneVisibility.ProfileFieldVisibility
+			}
+			if row.EmailVisibility.Valid {
+				emailVisibility = row.EmailVisibility.ProfileFieldVisibility
+			}
+			if row.Phone.Valid && fieldVisible(phoneVisibility, true) {
+				entry.Phone = &row.Phone.String
+			}
+			if fieldVisible(emailVisibility, true) {
+				entry.Email = &row.Email
+			}
+		}
+		resp.Items[i] = entry
+	}
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// handleListMyVolunteering lists the posts the caller volunteered on.
+// @Summary List my volunteering
+// @Description Lists the caller's volunteer applications with their posts and organizers, one page at a time.
+// @Description Follow 'next_cursor' until it is omitted to read every page.
+// @Tags Users, Volunteers
+// @Produce json
+// @Param status query string false "Only applications with this status" Enums(pending, approved, completed, rejected, withdrawn, no_show)
+// @Param sort query string false "created_at (oldest first) or -created_at (newest first, default)" Enums(created_at, -created_at)
+// @Param limit query int false "Page size, 1-100 (default 20)"
+// @Param cursor query string false "next_cursor from the previous page"
+// @Success 200 {object} PageDTO[VolunteeringDTO] "One page of applications"
+// @Failure 400 {object} ErrorResponse "Invalid filter or cursor"
+// @Failure 401 {object} ErrorResponse "Authentication required"
+// @Failure 500 {object} ErrorResponse "Failed to retrieve volunteering"
+// @Security BearerAuth
+// @Router /users/me/volunteering [get]
+func (s *Server) handleListMyVolunteering(w http.ResponseWriter, r *http.Request) {
+	authUserID, err := getUserIDFromContext(r.Context())
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	page, err := parsePageRequest(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	status, err := parseVolunteerStatusParam(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rows, err := s.db.ListUserVolunteeringPage(r.Context(), db.ListUserVolunteeringPageParams{
+		UserID:          authUserID,
+		Status:          status,
+		SortAsc:         page.SortAsc,
+		CursorCreatedAt: page.cursorCreatedAt(),
+		CursorID:        page.cursorID(),
+		PageSize:        page.fetchSize(),
+	})
+	if err != nil {
+		slog.Error("Failed to list volunteering", "error", err, "userID", authUserID)
+		respondWithError(w, http.StatusInternalServerError, "Failed to retrieve volunteering")
+		return
+	}
+	rows, nextCursor := trimPage(page, rows, func(row db.ListUserVolunteeringPageRow) (pgtype.Timestamptz, pgtype.UUID) {
+		return row.CreatedAt, row.ID
+	})
+
+	items := make([]VolunteeringDTO, len(rows))
+	for i, row := range rows {
+		item := VolunteeringDTO{
+			PostVolunteerDTO: toPostVolunteerDTO(db.PostVolunteer{
+				ID:        row.ID,
+				UserID:    row.UserID,
+				PostID:    row.PostID,
+				Status:    row.Status,
+				Notes:     row.Notes,
+				CreatedAt: row.CreatedAt,
+				UpdatedAt: row.UpdatedAt,
+			}),
+			PostTitle:          row.PostTitle,
+			PostStatus:         string(row.PostStatus),
+			OrganizerFirstName: row.OrganizerFirstName.String,
+			OrganizerLastName:  row.OrganizerLastName.String,
+		}
+		if row.PostPreviewUrl.Valid {
+			item.PostPreviewUrl = &row.PostPreviewUrl.String
+		}
+		if row.OrganizerID.Valid {
+			organizerID := uuid.UUID(row.OrganizerID.Bytes)
+			item.OrganizerID = &organizerID
+		}
+		items[i] = item
+	}
+	respondWithJSON(w, http.StatusOK, PageDTO[VolunteeringDTO]{Items: items, NextCursor: nextCursor})
+}
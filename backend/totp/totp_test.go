@@ -0,0 +1,125 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 key of the RFC 6238 appendix B test vectors,
+// "12345678901234567890", base32 encoded.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCodeRFC6238Vectors(t *testing.T) {
+	// The RFC lists 8-digit codes; a 6-digit code is their last 6 digits.
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+		{unix: 20000000000, want: "353130"},
+	}
+
+	for _, tt := range tests {
+		got, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code(T=%d): %v", tt.unix, err)
+		}
+		if got != tt.want {
+			t.Errorf("Code(T=%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestCodeNormalizesSecret(t *testing.T) {
+	spaced := strings.ToLower(rfcSecret[:16]) + " " + rfcSecret[16:]
+	got, err := Code(spaced, 1)
+	if err != nil {
+		t.Fatalf("Code: %v", err)
+	}
+	if got != "287082" {
+		t.Errorf("Code = %s, want 287082", got)
+	}
+}
+
+func TestCodeInvalidSecret(t *testing.T) {
+	if _, err := Code("not base32!", 1); err == nil {
+		t.Error("Code with an invalid secret returned no error")
+	}
+}
+
+func TestValidateSkewWindow(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := Step(now)
+
+	tests := []struct {
+		name   string
+		offset int64
+		ok     bool
+	}{
+		{name: "two periods early", offset: -2, ok: false},
+		{name: "one period early", offset: -1, ok: true},
+		{name: "current period", offset: 0, ok: true},
+		{name: "one period late", offset: 1, ok: true},
+		{name: "two periods late", offset: 2, ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := Code(rfcSecret, current+tt.offset)
+			if err != nil {
+				t.Fatalf("Code: %v", err)
+			}
+			step, ok := Validate(rfcSecret, code, now)
+			if ok != tt.ok {
+				t.Fatalf("Validate ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && step != current+tt.offset {
+				t.Errorf("Validate step = %d, want %d", step, current+tt.offset)
+			}
+		})
+	}
+}
+
+func TestValidateInput(t *testing.T) {
+	now := time.Unix(59, 0)
+
+	tests := []struct {
+		name string
+		code string
+		ok   bool
+	}{
+		{name: "exact", code: "287082", ok: true},
+		{name: "surrounding and inner spaces", code: " 287 082 ", ok: true},
+		{name: "wrong code", code: "287083", ok: false},
+		{name: "too short", code: "28708", ok: false},
+		{name: "eight digit RFC code", code: "94287082", ok: false},
+		{name: "empty", code: "", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := Validate(rfcSecret, tt.code, now); ok != tt.ok {
+				t.Errorf("Validate(%q) ok = %v, want %v", tt.code, ok, tt.ok)
+			}
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret: %v", err)
+	}
+	key, err := encoding.DecodeString(secret)
+	if err != nil {
+		t.Fatalf("secret is not base32: %v", err)
+	}
+	if len(key) != secretSize {
+		t.Errorf("secret decodes to %d bytes, want %d", len(key), secretSize)
+	}
+}